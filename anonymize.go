@@ -0,0 +1,86 @@
+package presence
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Anonymize rewrites the fields of the struct pointed to by v named in rules with the
+// result of their rule, to export production-shaped datasets without personal data:
+//
+//	err := presence.Anonymize(&user, map[string]func(any) any{
+//		"email": func(v any) any { return fmt.Sprintf("user%x@example.com", xxhash.Sum64String(v.(string))) },
+//		"name":  func(any) any { return "Jane Doe" },
+//	})
+//
+// Only values are rewritten: null and unset presence fields, and nil pointers, keep
+// their state. A rule returning nil sets a presence field null and zeroes a plain
+// field. Rule names matching no field are reported, so that a typo does not leave a
+// field unanonymized.
+func Anonymize(v any, rules map[string]func(any) any, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence anonymize target must be a non-nil struct pointer, got %T", v)
+	}
+
+	rv = rv.Elem()
+	seen := make(map[string]bool, len(rules))
+
+	var err error
+
+	walkFields(rv.Type(), nil, newOptions(opts), func(name string, index []int, isPresence bool) {
+		rule, ok := rules[name]
+		if !ok || seen[name] || err != nil {
+			return
+		}
+
+		seen[name] = true
+		err = anonymizeField(rv.FieldByIndex(index), isPresence, rule)
+		if err != nil {
+			err = fmt.Errorf("presence anonymizing %s : %w", name, err)
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(rules)) {
+		if !seen[name] {
+			return fmt.Errorf("presence: no field %s in %s", name, rv.Type())
+		}
+	}
+
+	return nil
+}
+
+// anonymizeField rewrites the value of the struct field f with rule.
+func anonymizeField(f reflect.Value, isPresence bool, rule func(any) any) error {
+	if isPresence {
+		pf := f.Addr().Interface().(presenceField)
+		if pf.State() != StateValue {
+			return nil
+		}
+
+		return pf.setAny(rule(pf.anyValue()))
+	}
+
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return nil
+		}
+
+		f = f.Elem()
+	}
+
+	v := rule(f.Interface())
+	if v == nil {
+		f.SetZero()
+
+		return nil
+	}
+
+	return assign(f, v)
+}
@@ -0,0 +1,144 @@
+package presence
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Builder builds a struct of type T field by field, for tests and for PATCH payloads
+// assembled at runtime, e.g. by rule engines:
+//
+//	patch, err := presence.Build[UserPatch]().Set("Email", "a@b.c").Null("Bio").Struct()
+//
+// Fields are named by their Go name or by their name as ToMap gives it. The first
+// error, such as an unknown field or a value of the wrong type, is reported by Struct.
+type Builder[T any] struct {
+	value  reflect.Value
+	fields map[string][]int
+	err    error
+}
+
+// Build returns a Builder of the struct type T, starting from its zero value.
+func Build[T any](opts ...Option) *Builder[T] {
+	b := &Builder[T]{value: reflect.New(reflect.TypeFor[T]()).Elem()}
+	if b.value.Kind() != reflect.Struct {
+		b.err = fmt.Errorf("presence cannot build %s, which is not a struct", b.value.Type())
+
+		return b
+	}
+
+	b.fields = map[string][]int{}
+	walkFields(b.value.Type(), nil, newOptions(opts), func(name string, index []int, _ bool) {
+		b.fields[name] = index
+		b.fields[b.value.Type().FieldByIndex(index).Name] = index
+	})
+
+	return b
+}
+
+// Set sets the field to v, which must be assignable or convertible to its type.
+// A nil v sets a presence field to null, like Null.
+func (b *Builder[T]) Set(field string, v any) *Builder[T] {
+	return b.apply(field, func(dst reflect.Value) error {
+		if pf, ok := dst.Addr().Interface().(presenceField); ok {
+			return pf.setAny(v)
+		}
+
+		if v == nil {
+			return nullField(dst)
+		}
+
+		if dst.Kind() == reflect.Pointer && !reflect.TypeOf(v).AssignableTo(dst.Type()) {
+			ptr := reflect.New(dst.Type().Elem())
+			err := assign(ptr.Elem(), v)
+			if err != nil {
+				return err
+			}
+
+			dst.Set(ptr)
+
+			return nil
+		}
+
+		return assign(dst, v)
+	})
+}
+
+// Null sets the field to null: presence fields become null and pointers nil, other
+// fields report ErrNullNotAllowed.
+func (b *Builder[T]) Null(field string) *Builder[T] {
+	return b.apply(field, nullField)
+}
+
+// Unset resets the presence field to unset.
+func (b *Builder[T]) Unset(field string) *Builder[T] {
+	return b.apply(field, func(dst reflect.Value) error {
+		pf, ok := dst.Addr().Interface().(presenceField)
+		if !ok {
+			return fmt.Errorf("presence cannot unset %s, which is not a presence field", dst.Type())
+		}
+
+		pf.Unset()
+
+		return nil
+	})
+}
+
+// Struct returns the built struct, or the first error met.
+func (b *Builder[T]) Struct() (T, error) {
+	if b.err != nil {
+		var zero T
+
+		return zero, b.err
+	}
+
+	return b.value.Interface().(T), nil
+}
+
+// MustStruct is Struct panicking on error, for tests.
+func (b *Builder[T]) MustStruct() T {
+	v, err := b.Struct()
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// apply calls fn on the field named field unless an error occurred before.
+func (b *Builder[T]) apply(field string, fn func(dst reflect.Value) error) *Builder[T] {
+	if b.err != nil {
+		return b
+	}
+
+	index, ok := b.fields[field]
+	if !ok {
+		b.err = fmt.Errorf("presence building %s : unknown field %s", b.value.Type(), field)
+
+		return b
+	}
+
+	err := fn(b.value.FieldByIndex(index))
+	if err != nil {
+		b.err = fmt.Errorf("presence building field %s : %w", field, err)
+	}
+
+	return b
+}
+
+// nullField sets the field dst to null.
+func nullField(dst reflect.Value) error {
+	if pf, ok := dst.Addr().Interface().(presenceField); ok {
+		pf.SetNull()
+
+		return nil
+	}
+
+	if dst.Kind() != reflect.Pointer {
+		return ErrNullNotAllowed
+	}
+
+	dst.SetZero()
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package presence
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MarshalCanonical returns the canonical JSON encoding of v, identical for equal
+// payloads so that they can be hashed or signed reproducibly. It follows the JSON
+// Canonicalization Scheme (RFC 8785):
+//   - object keys are sorted, including those of the values stored in Of[any] or
+//     Of[map[string]any] and of json.RawMessage values;
+//   - no insignificant whitespace, and strings escaped minimally, without the HTML
+//     escaping of encoding/json;
+//   - numbers with a fraction or an exponent formatted like ECMAScript does, 1.50
+//     and 15e-1 both giving 1.5.
+//
+// Unlike RFC 8785, integers are kept digit for digit instead of being rounded to
+// float64, so that int64 identifiers above 2^53 survive.
+func MarshalCanonical(v any) ([]byte, error) {
+	b, err := marshalDBOnly(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return CanonicalizeJSON(b)
+}
+
+// CanonicalizeJSON rewrites the JSON document data in the canonical form of
+// MarshalCanonical.
+func CanonicalizeJSON(data []byte) ([]byte, error) {
+	doc, err := decodeDocument(data, "canonical decoding")
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeCanonical(doc)
+}
+
+// decodeDocument decodes the single JSON document data, numbers as json.Number.
+// Errors are prefixed with op.
+func decodeDocument(data []byte, op string) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+
+	err := dec.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("presence %s : %w", op, err)
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("presence %s : data after the JSON document", op)
+	}
+
+	return doc, nil
+}
+
+// encodeCanonical encodes the document decoded by decodeDocument in canonical form.
+func encodeCanonical(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := writeCanonical(&buf, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(buf, v)
+	case json.Number:
+		return writeCanonicalNumber(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			err := writeCanonical(buf, elem)
+			if err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		slices.SortFunc(keys, compareUTF16)
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalString(buf, key)
+			buf.WriteByte(':')
+
+			err := writeCanonical(buf, v[key])
+			if err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	}
+
+	return nil
+}
+
+// writeCanonicalNumber keeps integers as they are, minus zero excepted, and formats the
+// other numbers like ECMAScript's Number.prototype.toString.
+func writeCanonicalNumber(buf *bytes.Buffer, n json.Number) error {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if s == "-0" {
+			s = "0"
+		}
+
+		buf.WriteString(s)
+
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) {
+		return fmt.Errorf("presence canonical encoding : number %s out of range", s)
+	}
+
+	if f == 0 {
+		buf.WriteByte('0')
+
+		return nil
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	b := strconv.AppendFloat(nil, f, format, -1, 64)
+	if format == 'e' {
+		// e-07 becomes e-7, as in ECMAScript.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+
+	buf.Write(b)
+
+	return nil
+}
+
+// writeCanonicalString escapes the quotation mark, the reverse solidus and the control
+// characters only, with the short forms when they exist.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xf])
+
+				continue
+			}
+
+			buf.WriteRune(r)
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// compareUTF16 orders strings by their UTF-16 code units, as RFC 8785 sorts keys.
+func compareUTF16(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
@@ -0,0 +1,156 @@
+package presence
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+var (
+	_ driver.Valuer    = CompatNull[int]{}
+	_ sql.Scanner      = (*CompatNull[int])(nil)
+	_ json.Marshaler   = CompatNull[int]{}
+	_ json.Unmarshaler = (*CompatNull[int])(nil)
+)
+
+// CompatNull is a presence value laid out like sql.Null[T], for the libraries (ORMs, CSV
+// mappers…) that reflect on the V and Valid fields of the sql.Null types:
+//
+//	type Row struct {
+//		Age presence.CompatNull[int64] `csv:"age" json:"age,omitzero"`
+//	}
+//
+// Valid reports a value. A CompatNull that is not Valid is null when it was set to null
+// (by Scan, UnmarshalJSON, SetNull or CompatNullOf) and unset otherwise, so that a
+// library zeroing Valid leaves an unset CompatNull unset. It scans, stores and encodes
+// in JSON like Of[T].
+type CompatNull[T any] struct {
+	V     T
+	Valid bool
+	null  bool
+}
+
+// CompatNullOf returns the CompatNull holding the state and value of n.
+func CompatNullOf[T any](n Of[T]) CompatNull[T] {
+	var c CompatNull[T]
+	c.set(n)
+
+	return c
+}
+
+// Of returns the presence value held by c.
+func (c CompatNull[T]) Of() Of[T] {
+	switch c.State() {
+	case StateValue:
+		return FromValue(c.V)
+	case StateNull:
+		return Null[T]()
+	case StateUnset:
+	}
+
+	return Of[T]{}
+}
+
+// State returns the state of c.
+func (c CompatNull[T]) State() State {
+	if c.Valid {
+		return StateValue
+	}
+
+	if c.null {
+		return StateNull
+	}
+
+	return StateUnset
+}
+
+// IsZero reports whether c is unset, for the omitzero JSON option.
+func (c CompatNull[T]) IsZero() bool {
+	return c.State() == StateUnset
+}
+
+// SetValue sets the value of c.
+func (c *CompatNull[T]) SetValue(v T) {
+	c.V, c.Valid, c.null = v, true, false
+}
+
+// SetNull sets c to null.
+func (c *CompatNull[T]) SetNull() {
+	*c = CompatNull[T]{null: true}
+}
+
+// Unset sets c to unset.
+func (c *CompatNull[T]) Unset() {
+	*c = CompatNull[T]{}
+}
+
+// Scan implements the sql.Scanner interface like Of[T].Scan.
+func (c *CompatNull[T]) Scan(v any) error {
+	n := c.Of()
+
+	err := n.Scan(v)
+	if err != nil {
+		return err
+	}
+
+	c.set(n)
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface like Of[T].Value.
+func (c CompatNull[T]) Value() (driver.Value, error) {
+	return c.Of().Value()
+}
+
+// MarshalJSON implements the encoding json interface like Of[T].MarshalJSON.
+func (c CompatNull[T]) MarshalJSON() ([]byte, error) {
+	return c.Of().MarshalJSON()
+}
+
+// UnmarshalJSON implements the decoding json interface like Of[T].UnmarshalJSON.
+func (c *CompatNull[T]) UnmarshalJSON(data []byte) error {
+	n := c.Of()
+
+	err := n.UnmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	c.set(n)
+
+	return nil
+}
+
+// anyValue returns the value boxed in an any, nil when null or unset.
+func (c *CompatNull[T]) anyValue() any {
+	n := c.Of()
+
+	return n.anyValue()
+}
+
+// setAny sets the value from v like Of[T].setAny.
+func (c *CompatNull[T]) setAny(v any) error {
+	var n Of[T]
+
+	err := n.setAny(v)
+	if err != nil {
+		return err
+	}
+
+	c.set(n)
+
+	return nil
+}
+
+// set copies the state and value of n into c.
+func (c *CompatNull[T]) set(n Of[T]) {
+	switch n.State() {
+	case StateValue:
+		c.SetValue(*n.val)
+	case StateNull:
+		c.SetNull()
+	case StateUnset:
+		c.Unset()
+	}
+}
@@ -0,0 +1,346 @@
+package presence
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// compositeTimeLayout is the layout of the times of composite literals, read back by
+// parseTime.
+const compositeTimeLayout = "2006-01-02 15:04:05.999999999Z07:00"
+
+// RegisterComposite stores the values of the struct type T as Postgres composite
+// literals, such as (12,"Main St",), instead of JSON, so that Of[T] persists into columns
+// of a composite type:
+//
+//	// CREATE TYPE address AS (number int, street text, zip text);
+//	type Address struct {
+//		Number int
+//		Street string
+//		Zip    presence.Of[string]
+//	}
+//
+//	presence.RegisterComposite[Address]()
+//	db.Exec("INSERT INTO users (home) VALUES ($1::address)", presence.FromValue(addr))
+//
+// The exported fields of T are the attributes of the type, in their declaration order.
+// Their values are converted like the arguments of database/sql, driver.Valuer included,
+// and null or unset presence fields are stored as NULL attributes. Scan decodes the
+// literals read from such columns or from ROW(...) expressions. Registering a type again
+// has no effect.
+func RegisterComposite[T any]() {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		composites := make(map[reflect.Type]bool, len(d.composites)+1)
+		maps.Copy(composites, d.composites)
+		composites[typ] = true
+		d.composites = composites
+	})
+}
+
+// UnregisterComposite removes the registration of T by RegisterComposite.
+func UnregisterComposite[T any]() {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		composites := maps.Clone(d.composites)
+		delete(composites, typ)
+		d.composites = composites
+	})
+}
+
+// isComposite reports whether T is registered by RegisterComposite.
+func isComposite[T any]() bool {
+	d := loadDefaults()
+
+	return len(d.composites) > 0 && d.composites[reflect.TypeFor[T]()]
+}
+
+// compositeValue encodes the struct v as a composite literal.
+func compositeValue(v any) (driver.Value, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("presence composite : %s is not a struct", rv.Type())
+	}
+
+	var b strings.Builder
+	b.WriteByte('(')
+
+	for i, index := range compositeFields(rv.Type()) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		v, err := compositeDriverValue(rv.Field(index).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("presence composite %s : %w", rv.Type(), err)
+		}
+
+		text, ok := compositeText(v)
+		if ok {
+			writeCompositeElement(&b, text)
+		}
+	}
+
+	b.WriteByte(')')
+
+	return b.String(), nil
+}
+
+// compositeDriverValue converts the attribute v like database/sql, keeping the values
+// of valuers, such as the uuid.UUID of Of[uuid.UUID], for compositeText.
+func compositeDriverValue(v any) (driver.Value, error) {
+	var (
+		value driver.Value
+		err   error
+	)
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err = valuer.Value()
+	} else {
+		value, err = driver.DefaultParameterConverter.ConvertValue(v)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("converting %T : %w", v, err)
+	}
+
+	return value, nil
+}
+
+// compositeFields returns the indexes of the exported fields of the struct type typ.
+func compositeFields(typ reflect.Type) []int {
+	var fields []int
+
+	for i := range typ.NumField() {
+		if typ.Field(i).IsExported() {
+			fields = append(fields, i)
+		}
+	}
+
+	return fields
+}
+
+// compositeText returns the text of the driver value v in a composite literal, false
+// for NULL.
+func compositeText(v driver.Value) (string, bool) {
+	switch value := v.(type) {
+	case nil, defaultValue:
+		return "", false
+	case []byte:
+		return `\x` + hex.EncodeToString(value), true
+	case time.Time:
+		return value.Format(compositeTimeLayout), true
+	case uuid.UUID:
+		return value.String(), true
+	case bool:
+		if value {
+			return "t", true
+		}
+
+		return "f", true
+	case string:
+		return value, true
+	}
+
+	return fmt.Sprint(v), true
+}
+
+// writeCompositeElement writes the element text to b, quoted when needed.
+func writeCompositeElement(b *strings.Builder, text string) {
+	if text != "" && !strings.ContainsAny(text, ",()\"\\ \t\n\r") {
+		b.WriteString(text)
+
+		return
+	}
+
+	b.WriteByte('"')
+
+	for _, r := range text {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	b.WriteByte('"')
+}
+
+// scanComposite decodes the composite literal v into n.
+func (n *Of[T]) scanComposite(v any) error {
+	var literal string
+
+	switch value := v.(type) {
+	case nil:
+		n.handleScanNull()
+
+		return nil
+	case string:
+		literal = value
+	case []byte:
+		literal = string(value)
+	default:
+		return fmt.Errorf("presence composite : cannot scan %T", v)
+	}
+
+	var val T
+
+	err := decodeComposite(literal, reflect.ValueOf(&val).Elem())
+	if err != nil {
+		return err
+	}
+
+	n.setScanned(val)
+
+	return nil
+}
+
+// decodeComposite decodes the composite literal into the struct rv.
+func decodeComposite(literal string, rv reflect.Value) error {
+	elements, err := splitComposite(literal)
+	if err != nil {
+		return err
+	}
+
+	fields := compositeFields(rv.Type())
+	if len(elements) != len(fields) {
+		return fmt.Errorf("presence composite %s : %d attributes for %d fields", rv.Type(), len(elements), len(fields))
+	}
+
+	for i, index := range fields {
+		err := setCompositeField(rv.Field(index), elements[i])
+		if err != nil {
+			return fmt.Errorf("presence composite %s.%s : %w", rv.Type(), rv.Type().Field(index).Name, err)
+		}
+	}
+
+	return nil
+}
+
+// splitComposite returns the elements of the composite literal, nil for NULL.
+func splitComposite(literal string) ([]*string, error) {
+	literal = strings.TrimSpace(literal)
+	if len(literal) < 2 || literal[0] != '(' || literal[len(literal)-1] != ')' {
+		return nil, fmt.Errorf("presence composite : invalid literal %q", literal)
+	}
+
+	var (
+		elements []*string
+		b        strings.Builder
+		quoted   bool
+		inQuotes bool
+	)
+
+	body := literal[1 : len(literal)-1]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case inQuotes && c == '\\' && i+1 < len(body):
+			i++
+			b.WriteByte(body[i])
+		case inQuotes && c == '"' && i+1 < len(body) && body[i+1] == '"':
+			i++
+			b.WriteByte('"')
+		case c == '"':
+			inQuotes, quoted = !inQuotes, true
+		case !inQuotes && c == ',':
+			elements = append(elements, compositeElement(b.String(), quoted))
+			b.Reset()
+			quoted = false
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("presence composite : unterminated quote in %q", literal)
+	}
+
+	return append(elements, compositeElement(b.String(), quoted)), nil
+}
+
+// compositeElement returns the element text, nil for an empty unquoted one, NULL.
+func compositeElement(text string, quoted bool) *string {
+	if text == "" && !quoted {
+		return nil
+	}
+
+	return &text
+}
+
+// setCompositeField sets the field f to the element text, nil for NULL.
+func setCompositeField(f reflect.Value, text *string) error {
+	if scanner, ok := f.Addr().Interface().(sql.Scanner); ok {
+		var v any
+		if text != nil {
+			v = *text
+		}
+
+		err := scanner.Scan(v)
+		if err != nil {
+			return fmt.Errorf("scanning : %w", err)
+		}
+
+		return nil
+	}
+
+	if text == nil {
+		f.SetZero()
+
+		return nil
+	}
+
+	return setCompositeText(f, *text)
+}
+
+// setCompositeText sets the field f, which is not a sql.Scanner, to the text s.
+func setCompositeText(f reflect.Value, s string) error {
+	var err error
+
+	kind := f.Kind()
+	if f.Type() == reflect.TypeFor[time.Time]() {
+		var t time.Time
+		t, err = parseTime(s)
+		f.Set(reflect.ValueOf(t))
+	} else if kind == reflect.String {
+		f.SetString(s)
+	} else if kind == reflect.Bool {
+		var v bool
+		v, err = strconv.ParseBool(s)
+		f.SetBool(v)
+	} else if f.CanInt() {
+		var v int64
+		v, err = strconv.ParseInt(s, 10, f.Type().Bits())
+		f.SetInt(v)
+	} else if f.CanUint() {
+		var v uint64
+		v, err = strconv.ParseUint(s, 10, f.Type().Bits())
+		f.SetUint(v)
+	} else if f.CanFloat() {
+		var v float64
+		v, err = strconv.ParseFloat(s, f.Type().Bits())
+		f.SetFloat(v)
+	} else {
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot parse %q : %w", s, err)
+	}
+
+	return nil
+}
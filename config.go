@@ -1,6 +1,17 @@
 package presence
 
-import "sync"
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // MarshalUnsetBehavior controls how unset values are marshaled to JSON.
 type MarshalUnsetBehavior int
@@ -10,6 +21,10 @@ const (
 	UnsetSkip MarshalUnsetBehavior = iota
 	// UnsetNull marshals unset fields as null.
 	UnsetNull
+	// UnsetError makes MarshalJSON fail with ErrUnsetMarshal for unset values, catching
+	// the required fields of strict producers never populated. Such fields are not
+	// omitted by omitzero.
+	UnsetError
 )
 
 // ScanNullBehavior controls how SQL NULL values are scanned.
@@ -22,38 +37,533 @@ const (
 	ScanNullAsUnset
 )
 
+// ValueUnsetBehavior controls what driver.Valuer returns for unset values.
+type ValueUnsetBehavior int
+
+const (
+	// ValueUnsetNull stores unset values as SQL NULL, like null values.
+	ValueUnsetNull ValueUnsetBehavior = iota
+	// ValueUnsetDefault returns the Default sentinel for unset values, which the
+	// statement builders of the package (Insert.SQL, BuildUpsert, InsertSQL) write as
+	// the SQL DEFAULT keyword. database/sql rejects the sentinel as an argument: bind
+	// the values through these builders, or omit the column.
+	ValueUnsetDefault
+	// ValueUnsetError makes Value() fail with ErrUnsetValue for unset values.
+	ValueUnsetError
+)
+
+// UUIDValueBehavior controls how driver.Valuer encodes uuid.UUID values.
+type UUIDValueBehavior int
+
+const (
+	// UUIDValueString stores UUIDs in their canonical text form.
+	UUIDValueString UUIDValueBehavior = iota
+	// UUIDValueBinary stores UUIDs as 16 raw bytes, for BINARY(16) columns.
+	UUIDValueBinary
+)
+
+// MapValueBehavior controls how driver.Valuer encodes map[string]string values.
+type MapValueBehavior int
+
+const (
+	// MapValueJSON stores maps as JSON objects, for json, jsonb and text columns.
+	MapValueJSON MapValueBehavior = iota
+	// MapValueHstore stores maps as PostgreSQL hstore literals, for hstore columns.
+	MapValueHstore
+)
+
+// JSONValueBehavior controls how driver.Valuer encodes the values stored as JSON.
+type JSONValueBehavior int
+
+const (
+	// JSONValueString returns JSON as a string, which every driver accepts.
+	JSONValueString JSONValueBehavior = iota
+	// JSONValueBytes returns JSON as []byte, saving a copy and the re-escaping of the text by
+	// drivers such as pgx which bind []byte to json/jsonb parameters as is. lib/pq sends
+	// []byte as bytea instead, keep JSONValueString for it.
+	JSONValueBytes
+)
+
+// MarshalNullBehavior controls how null container values are marshaled to JSON.
+type MarshalNullBehavior int
+
+const (
+	// MarshalNullAsNull marshals null values as null.
+	MarshalNullAsNull MarshalNullBehavior = iota
+	// MarshalNullAsEmpty marshals the null values of slices and arrays as [], and those
+	// of maps, structs and interfaces, such as Of[any], as {}, for the frontend clients
+	// breaking on null containers. Types with their own JSON encoding, like time.Time,
+	// and byte slices are still marshaled as null, as are the unset values marshaled
+	// with UnsetNull.
+	MarshalNullAsEmpty
+)
+
+// DuplicateKeysBehavior controls how UnmarshalJSON and UnmarshalMerge handle JSON
+// objects with duplicate keys.
+type DuplicateKeysBehavior int
+
+const (
+	// DuplicateKeysAllow keeps the last of the duplicate keys, like encoding/json.
+	DuplicateKeysAllow DuplicateKeysBehavior = iota
+	// DuplicateKeysReject fails with ErrDuplicateKey, so that a payload cannot be
+	// read differently by two parsers, one keeping the first key and the other the last.
+	DuplicateKeysReject
+)
+
+// NumberDecodingBehavior controls how JSON numbers are decoded into interface values,
+// such as Of[any] or the values of an Of[map[string]any].
+type NumberDecodingBehavior int
+
+const (
+	// NumbersAsFloat64 decodes numbers as float64, like encoding/json, rounding the
+	// integers above 2^53.
+	NumbersAsFloat64 NumberDecodingBehavior = iota
+	// NumbersAsJSONNumber decodes numbers as json.Number, keeping their text so that
+	// IDs and big integers round-trip exactly.
+	NumbersAsJSONNumber
+)
+
+// JSONFallbackBehavior controls whether Scan and Value() encode the slices and maps
+// without a database encoding of their own as JSON.
+type JSONFallbackBehavior int
+
+const (
+	// JSONFallbackEnabled stores slices and maps as JSON, for text, json and jsonb
+	// columns.
+	JSONFallbackEnabled JSONFallbackBehavior = iota
+	// JSONFallbackDisabled makes Scan and Value() fail with ErrUnsupportedType for
+	// slices and maps, so that a missing Scanner or Valuer is not silently replaced
+	// by JSON.
+	JSONFallbackDisabled
+)
+
+// defaultValue is the type of the Default sentinel.
+type defaultValue struct{}
+
+// Default is the sentinel returned by Value() for unset values when
+// ValueUnsetDefault is configured. It stands for the SQL DEFAULT keyword, which
+// InsertSQL writes in its place.
+var Default driver.Value = defaultValue{}
+
+// ErrUnsetMarshal is returned by MarshalJSON for unset values when UnsetError is configured.
+var ErrUnsetMarshal = errors.New("presence: unset value marshaled to JSON")
+
+// ErrUnsetValue is returned by Value() for unset values when ValueUnsetError is configured.
+var ErrUnsetValue = errors.New("presence: unset value used as database value")
+
+// ErrUnsupportedType is returned by Scan and Value() for the slices and maps to encode
+// as JSON when JSONFallbackDisabled is configured.
+var ErrUnsupportedType = errors.New("presence: type not supported as database value")
+
+// TimeNormalization controls how Of[time.Time] values are normalized by SetValue,
+// and therefore by Scan and UnmarshalJSON, so that times round-trip with equality
+// through databases storing a lower precision than Go.
+type TimeNormalization struct {
+	// Truncate rounds times down to a multiple of this duration since the zero time,
+	// e.g. time.Microsecond for PostgreSQL or time.Second for MySQL DATETIME.
+	// Zero keeps the full nanosecond precision.
+	Truncate time.Duration
+	// UTC converts times to UTC.
+	UTC bool
+}
+
+// normalize applies the normalization to t.
+func (tn TimeNormalization) normalize(t time.Time) time.Time {
+	if tn.Truncate > 0 {
+		t = t.Truncate(tn.Truncate)
+	}
+
+	if tn.UTC {
+		t = t.UTC()
+	}
+
+	return t
+}
+
+// ErrLimitsExceeded is returned by UnmarshalJSON for payloads exceeding the JSONLimits.
+var ErrLimitsExceeded = errors.New("presence: JSON limits exceeded")
+
+// JSONLimits bounds the JSON payloads decoded by UnmarshalJSON, protecting services
+// decoding attacker-controlled documents into Of[any] or JSON columns from deeply
+// nested or huge payloads. The limits apply to the payload of each Of[T] value,
+// a presence value nested in another one being checked again on its own payload.
+type JSONLimits struct {
+	// MaxDepth is the maximum nesting of arrays and objects, 1 for a flat array or
+	// object. Zero means no limit.
+	MaxDepth int
+	// MaxSize is the maximum size of the payload in bytes. Zero means no limit.
+	MaxSize int
+}
+
+// check returns ErrLimitsExceeded when data exceeds the limits.
+func (l JSONLimits) check(data []byte) error {
+	if l.MaxSize > 0 && len(data) > l.MaxSize {
+		return fmt.Errorf("%w : %d bytes, the maximum is %d", ErrLimitsExceeded, len(data), l.MaxSize)
+	}
+
+	if l.MaxDepth <= 0 {
+		return nil
+	}
+
+	depth, inString, escaped := 0, false, false
+	for _, c := range data {
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '[' || c == '{':
+			depth++
+			if depth > l.MaxDepth {
+				return fmt.Errorf("%w : nested deeper than %d", ErrLimitsExceeded, l.MaxDepth)
+			}
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// defaults is a snapshot of the package-level defaults.
+// Snapshots are never modified once published, so readers load them without locking.
+type defaults struct {
+	timeNormalization TimeNormalization
+	marshalUnset      MarshalUnsetBehavior
+	scanNull          ScanNullBehavior
+	valueUnset        ValueUnsetBehavior
+	uuidValue         UUIDValueBehavior
+	jsonValue         JSONValueBehavior
+	mapValue          MapValueBehavior
+	marshalNull       MarshalNullBehavior
+	jsonLimits        JSONLimits
+	duplicateKeys     DuplicateKeysBehavior
+	numberDecoding    NumberDecodingBehavior
+	jsonFallback      JSONFallbackBehavior
+	types             map[reflect.Type]typeDefaults
+	// normalizers holds a func(T) T per type T.
+	normalizers map[reflect.Type]any
+	// validators holds a func(T) error per type T.
+	validators map[reflect.Type]any
+	// composites holds the struct types registered by RegisterComposite.
+	composites      map[reflect.Type]bool
+	errorTranslator ErrorTranslator
+	metricsHook     MetricsHook
+}
+
+// typeDefaults are the behaviors registered for the values of one type.
+type typeDefaults struct {
+	marshalUnset MarshalUnsetBehavior
+	scanNull     ScanNullBehavior
+}
+
 var (
-	defaultMarshalUnset MarshalUnsetBehavior = UnsetSkip
-	defaultScanNull     ScanNullBehavior     = ScanNullAsNull
-	configMu            sync.RWMutex
+	current         atomic.Pointer[defaults]
+	initialDefaults defaults
+	// configMu serializes the writers so that concurrent setters do not lose updates.
+	configMu sync.Mutex
 )
 
-// SetDefaultMarshalUnset sets the package-level default for marshal unset behavior.
-func SetDefaultMarshalUnset(b MarshalUnsetBehavior) {
+// loadDefaults returns the current snapshot of the package-level defaults.
+func loadDefaults() *defaults {
+	if d := current.Load(); d != nil {
+		return d
+	}
+
+	// Nothing set yet: the zero values of the behaviors are the documented defaults.
+	return &initialDefaults
+}
+
+// updateDefaults publishes a copy of the current defaults modified by fn.
+func updateDefaults(fn func(d *defaults)) {
 	configMu.Lock()
 	defer configMu.Unlock()
-	defaultMarshalUnset = b
+
+	d := *loadDefaults()
+	fn(&d)
+	current.Store(&d)
+}
+
+// SetDefaultMarshalUnset sets the package-level default for marshal unset behavior.
+func SetDefaultMarshalUnset(b MarshalUnsetBehavior) {
+	updateDefaults(func(d *defaults) { d.marshalUnset = b })
 }
 
 // GetDefaultMarshalUnset returns the package-level default for marshal unset behavior.
 func GetDefaultMarshalUnset() MarshalUnsetBehavior {
-	configMu.RLock()
-	defer configMu.RUnlock()
-
-	return defaultMarshalUnset
+	return loadDefaults().marshalUnset
 }
 
 // SetDefaultScanNull sets the package-level default for scan null behavior.
 func SetDefaultScanNull(b ScanNullBehavior) {
-	configMu.Lock()
-	defer configMu.Unlock()
-	defaultScanNull = b
+	updateDefaults(func(d *defaults) { d.scanNull = b })
 }
 
 // GetDefaultScanNull returns the package-level default for scan null behavior.
 func GetDefaultScanNull() ScanNullBehavior {
-	configMu.RLock()
-	defer configMu.RUnlock()
+	return loadDefaults().scanNull
+}
+
+// SetDefaultValueUnset sets the package-level default for value unset behavior.
+func SetDefaultValueUnset(b ValueUnsetBehavior) {
+	updateDefaults(func(d *defaults) { d.valueUnset = b })
+}
+
+// GetDefaultValueUnset returns the package-level default for value unset behavior.
+func GetDefaultValueUnset() ValueUnsetBehavior {
+	return loadDefaults().valueUnset
+}
+
+// SetDefaultTimeNormalization sets the package-level normalization of time values.
+func SetDefaultTimeNormalization(tn TimeNormalization) {
+	updateDefaults(func(d *defaults) { d.timeNormalization = tn })
+}
+
+// GetDefaultTimeNormalization returns the package-level normalization of time values.
+func GetDefaultTimeNormalization() TimeNormalization {
+	return loadDefaults().timeNormalization
+}
+
+// SetDefaultUUIDValue sets the package-level encoding of UUID database values.
+func SetDefaultUUIDValue(b UUIDValueBehavior) {
+	updateDefaults(func(d *defaults) { d.uuidValue = b })
+}
+
+// GetDefaultUUIDValue returns the package-level encoding of UUID database values.
+func GetDefaultUUIDValue() UUIDValueBehavior {
+	return loadDefaults().uuidValue
+}
+
+// SetDefaultJSONValue sets the package-level encoding of JSON database values.
+func SetDefaultJSONValue(b JSONValueBehavior) {
+	updateDefaults(func(d *defaults) { d.jsonValue = b })
+}
+
+// GetDefaultJSONValue returns the package-level encoding of JSON database values.
+func GetDefaultJSONValue() JSONValueBehavior {
+	return loadDefaults().jsonValue
+}
+
+// SetDefaultMapValue sets the package-level encoding of map database values.
+func SetDefaultMapValue(b MapValueBehavior) {
+	updateDefaults(func(d *defaults) { d.mapValue = b })
+}
+
+// GetDefaultMapValue returns the package-level encoding of map database values.
+func GetDefaultMapValue() MapValueBehavior {
+	return loadDefaults().mapValue
+}
+
+// SetDefaultMarshalNull sets the package-level marshaling of null container values.
+func SetDefaultMarshalNull(b MarshalNullBehavior) {
+	updateDefaults(func(d *defaults) { d.marshalNull = b })
+}
+
+// GetDefaultMarshalNull returns the package-level marshaling of null container values.
+func GetDefaultMarshalNull() MarshalNullBehavior {
+	return loadDefaults().marshalNull
+}
+
+// SetDefaultJSONLimits sets the package-level limits of the JSON payloads decoded by
+// UnmarshalJSON.
+func SetDefaultJSONLimits(l JSONLimits) {
+	updateDefaults(func(d *defaults) { d.jsonLimits = l })
+}
+
+// GetDefaultJSONLimits returns the package-level limits of the JSON payloads decoded by
+// UnmarshalJSON.
+func GetDefaultJSONLimits() JSONLimits {
+	return loadDefaults().jsonLimits
+}
+
+// SetDefaultDuplicateKeys sets the package-level handling of duplicate JSON object keys.
+func SetDefaultDuplicateKeys(b DuplicateKeysBehavior) {
+	updateDefaults(func(d *defaults) { d.duplicateKeys = b })
+}
+
+// GetDefaultDuplicateKeys returns the package-level handling of duplicate JSON object keys.
+func GetDefaultDuplicateKeys() DuplicateKeysBehavior {
+	return loadDefaults().duplicateKeys
+}
+
+// SetDefaultNumberDecoding sets the package-level decoding of JSON numbers into
+// interface values, by UnmarshalJSON and Scan.
+func SetDefaultNumberDecoding(b NumberDecodingBehavior) {
+	updateDefaults(func(d *defaults) { d.numberDecoding = b })
+}
+
+// GetDefaultNumberDecoding returns the package-level decoding of JSON numbers into
+// interface values.
+func GetDefaultNumberDecoding() NumberDecodingBehavior {
+	return loadDefaults().numberDecoding
+}
+
+// SetDefaultJSONFallback sets the package-level JSON fallback of slices and maps in
+// Scan and Value().
+func SetDefaultJSONFallback(b JSONFallbackBehavior) {
+	updateDefaults(func(d *defaults) { d.jsonFallback = b })
+}
+
+// GetDefaultJSONFallback returns the package-level JSON fallback of slices and maps.
+func GetDefaultJSONFallback() JSONFallbackBehavior {
+	return loadDefaults().jsonFallback
+}
+
+// DetectJSONValue returns the JSON encoding suited to the driver of db: JSONValueBytes for
+// pgx, JSONValueString otherwise.
+//
+//	presence.SetDefaultJSONValue(presence.DetectJSONValue(db))
+func DetectJSONValue(db *sql.DB) JSONValueBehavior {
+	if db == nil {
+		return JSONValueString
+	}
+
+	typ := reflect.TypeOf(db.Driver())
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if strings.HasPrefix(typ.PkgPath(), "github.com/jackc/pgx/") {
+		return JSONValueBytes
+	}
+
+	return JSONValueString
+}
+
+// RegisterTypeDefaults sets the marshal unset and scan null behaviors of all the Of[T]
+// values, and of the named types embedding Of[T], taking precedence over the
+// package-level defaults but not over the per-value settings:
+//
+//	presence.RegisterTypeDefaults[time.Time](presence.UnsetSkip, presence.ScanNullAsUnset)
+func RegisterTypeDefaults[T any](marshalUnset MarshalUnsetBehavior, scanNull ScanNullBehavior) {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		types := make(map[reflect.Type]typeDefaults, len(d.types)+1)
+		maps.Copy(types, d.types)
+		types[typ] = typeDefaults{marshalUnset: marshalUnset, scanNull: scanNull}
+		d.types = types
+	})
+}
+
+// UnregisterTypeDefaults removes the behaviors registered for T by RegisterTypeDefaults.
+func UnregisterTypeDefaults[T any]() {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		types := maps.Clone(d.types)
+		delete(types, typ)
+		d.types = types
+	})
+}
+
+// typeDefaultsOf returns the behaviors registered for T.
+func typeDefaultsOf[T any]() (typeDefaults, bool) {
+	d := loadDefaults()
+	if len(d.types) == 0 {
+		return typeDefaults{}, false
+	}
+
+	td, ok := d.types[reflect.TypeFor[T]()]
+
+	return td, ok
+}
+
+// RegisterNormalizer sets fn as the normalizer of the values of T, applied to the values
+// stored by SetValue (and so FromValue), Scan and UnmarshalJSON, after the time
+// normalization for time.Time. It centralizes data hygiene such as trimming, lowercasing
+// or clamping:
+//
+//	presence.RegisterNormalizer(strings.TrimSpace)
+//	presence.RegisterNormalizer(func(e Email) Email { return Email(strings.ToLower(string(e))) })
+//
+// Normalizers are registered per type: the one of string applies to Of[string] and
+// presence.String, not to Of[Email]. Registering a normalizer again replaces it.
+func RegisterNormalizer[T any](fn func(T) T) {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		normalizers := make(map[reflect.Type]any, len(d.normalizers)+1)
+		maps.Copy(normalizers, d.normalizers)
+		normalizers[typ] = fn
+		d.normalizers = normalizers
+	})
+}
+
+// UnregisterNormalizer removes the normalizer registered for T by RegisterNormalizer.
+func UnregisterNormalizer[T any]() {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		normalizers := maps.Clone(d.normalizers)
+		delete(normalizers, typ)
+		d.normalizers = normalizers
+	})
+}
+
+// normalizerOf returns the normalizer registered for T, nil if none.
+func normalizerOf[T any]() func(T) T {
+	d := loadDefaults()
+	if len(d.normalizers) == 0 {
+		return nil
+	}
+
+	fn, _ := d.normalizers[reflect.TypeFor[T]()].(func(T) T)
+
+	return fn
+}
+
+// RegisterValidator sets fn as the validator of the values of T, run on the values
+// decoded by Scan and UnmarshalJSON and on those given to SetValueChecked, after their
+// normalization, so that invalid values are rejected at the boundary:
+//
+//	presence.RegisterValidator(func(age Age) error {
+//		if age < 0 {
+//			return errors.New("negative age")
+//		}
+//
+//		return nil
+//	})
+//
+// The error of fn is wrapped in the one returned. Like normalizers, validators are
+// registered per type, and registering a validator again replaces it. SetValue and
+// FromValue do not validate.
+func RegisterValidator[T any](fn func(T) error) {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		validators := make(map[reflect.Type]any, len(d.validators)+1)
+		maps.Copy(validators, d.validators)
+		validators[typ] = fn
+		d.validators = validators
+	})
+}
+
+// UnregisterValidator removes the validator registered for T by RegisterValidator.
+func UnregisterValidator[T any]() {
+	typ := reflect.TypeFor[T]()
+	updateDefaults(func(d *defaults) {
+		validators := maps.Clone(d.validators)
+		delete(validators, typ)
+		d.validators = validators
+	})
+}
+
+// GetValidator returns the validator registered for T by RegisterValidator, nil if none,
+// so that a new validator can be composed with it.
+func GetValidator[T any]() func(T) error {
+	return validatorOf[T]()
+}
+
+// validatorOf returns the validator registered for T, nil if none.
+func validatorOf[T any]() func(T) error {
+	d := loadDefaults()
+	if len(d.validators) == 0 {
+		return nil
+	}
+
+	fn, _ := d.validators[reflect.TypeFor[T]()].(func(T) error)
 
-	return defaultScanNull
+	return fn
 }
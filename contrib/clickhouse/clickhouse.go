@@ -0,0 +1,30 @@
+package presenceclickhouse
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pivaldi/presence"
+)
+
+// Bind returns the argument binding n to a Nullable(T) column in row mode
+// (batch.Append, batch.Column(i).AppendRow): the value of n, nil when it is null or
+// unset.
+func Bind[T any](n presence.Of[T]) any {
+	if v, ok := n.Get(); ok {
+		return v
+	}
+
+	return nil
+}
+
+// AppendColumn appends values to the Nullable(T) column col of a batch in column mode,
+// null and unset values as NULL. T must be the Go type of the column, as for []*T.
+func AppendColumn[T any](col driver.BatchColumn, values []presence.Of[T]) error {
+	err := col.Append(presence.Ptrs(values))
+	if err != nil {
+		return fmt.Errorf("presence clickhouse appending %T : %w", values, err)
+	}
+
+	return nil
+}
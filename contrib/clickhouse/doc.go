@@ -0,0 +1,24 @@
+/*
+Package presenceclickhouse binds presence values to the Nullable(T) columns of
+[github.com/ClickHouse/clickhouse-go/v2] batches.
+
+The driver scans Nullable(T) columns into presence values through sql.Scanner, NULL
+giving null values, but its column encoders only take driver.Valuer results of their
+own Go type, which Of[T].Value does not return for every T (unsigned integers and
+float32 among them), and its column-oriented API bypasses driver.Valuer. Bind and
+AppendColumn pass the values themselves, null and unset values as NULL:
+
+	// Row mode
+	err := batch.Append(presenceclickhouse.Bind(e.ID), presenceclickhouse.Bind(e.Hits))
+
+	// Column mode
+	err = presenceclickhouse.AppendColumn(batch.Column(1), hits)
+
+	// Scanning
+	var hits presence.Of[uint64]
+	err = rows.Scan(&hits)
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presenceclickhouse
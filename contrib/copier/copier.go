@@ -0,0 +1,63 @@
+package presencecopier
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"github.com/pivaldi/presence"
+)
+
+// Converters returns the copier converters between T, *T and presence.Of[T], both
+// ways: values are wrapped, nil pointers become null, and null or unset values become
+// nil pointers or the zero value of T.
+func Converters[T any]() []copier.TypeConverter {
+	var (
+		value T
+		ptr   *T
+		n     presence.Of[T]
+	)
+
+	return []copier.TypeConverter{
+		{SrcType: value, DstType: n, Fn: func(src any) (any, error) {
+			return presence.FromValue(src.(T)), nil
+		}},
+		{SrcType: ptr, DstType: n, Fn: func(src any) (any, error) {
+			return presence.FromPtr(src.(*T)), nil
+		}},
+		{SrcType: n, DstType: value, Fn: func(src any) (any, error) {
+			n := src.(presence.Of[T])
+
+			return n.GetOr(value), nil
+		}},
+		{SrcType: n, DstType: ptr, Fn: func(src any) (any, error) {
+			n := src.(presence.Of[T])
+
+			return n.Ptr(), nil
+		}},
+	}
+}
+
+// Option returns the copier option converting the types supported by presence
+// (string, bool, int, int16, int32, int64, float64, time.Time and uuid.UUID) and those
+// of converters.
+func Option(converters ...[]copier.TypeConverter) copier.Option {
+	all := [][]copier.TypeConverter{
+		Converters[string](),
+		Converters[bool](),
+		Converters[int](),
+		Converters[int16](),
+		Converters[int32](),
+		Converters[int64](),
+		Converters[float64](),
+		Converters[time.Time](),
+		Converters[uuid.UUID](),
+	}
+
+	var opt copier.Option
+	for _, c := range append(all, converters...) {
+		opt.Converters = append(opt.Converters, c...)
+	}
+
+	return opt
+}
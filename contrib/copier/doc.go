@@ -0,0 +1,18 @@
+/*
+Package presencecopier provides the [github.com/jinzhu/copier] converters between
+plain values and presence values, so that copying between entity structs and presence
+DTOs wraps and unwraps the values, nil pointers becoming null, instead of leaving the
+presence fields unset:
+
+	var dto UserDTO // Email presence.Of[string], Age presence.Of[int]
+	err := copier.CopyWithOption(&dto, &user, presencecopier.Option())
+	// user.Email "ada@example.com" → dto.Email holds it, user.Age nil → dto.Age is null
+
+The converters of other value types are added with Converters:
+
+	opt := presencecopier.Option(presencecopier.Converters[Money]())
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencecopier
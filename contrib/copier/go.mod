@@ -0,0 +1,11 @@
+module github.com/pivaldi/presence/contrib/copier
+
+go 1.25.0
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jinzhu/copier v0.4.0
+	github.com/pivaldi/presence v0.0.0
+)
+
+replace github.com/pivaldi/presence => ../..
@@ -0,0 +1,40 @@
+// Command presence-easyjson generates the easyjson methods of the struct types with
+// presence fields of the package in the current directory, see package presenceeasyjson.
+//
+//	presence-easyjson [-type T1,T2] [-output presence_easyjson.go]
+//
+// The types annotated with //presence:easyjson are generated along with those of -type.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	presenceeasyjson "github.com/pivaldi/presence/contrib/easyjson"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated struct types to generate, besides the annotated ones")
+	output := flag.String("output", "presence_easyjson.go", "generated file")
+	flag.Parse()
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	var out bytes.Buffer
+
+	err := presenceeasyjson.Generate(&out, ".", names...)
+	if err == nil {
+		err = os.WriteFile(*output, out.Bytes(), 0o600)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "presence-easyjson:", err)
+		os.Exit(1)
+	}
+}
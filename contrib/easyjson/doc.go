@@ -0,0 +1,27 @@
+/*
+Package presenceeasyjson generates [github.com/mailru/easyjson] compatible marshaling
+code for structs with presence fields, for services where encoding/json is the
+bottleneck.
+
+The presence-easyjson command writes the MarshalEasyJSON, UnmarshalEasyJSON,
+MarshalJSON and UnmarshalJSON methods of the struct types annotated with
+//presence:easyjson, or named with -type:
+
+	//go:generate go run github.com/pivaldi/presence/contrib/easyjson/cmd/presence-easyjson
+
+	//presence:easyjson
+	type UserPatch struct {
+		Name  presence.Of[string] `json:"name,omitzero"`
+		Email presence.Of[string] `json:"email,omitzero"`
+	}
+
+The generated code encodes like encoding/json: presence fields tagged omitzero are
+skipped when their IsZero method reports true, and null and unset values decode as
+with UnmarshalJSON. Primitive values are read and written directly, other values
+through their easyjson methods when they have some, and encoding/json otherwise.
+Object keys are matched exactly, as easyjson does.
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presenceeasyjson
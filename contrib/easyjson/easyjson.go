@@ -0,0 +1,152 @@
+package presenceeasyjson
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+	"github.com/pivaldi/presence"
+)
+
+// The functions below are called by the generated code.
+
+// WriteOf writes n to w like its MarshalJSON method, primitive values being written
+// directly.
+func WriteOf[T any](w *jwriter.Writer, n presence.Of[T]) {
+	value, ok := n.Get()
+	if !ok {
+		w.RawString("null")
+
+		return
+	}
+
+	switch v := any(value).(type) {
+	case string:
+		w.String(v)
+	case bool:
+		w.Bool(v)
+	case int:
+		w.Int(v)
+	case int16:
+		w.Int16(v)
+	case int32:
+		w.Int32(v)
+	case int64:
+		w.Int64(v)
+	case easyjson.Marshaler:
+		v.MarshalEasyJSON(w)
+	default:
+		w.Raw(n.MarshalJSON())
+	}
+}
+
+// ReadOf reads n from l like its UnmarshalJSON method, primitive values being read
+// directly.
+func ReadOf[T any](l *jlexer.Lexer, n *presence.Of[T]) {
+	if l.IsNull() {
+		l.Skip()
+		n.SetNull()
+
+		return
+	}
+
+	var value T
+	switch p := any(&value).(type) {
+	case *string:
+		*p = l.String()
+	case *bool:
+		*p = l.Bool()
+	case *int:
+		*p = l.Int()
+	case *int16:
+		*p = l.Int16()
+	case *int32:
+		*p = l.Int32()
+	case *int64:
+		*p = l.Int64()
+	case easyjson.Unmarshaler:
+		p.UnmarshalEasyJSON(l)
+	default:
+		l.AddError(n.UnmarshalJSON(l.Raw()))
+
+		return
+	}
+
+	if l.Ok() {
+		n.SetValue(value)
+	}
+}
+
+// ReadOfPtr reads the presence value *p points to from l, allocating it if needed.
+// Like encoding/json, null sets *p to nil.
+func ReadOfPtr[T any](l *jlexer.Lexer, p **presence.Of[T]) {
+	if l.IsNull() {
+		l.Skip()
+		*p = nil
+
+		return
+	}
+
+	if *p == nil {
+		*p = new(presence.Of[T])
+	}
+
+	ReadOf(l, *p)
+}
+
+// Write writes v to w with its MarshalEasyJSON method, or encoding/json.
+func Write(w *jwriter.Writer, v any) {
+	if m, ok := v.(easyjson.Marshaler); ok {
+		m.MarshalEasyJSON(w)
+
+		return
+	}
+
+	w.Raw(json.Marshal(v))
+}
+
+// Read reads the value p points to from l with its UnmarshalEasyJSON method, or
+// encoding/json.
+func Read(l *jlexer.Lexer, p any) {
+	if u, ok := p.(easyjson.Unmarshaler); ok {
+		u.UnmarshalEasyJSON(l)
+
+		return
+	}
+
+	l.AddError(json.Unmarshal(l.Raw(), p))
+}
+
+// IsEmpty reports whether encoding/json's omitempty omits v.
+func IsEmpty(v any) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return true
+	}
+
+	if kind := rv.Kind(); kind == reflect.Array || kind == reflect.Map || kind == reflect.Slice ||
+		kind == reflect.String {
+		return rv.Len() == 0
+	}
+
+	if rv.Kind() == reflect.Struct {
+		return false
+	}
+
+	return rv.IsZero()
+}
+
+// IsZero reports whether encoding/json's omitzero omits v.
+func IsZero(v any) bool {
+	if z, ok := v.(interface{ IsZero() bool }); ok {
+		rv := reflect.ValueOf(v)
+
+		return rv.Kind() == reflect.Pointer && rv.IsNil() || z.IsZero()
+	}
+
+	rv := reflect.ValueOf(v)
+
+	return !rv.IsValid() || rv.IsZero()
+}
@@ -0,0 +1,327 @@
+package presenceeasyjson
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pivaldi/presence/contrib/internal/gen"
+)
+
+// Annotation marks the struct types to generate the methods of.
+const Annotation = "//presence:easyjson"
+
+// ErrNoTypes is returned by Generate when no struct type is annotated nor named.
+var ErrNoTypes = gen.ErrNoTypes
+
+// fieldKind tells how the generated code encodes a field.
+type fieldKind int
+
+const (
+	kindOther       fieldKind = iota // through Write and Read
+	kindPresence                     // through WriteOf and ReadOf
+	kindPresencePtr                  // a *presence.Of[T], through WriteOf and ReadOfPtr
+	kindBasic                        // a string, bool or integer, through the writer and lexer methods
+)
+
+// jsonField is a field of a generated struct type, embedded ones being flattened.
+type jsonField struct {
+	name string // the JSON key
+	expr string // the Go selector from the receiver, e.g. "Base.Name"
+	kind fieldKind
+	// of selects the presence.Of[T] of a type embedding it, e.g. ".Of"
+	of string
+	// method is the writer and lexer method of basic fields, e.g. "Int64"
+	method string
+	// omit is the condition to write the field, empty when always written
+	omit string
+}
+
+// Generate writes to w the easyjson methods of the struct types of the package in dir
+// annotated with //presence:easyjson, or named in typeNames.
+func Generate(w io.Writer, dir string, typeNames ...string) error {
+	pkg, err := gen.LoadPackage(dir)
+	if err != nil {
+		return fmt.Errorf("presence easyjson : %w", err)
+	}
+
+	names := slices.Clone(typeNames)
+	for _, t := range gen.AnnotatedTypes(pkg.Syntax, Annotation) {
+		names = append(names, t.Name)
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	if len(names) == 0 {
+		return ErrNoTypes
+	}
+
+	var body bytes.Buffer
+	for _, name := range names {
+		err := generateType(&body, pkg.Types, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by presence-easyjson. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	src.WriteString("import (\n\t\"github.com/mailru/easyjson/jlexer\"\n\t\"github.com/mailru/easyjson/jwriter\"\n")
+	src.WriteString("\tpresenceeasyjson \"github.com/pivaldi/presence/contrib/easyjson\"\n)\n")
+	src.Write(body.Bytes())
+
+	err = gen.Write(w, src.Bytes())
+	if err != nil {
+		return fmt.Errorf("presence easyjson : %w", err)
+	}
+
+	return nil
+}
+
+// generateType writes the methods of the struct type name.
+func generateType(w *bytes.Buffer, pkg *types.Package, name string) error {
+	obj := pkg.Scope().Lookup(name)
+	if obj == nil {
+		return fmt.Errorf("presence easyjson : unknown type %s", name)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("presence easyjson : %s is not a struct", name)
+	}
+
+	fields, err := structFields(st, "", map[string]bool{})
+	if err != nil {
+		return fmt.Errorf("presence easyjson generating %s : %w", name, err)
+	}
+
+	writeMarshal(w, name, fields)
+	writeUnmarshal(w, name, fields)
+
+	return nil
+}
+
+// structFields returns the JSON fields of st, following the encoding/json tag rules.
+func structFields(st *types.Struct, prefix string, seen map[string]bool) ([]jsonField, error) {
+	var fields []jsonField
+
+	for i := range st.NumFields() {
+		v := st.Field(i)
+		if !v.Exported() && !v.Embedded() {
+			continue
+		}
+
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		key, opts, _ := strings.Cut(tag, ",")
+		options := strings.Split(opts, ",")
+		expr := prefix + v.Name()
+
+		if _, isPresence := presenceSelector(v.Type()); v.Embedded() && key == "" && !isPresence {
+			if ptr, isPtr := v.Type().Underlying().(*types.Pointer); isPtr {
+				if _, ok := ptr.Elem().Underlying().(*types.Struct); ok {
+					return nil, fmt.Errorf("embedded struct pointer %s is not supported", expr)
+				}
+			}
+
+			if inner, ok := v.Type().Underlying().(*types.Struct); ok {
+				embedded, err := structFields(inner, expr+".", seen)
+				if err != nil {
+					return nil, err
+				}
+
+				fields = append(fields, embedded...)
+
+				continue
+			}
+		}
+
+		if !v.Exported() {
+			continue
+		}
+
+		if key == "" {
+			key = v.Name()
+		}
+
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate JSON key %q", key)
+		}
+
+		seen[key] = true
+		fields = append(fields, newJSONField(key, expr, v.Type(), options))
+	}
+
+	return fields, nil
+}
+
+// newJSONField returns the field named key, of type typ, with the tag options.
+func newJSONField(key, expr string, typ types.Type, options []string) jsonField {
+	field := jsonField{name: key, expr: expr}
+	omitEmpty, omitZero := slices.Contains(options, "omitempty"), slices.Contains(options, "omitzero")
+
+	if of, ok := presenceSelector(typ); ok {
+		field.kind, field.of = kindPresence, of
+		if omitZero {
+			field.omit = "!v." + expr + ".IsZero()"
+		}
+
+		return field
+	}
+
+	if ptr, ok := typ.(*types.Pointer); ok {
+		if of, isPresence := presenceSelector(ptr.Elem()); isPresence && of == "" {
+			field.kind = kindPresencePtr
+			switch {
+			case omitZero:
+				field.omit = "v." + expr + " != nil && !v." + expr + ".IsZero()"
+			case omitEmpty:
+				field.omit = "v." + expr + " != nil"
+			}
+
+			return field
+		}
+	}
+
+	if basic, ok := typ.(*types.Basic); ok && basicMethods[basic.Kind()] != "" {
+		field.kind, field.method = kindBasic, basicMethods[basic.Kind()]
+		if omitEmpty || omitZero {
+			field.omit = basicNotZero(basic, "v."+expr)
+		}
+
+		return field
+	}
+
+	switch {
+	case omitZero:
+		field.omit = "!presenceeasyjson.IsZero(v." + expr + ")"
+	case omitEmpty:
+		field.omit = "!presenceeasyjson.IsEmpty(v." + expr + ")"
+	}
+
+	return field
+}
+
+// presenceSelector reports whether typ is presence.Of[T] or a type only embedding it,
+// like presence.String, returning the selector of the embedded presence.Of[T].
+func presenceSelector(typ types.Type) (string, bool) {
+	if isPresenceOf(typ) {
+		return "", true
+	}
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok || st.NumFields() != 1 {
+		return "", false
+	}
+
+	if v := st.Field(0); v.Embedded() && isPresenceOf(v.Type()) {
+		return "." + v.Name(), true
+	}
+
+	return "", false
+}
+
+func isPresenceOf(typ types.Type) bool {
+	_, ok := gen.PresenceOfElem(typ)
+
+	return ok
+}
+
+// basicMethods are the writer and lexer methods of the basic types written directly,
+// floats going through encoding/json, whose formatting differs.
+var basicMethods = map[types.BasicKind]string{
+	types.String: "String", types.Bool: "Bool",
+	types.Int: "Int", types.Int8: "Int8", types.Int16: "Int16", types.Int32: "Int32", types.Int64: "Int64",
+	types.Uint: "Uint", types.Uint8: "Uint8", types.Uint16: "Uint16", types.Uint32: "Uint32", types.Uint64: "Uint64",
+}
+
+// basicNotZero returns the condition of expr, of a basic type, not being zero.
+func basicNotZero(basic *types.Basic, expr string) string {
+	if basic.Kind() == types.String {
+		return expr + ` != ""`
+	}
+
+	if basic.Kind() == types.Bool {
+		return expr
+	}
+
+	return expr + " != 0"
+}
+
+func writeMarshal(w *bytes.Buffer, name string, fields []jsonField) {
+	fmt.Fprintf(w, "\n// MarshalEasyJSON implements easyjson.Marshaler.\n")
+	fmt.Fprintf(w, "func (v %s) MarshalEasyJSON(w *jwriter.Writer) {\n", name)
+	if len(fields) > 0 {
+		fmt.Fprintf(w, "\tfirst := true\n")
+	}
+
+	fmt.Fprintf(w, "\tw.RawByte('{')\n")
+
+	for _, f := range fields {
+		if f.omit != "" {
+			fmt.Fprintf(w, "\tif %s {\n", f.omit)
+		}
+
+		fmt.Fprintf(w, "\tif !first {\n\t\tw.RawByte(',')\n\t}\n\tfirst = false\n")
+		fmt.Fprintf(w, "\tw.RawString(%q)\n", fmt.Sprintf("%q:", f.name))
+
+		switch f.kind {
+		case kindPresence:
+			fmt.Fprintf(w, "\tpresenceeasyjson.WriteOf(w, v.%s%s)\n", f.expr, f.of)
+		case kindPresencePtr:
+			fmt.Fprintf(w, "\tif v.%[1]s == nil {\n\t\tw.RawString(\"null\")\n\t} else {\n", f.expr)
+			fmt.Fprintf(w, "\t\tpresenceeasyjson.WriteOf(w, *v.%s)\n\t}\n", f.expr)
+		case kindBasic:
+			fmt.Fprintf(w, "\tw.%s(v.%s)\n", f.method, f.expr)
+		case kindOther:
+			fmt.Fprintf(w, "\tpresenceeasyjson.Write(w, v.%s)\n", f.expr)
+		}
+
+		if f.omit != "" {
+			fmt.Fprintf(w, "\t}\n")
+		}
+	}
+
+	fmt.Fprintf(w, "\tw.RawByte('}')\n}\n")
+	fmt.Fprintf(w, "\n// MarshalJSON implements json.Marshaler.\n")
+	fmt.Fprintf(w, "func (v %s) MarshalJSON() ([]byte, error) {\n", name)
+	fmt.Fprintf(w, "\tw := jwriter.Writer{}\n\tv.MarshalEasyJSON(&w)\n\n\treturn w.BuildBytes()\n}\n")
+}
+
+func writeUnmarshal(w *bytes.Buffer, name string, fields []jsonField) {
+	fmt.Fprintf(w, "\n// UnmarshalEasyJSON implements easyjson.Unmarshaler.\n")
+	fmt.Fprintf(w, "func (v *%s) UnmarshalEasyJSON(l *jlexer.Lexer) {\n", name)
+	fmt.Fprintf(w, "\tisTopLevel := l.IsStart()\n")
+	fmt.Fprintf(w, "\tif l.IsNull() {\n\t\tl.Skip()\n\n\t\treturn\n\t}\n\n")
+	fmt.Fprintf(w, "\tl.Delim('{')\n\tfor !l.IsDelim('}') {\n")
+	fmt.Fprintf(w, "\t\tkey := l.UnsafeFieldName(false)\n\t\tl.WantColon()\n\n\t\tswitch key {\n")
+
+	for _, f := range fields {
+		fmt.Fprintf(w, "\t\tcase %q:\n", f.name)
+
+		switch f.kind {
+		case kindPresence:
+			fmt.Fprintf(w, "\t\t\tpresenceeasyjson.ReadOf(l, &v.%s%s)\n", f.expr, f.of)
+		case kindPresencePtr:
+			fmt.Fprintf(w, "\t\t\tpresenceeasyjson.ReadOfPtr(l, &v.%s)\n", f.expr)
+		case kindBasic:
+			fmt.Fprintf(w, "\t\t\tv.%s = l.%s()\n", f.expr, f.method)
+		case kindOther:
+			fmt.Fprintf(w, "\t\t\tpresenceeasyjson.Read(l, &v.%s)\n", f.expr)
+		}
+	}
+
+	fmt.Fprintf(w, "\t\tdefault:\n\t\t\tl.SkipRecursive()\n\t\t}\n\n\t\tl.WantComma()\n\t}\n")
+	fmt.Fprintf(w, "\tl.Delim('}')\n\n\tif isTopLevel {\n\t\tl.Consumed()\n\t}\n}\n")
+	fmt.Fprintf(w, "\n// UnmarshalJSON implements json.Unmarshaler.\n")
+	fmt.Fprintf(w, "func (v *%s) UnmarshalJSON(data []byte) error {\n", name)
+	fmt.Fprintf(w, "\tl := jlexer.Lexer{Data: data}\n\tv.UnmarshalEasyJSON(&l)\n\n\treturn l.Error()\n}\n")
+}
@@ -0,0 +1,22 @@
+module github.com/pivaldi/presence/contrib/easyjson
+
+go 1.25.0
+
+require (
+	github.com/mailru/easyjson v0.9.2
+	github.com/pivaldi/presence v0.0.0
+	github.com/pivaldi/presence/contrib/internal v0.0.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+	golang.org/x/tools v0.47.0 // indirect
+)
+
+replace (
+	github.com/pivaldi/presence => ../..
+	github.com/pivaldi/presence/contrib/internal => ../internal
+)
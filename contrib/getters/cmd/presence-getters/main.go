@@ -0,0 +1,40 @@
+// Command presence-getters generates the nil-safe getters of the struct types with
+// presence fields of the package in the current directory, see package presencegetters.
+//
+//	presence-getters [-type T1,T2] [-output presence_getters.go]
+//
+// The types annotated with //presence:getters are generated along with those of -type.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	presencegetters "github.com/pivaldi/presence/contrib/getters"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated struct types to generate, besides the annotated ones")
+	output := flag.String("output", "presence_getters.go", "generated file")
+	flag.Parse()
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	var out bytes.Buffer
+
+	err := presencegetters.Generate(&out, ".", names...)
+	if err == nil {
+		err = os.WriteFile(*output, out.Bytes(), 0o600)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "presence-getters:", err)
+		os.Exit(1)
+	}
+}
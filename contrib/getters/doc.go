@@ -0,0 +1,35 @@
+/*
+Package presencegetters generates nil-safe getters traversing the nested presence
+values of structs, like the getters protoc generates for messages.
+
+The presence-getters command writes, for the struct types annotated with
+//presence:getters or named with -type, a Get<Path>() (T, bool) method per presence
+field, at any depth, and per plain field reached through a presence or pointer field:
+
+	//go:generate go run github.com/pivaldi/presence/contrib/getters/cmd/presence-getters
+
+	//presence:getters
+	type User struct {
+		Name    presence.Of[string]  `json:"name"`
+		Profile presence.Of[Profile] `json:"profile"`
+	}
+
+	type Profile struct {
+		Bio  presence.Of[string] `json:"bio"`
+		Home *Address            `json:"home"`
+	}
+
+	// generated
+	func (v *User) GetName() (string, bool)
+	func (v *User) GetProfile() (Profile, bool)
+	func (v *User) GetProfileBio() (string, bool)
+	func (v *User) GetProfileHomeCity() (string, bool)
+
+The getters report false when the receiver, or a field on the path, is nil, null or
+unset. Nested struct fields are traversed through presence values, pointers and
+plain struct fields, the fields of the types already on the path being left out.
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencegetters
@@ -0,0 +1,243 @@
+package presencegetters
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pivaldi/presence/contrib/internal/gen"
+)
+
+// Annotation marks the struct types to generate the getters of.
+const Annotation = "//presence:getters"
+
+// ErrNoTypes is returned by Generate when no struct type is annotated nor named.
+var ErrNoTypes = gen.ErrNoTypes
+
+// stepKind tells how a getter goes through a field of its path.
+type stepKind int
+
+const (
+	stepStruct   stepKind = iota // a plain struct field, selected
+	stepPointer                  // a pointer to a struct, checked for nil
+	stepPresence                 // a presence field, unwrapped by Get
+)
+
+// step is a field on the path of a getter.
+type step struct {
+	name string
+	kind stepKind
+	// nilable reports whether the value of a presence field is a pointer.
+	nilable bool
+}
+
+// getter is the getter of the field at the end of path, of type result.
+type getter struct {
+	path   []step
+	result types.Type
+}
+
+// name returns the name of the getter method.
+func (g getter) name() string {
+	var b strings.Builder
+	b.WriteString("Get")
+
+	for _, s := range g.path {
+		b.WriteString(s.name)
+	}
+
+	return b.String()
+}
+
+// Generate writes to w the getters of the struct types of the package in dir annotated
+// with //presence:getters, or named in typeNames.
+func Generate(w io.Writer, dir string, typeNames ...string) error {
+	pkg, err := gen.LoadPackage(dir)
+	if err != nil {
+		return fmt.Errorf("presence getters : %w", err)
+	}
+
+	names := slices.Clone(typeNames)
+	for _, t := range gen.AnnotatedTypes(pkg.Syntax, Annotation) {
+		names = append(names, t.Name)
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	if len(names) == 0 {
+		return ErrNoTypes
+	}
+
+	g := &generator{pkg: pkg.Types, Imports: gen.NewImports(pkg.Types)}
+
+	var body bytes.Buffer
+	for _, name := range names {
+		err := g.generateType(&body, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by presence-getters. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	g.WriteDecl(&src)
+	src.Write(body.Bytes())
+
+	err = gen.Write(w, src.Bytes())
+	if err != nil {
+		return fmt.Errorf("presence getters : %w", err)
+	}
+
+	return nil
+}
+
+// generator writes the getters of the types of the package pkg, recording the imports
+// of the types it names.
+type generator struct {
+	*gen.Imports
+
+	pkg *types.Package
+}
+
+// generateType writes the getters of the struct type name.
+func (g *generator) generateType(w *bytes.Buffer, name string) error {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return fmt.Errorf("presence getters : unknown type %s", name)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return fmt.Errorf("presence getters : %s is not a struct", name)
+	}
+
+	var getters []getter
+	collect(st, nil, false, map[types.Type]bool{obj.Type(): true}, &getters)
+
+	seen := map[string]bool{}
+	for _, gt := range getters {
+		if seen[gt.name()] {
+			return fmt.Errorf("presence getters generating %s : duplicate getter %s", name, gt.name())
+		}
+
+		seen[gt.name()] = true
+		g.writeGetter(w, name, gt)
+	}
+
+	return nil
+}
+
+// collect appends to getters those of the fields of st, reached through path. optional
+// reports whether path goes through a presence or pointer field, visiting holds the
+// struct types on the path.
+func collect(st *types.Struct, path []step, optional bool, visiting map[types.Type]bool, getters *[]getter) {
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		s := step{name: f.Name(), kind: stepStruct}
+		nested := f.Type()
+
+		if elem, ok := presenceElem(f.Type()); ok {
+			s.kind, nested = stepPresence, elem
+			_, s.nilable = elem.Underlying().(*types.Pointer)
+			*getters = append(*getters, getter{path: append(slices.Clone(path), s), result: elem})
+		} else if ptr, ok := f.Type().Underlying().(*types.Pointer); ok && structOf(ptr.Elem()) != nil {
+			s.kind = stepPointer
+		} else if structOf(f.Type()) == nil && optional {
+			*getters = append(*getters, getter{path: append(slices.Clone(path), s), result: f.Type()})
+		}
+
+		if ptr, ok := nested.Underlying().(*types.Pointer); ok {
+			nested = ptr.Elem()
+		}
+
+		inner := structOf(nested)
+		if inner == nil || visiting[nested] {
+			continue
+		}
+
+		visiting[nested] = true
+		collect(inner, append(slices.Clone(path), s), optional || s.kind != stepStruct, visiting, getters)
+		delete(visiting, nested)
+	}
+}
+
+// structOf returns the struct type of typ when it has exported fields to traverse,
+// nil otherwise.
+func structOf(typ types.Type) *types.Struct {
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i := range st.NumFields() {
+		if st.Field(i).Exported() {
+			return st
+		}
+	}
+
+	return nil
+}
+
+// writeGetter writes the getter gt of the type name.
+func (g *generator) writeGetter(w *bytes.Buffer, name string, gt getter) {
+	names := make([]string, len(gt.path))
+	for i, s := range gt.path {
+		names[i] = s.name
+	}
+
+	fmt.Fprintf(w, "\n// %s returns %s, false when it or a field on its path is nil, null or unset.\n",
+		gt.name(), strings.Join(names, "."))
+	fmt.Fprintf(w, "func (v *%s) %s() (%s, bool) {\n", name, gt.name(), g.TypeString(gt.result))
+	fmt.Fprintf(w, "\tvar zero %s\n\tif v == nil {\n\t\treturn zero, false\n\t}\n\n", g.TypeString(gt.result))
+
+	current := "v"
+	for i, s := range gt.path[:len(gt.path)-1] {
+		field := current + "." + s.name
+
+		switch s.kind {
+		case stepStruct:
+			current = field
+		case stepPointer:
+			fmt.Fprintf(w, "\tif %s == nil {\n\t\treturn zero, false\n\t}\n\n", field)
+			current = field
+		case stepPresence:
+			current = fmt.Sprintf("x%d", i)
+			fmt.Fprintf(w, "\t%s, ok := %s.Get()\n", current, field)
+			if s.nilable {
+				fmt.Fprintf(w, "\tif !ok || %s == nil {\n\t\treturn zero, false\n\t}\n\n", current)
+			} else {
+				fmt.Fprintf(w, "\tif !ok {\n\t\treturn zero, false\n\t}\n\n")
+			}
+		}
+	}
+
+	leaf := gt.path[len(gt.path)-1]
+	if leaf.kind == stepPresence {
+		fmt.Fprintf(w, "\treturn %s.%s.Get()\n}\n", current, leaf.name)
+	} else {
+		fmt.Fprintf(w, "\treturn %s.%s, true\n}\n", current, leaf.name)
+	}
+}
+
+// presenceElem reports whether typ is presence.Of[T] or a type only embedding it, like
+// presence.String, returning T.
+func presenceElem(typ types.Type) (types.Type, bool) {
+	if elem, ok := gen.PresenceOfElem(typ); ok {
+		return elem, true
+	}
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok || st.NumFields() != 1 || !st.Field(0).Embedded() {
+		return nil, false
+	}
+
+	return gen.PresenceOfElem(st.Field(0).Type())
+}
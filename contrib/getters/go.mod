@@ -0,0 +1,13 @@
+module github.com/pivaldi/presence/contrib/getters
+
+go 1.25.0
+
+require github.com/pivaldi/presence/contrib/internal v0.0.0
+
+require (
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+	golang.org/x/tools v0.47.0 // indirect
+)
+
+replace github.com/pivaldi/presence/contrib/internal => ../internal
@@ -0,0 +1,20 @@
+/*
+Package presencegofakeit fills the presence fields of structs with random data from
+[github.com/brianvoe/gofakeit], across the three states, so that property-style tests
+exercise the null and unset paths that hand-written fixtures never hit:
+
+	faker := presencegofakeit.New(gofakeit.New(42), presencegofakeit.WithRatios(1, 1, 2))
+
+	var req PatchUserRequest
+	if err := faker.Struct(&req); err != nil {
+		t.Fatal(err)
+	}
+	// req.Email is unset a quarter of the time, null a quarter of the time, else a
+	// random email when tagged fake:"{email}".
+
+Plain fields are filled by gofakeit as its Struct function does, fake tags included.
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencegofakeit
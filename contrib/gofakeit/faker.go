@@ -0,0 +1,229 @@
+package presencegofakeit
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/pivaldi/presence"
+)
+
+// maxSliceLen is the maximum length of the random slices of structs with presence fields.
+const maxSliceLen = 10
+
+// field is implemented by the pointers to presence values.
+type field interface {
+	State() presence.State
+	SetNull()
+	Unset()
+}
+
+var fieldType = reflect.TypeFor[field]()
+
+// Faker fills structs with random data, their presence fields being unset, null or
+// holding a value according to its ratios.
+type Faker struct {
+	faker *gofakeit.Faker
+	unset float64
+	null  float64
+	value float64
+}
+
+// Option configures a Faker.
+type Option func(*Faker)
+
+// WithRatios sets the relative frequencies of the unset, null and value states, 1:1:1 by
+// default: WithRatios(0, 1, 3) never leaves fields unset and gives null a quarter of the
+// time. Negative ratios count as 0.
+func WithRatios(unset, null, value float64) Option {
+	return func(f *Faker) {
+		f.unset, f.null, f.value = max(unset, 0), max(null, 0), max(value, 0)
+	}
+}
+
+// New returns a Faker drawing its random data from faker, gofakeit.GlobalFaker when nil.
+func New(faker *gofakeit.Faker, opts ...Option) *Faker {
+	if faker == nil {
+		faker = gofakeit.GlobalFaker
+	}
+
+	f := &Faker{faker: faker, unset: 1, null: 1, value: 1}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// Struct fills the struct pointed to by v: presence fields, including those of nested
+// structs, pointers and slices, get a random state, their values being generated from
+// their fake tags; the other fields are filled by gofakeit. Fields tagged fake:"skip"
+// are left untouched.
+func (f *Faker) Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presencegofakeit expected a pointer to a struct, got %T", v)
+	}
+
+	return f.fillStruct(rv.Elem(), 0)
+}
+
+// State returns a random state according to the ratios of f.
+func (f *Faker) State() presence.State {
+	total := f.unset + f.null + f.value
+	if total == 0 {
+		return presence.StateValue
+	}
+
+	x := f.faker.Float64() * total
+	if x < f.unset {
+		return presence.StateUnset
+	}
+
+	if x < f.unset+f.null {
+		return presence.StateNull
+	}
+
+	return presence.StateValue
+}
+
+// Of returns a presence value in a random state, its value being generated by gofakeit
+// from the fake tag tag, "" for none, e.g. presencegofakeit.Of[string](faker, "{email}").
+func Of[T any](f *Faker, tag string) (presence.Of[T], error) {
+	var n presence.Of[T]
+
+	err := f.fillField(reflect.ValueOf(&n).Elem(), reflect.StructTag(`fake:"`+tag+`"`), 0)
+
+	return n, err
+}
+
+// fillStruct fills the fields of the addressable struct rv, nested depth levels deep.
+func (f *Faker) fillStruct(rv reflect.Value, depth int) error {
+	for i := range rv.NumField() {
+		sf := rv.Type().Field(i)
+		if !sf.IsExported() || sf.Tag.Get("fake") == "skip" || sf.Tag.Get("fake") == "-" {
+			continue
+		}
+
+		err := f.fill(rv.Field(i), sf.Tag, depth+1)
+		if err != nil {
+			return fmt.Errorf("presencegofakeit field %s : %w", sf.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// fill fills the settable value rv, tagged tag, nested depth levels deep. Like gofakeit,
+// it stops at gofakeit.RecursiveDepth levels, leaving the deeper values zero.
+func (f *Faker) fill(rv reflect.Value, tag reflect.StructTag, depth int) error {
+	if depth >= gofakeit.RecursiveDepth {
+		return nil
+	}
+
+	typ := rv.Type()
+	if reflect.PointerTo(typ).Implements(fieldType) {
+		return f.fillField(rv, tag, depth)
+	}
+
+	if !hasPresence(typ, map[reflect.Type]bool{}) {
+		return f.fake(rv, tag)
+	}
+
+	if typ.Kind() == reflect.Struct {
+		return f.fillStruct(rv, depth)
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		rv.Set(reflect.New(typ.Elem()))
+
+		return f.fill(rv.Elem(), tag, depth+1)
+	}
+
+	if typ.Kind() == reflect.Slice {
+		n := f.faker.Number(1, maxSliceLen)
+		rv.Set(reflect.MakeSlice(typ, n, n))
+	}
+
+	for i := range rv.Len() {
+		err := f.fill(rv.Index(i), tag, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillField sets the presence value rv to a random state, generating its value from
+// tag.
+func (f *Faker) fillField(rv reflect.Value, tag reflect.StructTag, depth int) error {
+	pf := rv.Addr().Interface().(field)
+
+	switch f.State() {
+	case presence.StateUnset:
+		pf.Unset()
+	case presence.StateNull:
+		pf.SetNull()
+	case presence.StateValue:
+		setValue := rv.Addr().MethodByName("SetValue")
+		if !setValue.IsValid() {
+			return errors.New("presencegofakeit : presence value without SetValue method")
+		}
+
+		value := reflect.New(setValue.Type().In(0)).Elem()
+
+		err := f.fill(value, tag, depth+1)
+		if err != nil {
+			return err
+		}
+
+		setValue.Call([]reflect.Value{value})
+	}
+
+	return nil
+}
+
+// fake fills the settable value rv, tagged tag, with gofakeit, through a single field
+// struct carrying the tag.
+func (f *Faker) fake(rv reflect.Value, tag reflect.StructTag) error {
+	holder := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "V", Type: rv.Type(), Tag: tag}}))
+
+	err := f.faker.Struct(holder.Interface())
+	if err != nil {
+		return fmt.Errorf("presencegofakeit faking %s : %w", rv.Type(), err)
+	}
+
+	rv.Set(holder.Elem().Field(0))
+
+	return nil
+}
+
+// hasPresence reports whether the type typ holds presence values, through struct
+// fields, pointers, slices and arrays.
+func hasPresence(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if reflect.PointerTo(typ).Implements(fieldType) {
+		return true
+	}
+
+	if seen[typ] {
+		return false
+	}
+
+	seen[typ] = true
+
+	if typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		return hasPresence(typ.Elem(), seen)
+	}
+
+	if typ.Kind() == reflect.Struct {
+		for i := range typ.NumField() {
+			if typ.Field(i).IsExported() && hasPresence(typ.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
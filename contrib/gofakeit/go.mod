@@ -0,0 +1,12 @@
+module github.com/pivaldi/presence/contrib/gofakeit
+
+go 1.25.0
+
+require (
+	github.com/brianvoe/gofakeit/v7 v7.17.1
+	github.com/pivaldi/presence v0.0.0
+)
+
+require github.com/google/uuid v1.6.0 // indirect
+
+replace github.com/pivaldi/presence => ../..
@@ -0,0 +1,113 @@
+package presencegorm
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// UpdateBelongsTo updates the model of db with the set presence fields of patch, like
+// Updates(patch), applying their foreign keys to the belongs-to
+// associations of the model:
+//
+//	type Post struct {
+//		ID       int64
+//		AuthorID presence.Of[uuid.UUID]
+//		Author   *User
+//	}
+//
+//	type PostPatch struct {
+//		AuthorID presence.Of[uuid.UUID]
+//	}
+//
+//	err := presencegorm.UpdateBelongsTo(db.Model(&post), patch).Error
+//
+// A null foreign key sets the column to NULL and clears the association of the model, an
+// unset one leaves both untouched, and a value replaces the association loaded for
+// another row. Associations are omitted from the update, so that gorm does not save them
+// back and restore the foreign keys they hold. Nothing is updated when patch sets no
+// field. The columns are named like by ToMap, the fields of embedded structs included.
+func UpdateBelongsTo(db *gorm.DB, patch any) *gorm.DB {
+	updates, err := ToMap(db, patch)
+	if err != nil {
+		_ = db.AddError(err)
+
+		return db
+	}
+
+	if len(updates) == 0 {
+		return db
+	}
+
+	err = clearBelongsTo(db, updates)
+	if err != nil {
+		_ = db.AddError(err)
+
+		return db
+	}
+
+	return db.Omit(clause.Associations).Updates(updates)
+}
+
+// clearBelongsTo clears the belongs-to associations of the model of db whose foreign key
+// updates sets to null or to another row.
+func clearBelongsTo(db *gorm.DB, updates map[string]any) error {
+	model := reflect.ValueOf(db.Statement.Model)
+	if model.Kind() != reflect.Pointer || model.IsNil() || model.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+
+	err := stmt.Parse(db.Statement.Model)
+	if err != nil {
+		return fmt.Errorf("presence parsing the model : %w", err)
+	}
+
+	model = model.Elem()
+	for _, rel := range stmt.Schema.Relationships.BelongsTo {
+		if stale(db, rel, model, updates) {
+			err := rel.Field.Set(db.Statement.Context, model, reflect.Zero(rel.Field.FieldType).Interface())
+			if err != nil {
+				return fmt.Errorf("presence clearing the association %s : %w", rel.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// stale reports whether the association rel loaded in model no longer matches the
+// foreign keys set by updates.
+func stale(db *gorm.DB, rel *schema.Relationship, model reflect.Value, updates map[string]any) bool {
+	assoc, zero := rel.Field.ValueOf(db.Statement.Context, model)
+	if zero || assoc == nil {
+		return false
+	}
+
+	assocValue := reflect.Indirect(reflect.ValueOf(assoc))
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey || ref.PrimaryValue != "" {
+			continue
+		}
+
+		fk, ok := updates[ref.ForeignKey.DBName]
+		if !ok {
+			continue
+		}
+
+		if fk == nil {
+			return true
+		}
+
+		pk, _ := ref.PrimaryKey.ValueOf(db.Statement.Context, assocValue)
+		if !reflect.DeepEqual(pk, fk) {
+			return true
+		}
+	}
+
+	return false
+}
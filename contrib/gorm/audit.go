@@ -0,0 +1,115 @@
+package presencegorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivaldi/presence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotNullSuggestion is a column declared nullable which held no null in the rows sampled
+// by AuditNotNull.
+type NotNullSuggestion struct {
+	Table string
+	// Stats counts the null and non-null values of the column in the sample.
+	Stats presence.FieldStats
+	// SQL is the ALTER TABLE statement making the column NOT NULL, in the PostgreSQL
+	// syntax, quoted by the dialect of the database.
+	SQL string
+}
+
+// AuditNotNull samples the given tables, all the tables of the database when none is
+// given, and returns their nullable columns which held no null, candidates for a NOT
+// NULL migration:
+//
+//	suggestions, err := presencegorm.AuditNotNull(db, 10000, "users", "orders")
+//	for _, s := range suggestions {
+//		fmt.Println(s.SQL) // ALTER TABLE "users" ALTER COLUMN "email" SET NOT NULL;
+//	}
+//
+// The sample holds the first sample rows of each table, the whole table when sample is
+// not positive. Columns of empty samples are not suggested. A sample is not a proof:
+// review the suggestions, and mind the writers still inserting nulls.
+func AuditNotNull(db *gorm.DB, sample int, tables ...string) ([]NotNullSuggestion, error) {
+	if len(tables) == 0 {
+		var err error
+
+		tables, err = db.Migrator().GetTables()
+		if err != nil {
+			return nil, fmt.Errorf("presence listing tables : %w", err)
+		}
+	}
+
+	var suggestions []NotNullSuggestion
+
+	for _, table := range tables {
+		stats, err := nullableStats(db, table, sample)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range stats {
+			if s.Null > 0 || s.Value == 0 {
+				continue
+			}
+
+			suggestions = append(suggestions, NotNullSuggestion{
+				Table: table,
+				Stats: s,
+				SQL: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;",
+					db.Statement.Quote(clause.Table{Name: table}), db.Statement.Quote(clause.Column{Name: s.Field})),
+			})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// nullableStats counts the null and non-null values of the nullable columns of table in
+// its first sample rows.
+func nullableStats(db *gorm.DB, table string, sample int) ([]presence.FieldStats, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return nil, fmt.Errorf("presence reading the columns of %s : %w", table, err)
+	}
+
+	var columns []string
+
+	selects := []string{"COUNT(*)"}
+	for _, ct := range columnTypes {
+		if nullable, ok := ct.Nullable(); ok && nullable {
+			columns = append(columns, ct.Name())
+			selects = append(selects, "COUNT("+db.Statement.Quote(clause.Column{Name: ct.Name()})+")")
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	rows := db.Table(table)
+	if sample > 0 {
+		rows = rows.Limit(sample)
+	}
+
+	// counts holds the number of sampled rows, then the non-null values of each column.
+	counts := make([]int64, len(selects))
+	dests := make([]any, len(counts))
+	for i := range counts {
+		dests[i] = &counts[i]
+	}
+
+	err = db.Table("(?) AS sample", rows).Select(strings.Join(selects, ", ")).Row().Scan(dests...)
+	if err != nil {
+		return nil, fmt.Errorf("presence sampling %s : %w", table, err)
+	}
+
+	stats := make([]presence.FieldStats, len(columns))
+	for i, column := range columns {
+		stats[i] = presence.FieldStats{Field: column, Value: int(counts[i+1]), Null: int(counts[0] - counts[i+1])}
+	}
+
+	return stats, nil
+}
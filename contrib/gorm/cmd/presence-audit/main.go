@@ -0,0 +1,52 @@
+// Command presence-audit samples the tables of a PostgreSQL database and prints the
+// ALTER TABLE statements making NOT NULL the nullable columns which held no null, see
+// presencegorm.AuditNotNull.
+//
+//	presence-audit [-dsn postgres://...] [-sample 10000] [-tables users,orders]
+//
+// The DSN defaults to $DATABASE_URL and the tables to all the tables of the database.
+// The statements are suggestions, to review before running them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	presencegorm "github.com/pivaldi/presence/contrib/gorm"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// defaultSample is the default number of rows sampled per table.
+const defaultSample = 10000
+
+func main() {
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "PostgreSQL connection string")
+	sample := flag.Int("sample", defaultSample, "rows sampled per table, all when not positive")
+	tableList := flag.String("tables", "", "comma separated tables to audit, all when empty")
+	flag.Parse()
+
+	var tables []string
+	if *tableList != "" {
+		tables = strings.Split(*tableList, ",")
+	}
+
+	db, err := gorm.Open(postgres.Open(*dsn), &gorm.Config{Logger: logger.Discard})
+
+	var suggestions []presencegorm.NotNullSuggestion
+	if err == nil {
+		suggestions, err = presencegorm.AuditNotNull(db, *sample, tables...)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "presence-audit:", err)
+		os.Exit(1)
+	}
+
+	for _, s := range suggestions {
+		fmt.Printf("-- %s.%s: no null in %d sampled rows\n%s\n", s.Table, s.Stats.Field, s.Stats.Total(), s.SQL)
+	}
+}
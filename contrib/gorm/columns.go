@@ -0,0 +1,156 @@
+package presencegorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pivaldi/presence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// valuer is implemented by *presence.Of[T] and the types embedding it.
+type valuer interface {
+	State() presence.State
+	Any() any
+}
+
+// ToMap returns the set presence fields of v keyed by their columns as gorm maps them:
+// values map to themselves, nulls to nil and unset fields are left out, like
+// presence.ToMap. The fields of the structs tagged gorm:"embedded" are included with
+// their embeddedPrefix, so nested models need no manual flattening:
+//
+//	type AddressPatch struct {
+//		City presence.Of[string]
+//		Zip  presence.Of[string]
+//	}
+//
+//	type UserPatch struct {
+//		Name    presence.Of[string]
+//		Address AddressPatch `gorm:"embedded;embeddedPrefix:addr_"`
+//	}
+//
+//	m, err := presencegorm.ToMap(db, patch) // {"name": "Ada", "addr_city": nil}
+//
+// The map suits both Updates and Where, gorm building `column IS NULL` for nil. Fields
+// tagged presence:"apionly" are left out. The update builders of this package name the
+// columns the same way.
+func ToMap(db *gorm.DB, v any) (map[string]any, error) {
+	sch, value, err := parseStruct(db, v)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{}
+	for _, field := range sch.Fields {
+		if field.DBName == "" || hasOption(field, "apionly") {
+			continue
+		}
+
+		p, ok := presenceField(db, field, value)
+		if ok && p.State() != presence.StateUnset {
+			out[field.DBName] = p.Any()
+		}
+	}
+
+	return out, nil
+}
+
+// Updates updates the model of db with the set presence fields of patch keyed by ToMap,
+// the fields of its embedded structs included:
+//
+//	err := presencegorm.Updates(db.Model(&User{ID: id}), patch).Error
+//
+// Nothing is updated when patch sets no field.
+func Updates(db *gorm.DB, patch any) *gorm.DB {
+	updates, err := ToMap(db, patch)
+	if err != nil {
+		_ = db.AddError(err)
+
+		return db
+	}
+
+	if len(updates) == 0 {
+		return db
+	}
+
+	return db.Updates(updates)
+}
+
+// FieldGenType returns the typed gen field (String, Int64, Time…) of the presence.Of[T]
+// field f, "" for other fields. It implements the GetFieldGenType method with which the
+// models given to (*gen.Generator).ApplyBasic type their query fields, the columns of
+// their embedded structs included:
+//
+//	func (User) GetFieldGenType(f *schema.Field) string {
+//		return presencegorm.FieldGenType(f)
+//	}
+//
+// It is the GenTypes counterpart for models written by hand.
+func FieldGenType(f *schema.Field) string {
+	typ := f.FieldType
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	// Named types such as presence.String embed their presence.Of[T].
+	if typ.Kind() == reflect.Struct && typ.NumField() == 1 && typ.Field(0).Anonymous {
+		typ = typ.Field(0).Type
+	}
+
+	base, ok := baseType(typ.String())
+	if !ok {
+		return ""
+	}
+
+	return genTypeOf(base)
+}
+
+// parseStruct returns the schema of the struct v and the struct itself.
+func parseStruct(db *gorm.DB, v any) (*schema.Schema, reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, reflect.Value{}, fmt.Errorf("presence expected a struct, got %T", v)
+	}
+
+	sch, err := schema.Parse(v, &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil, reflect.Value{}, fmt.Errorf("presence parsing %T : %w", v, err)
+	}
+
+	return sch, value, nil
+}
+
+// presenceField returns the presence value of field in the struct value, false when the
+// field is not a presence field or when the embedded struct pointer holding it is nil.
+func presenceField(db *gorm.DB, field *schema.Field, value reflect.Value) (valuer, bool) {
+	fv, zero := field.ValueOf(db.Statement.Context, value)
+	if zero && fv == nil {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(fv)
+	ptr := reflect.New(rv.Type())
+	ptr.Elem().Set(rv)
+
+	// State has a pointer receiver, Any a value one.
+	p, ok := ptr.Interface().(valuer)
+
+	return p, ok
+}
+
+// hasOption reports whether the presence tag of f holds option, e.g. presence:"apionly".
+func hasOption(f *schema.Field, option string) bool {
+	for o := range strings.SplitSeq(f.Tag.Get("presence"), ",") {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package presencegorm
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DeletedAt is a soft-delete column behaving like gorm.DeletedAt while keeping the three
+// presence states: queries are scoped to the rows where it is NULL, Delete sets it to the
+// current time, and an unset DeletedAt is omitted from JSON with the omitzero option.
+//
+//	type User struct {
+//		ID        int64
+//		DeletedAt presencegorm.DeletedAt `json:"deleted_at,omitzero"`
+//	}
+type DeletedAt struct {
+	presence.Of[time.Time]
+}
+
+// notDeleted is the soft-delete zero value, an invalid NullString renders as IS NULL.
+var notDeleted = sql.NullString{}
+
+// QueryClauses implements schema.QueryClausesInterface.
+func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.SoftDeleteQueryClause{Field: f, ZeroValue: notDeleted}}
+}
+
+// UpdateClauses implements schema.UpdateClausesInterface.
+func (DeletedAt) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.SoftDeleteUpdateClause{Field: f, ZeroValue: notDeleted}}
+}
+
+// DeleteClauses implements schema.DeleteClausesInterface.
+func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.SoftDeleteDeleteClause{Field: f, ZeroValue: notDeleted}}
+}
@@ -0,0 +1,7 @@
+/*
+Package presencegorm integrates presence values with [gorm.io/gorm] and [gorm.io/gen].
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencegorm
@@ -0,0 +1,88 @@
+package presencegorm
+
+import (
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"github.com/pivaldi/presence"
+)
+
+// comparableField is implemented by the typed gen fields (field.String, field.Int64, field.Time…).
+type comparableField[T any] interface {
+	Eq(value T) field.Expr
+	Neq(value T) field.Expr
+	IsNull() field.Expr
+	IsNotNull() field.Expr
+}
+
+// Eq builds `column = value` for a value and `column IS NULL` for null.
+// It returns nil for unset values so that the condition is dropped by Conds.
+func Eq[T any](f comparableField[T], v presence.Of[T]) field.Expr {
+	switch v.State() {
+	case presence.StateUnset:
+		return nil
+	case presence.StateNull:
+		return f.IsNull()
+	case presence.StateValue:
+	}
+
+	return f.Eq(v.MustGet())
+}
+
+// Neq builds `column <> value` for a value and `column IS NOT NULL` for null.
+// It returns nil for unset values so that the condition is dropped by Conds.
+func Neq[T any](f comparableField[T], v presence.Of[T]) field.Expr {
+	switch v.State() {
+	case presence.StateUnset:
+		return nil
+	case presence.StateNull:
+		return f.IsNotNull()
+	case presence.StateValue:
+	}
+
+	return f.Neq(v.MustGet())
+}
+
+// Is is the field.Bool counterpart of Eq.
+func Is(f field.Bool, v presence.Of[bool]) field.Expr {
+	switch v.State() {
+	case presence.StateUnset:
+		return nil
+	case presence.StateNull:
+		return f.IsNull()
+	case presence.StateValue:
+	}
+
+	return f.Is(v.MustGet())
+}
+
+// EqField is the Eq counterpart for the generic field.Field generated for
+// presence columns when GenTypes is not used.
+func EqField[T any](f field.Field, v presence.Of[T]) field.Expr {
+	switch v.State() {
+	case presence.StateUnset:
+		return nil
+	case presence.StateNull:
+		return f.IsNull()
+	case presence.StateValue:
+	}
+
+	return f.Eq(v)
+}
+
+// Conds returns the non nil expressions as gen conditions, ready for Where:
+//
+//	q.User.Where(presencegorm.Conds(
+//		presencegorm.Eq(q.User.Email, filter.Email),
+//		presencegorm.Eq(q.User.Age, filter.Age),
+//	)...)
+func Conds(exprs ...field.Expr) []gen.Condition {
+	out := make([]gen.Condition, 0, len(exprs))
+	for _, e := range exprs {
+		if e != nil {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
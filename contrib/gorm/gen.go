@@ -0,0 +1,79 @@
+package presencegorm
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+const (
+	presenceTypePrefix = "presence.Of["
+	presenceTypeSuffix = "]"
+)
+
+// NullableType returns a gen data type mapping function wrapping nullable
+// columns with presence.Of[baseType]. Non nullable columns keep baseType.
+// It is meant to be used with (*gen.Generator).WithDataTypeMap.
+func NullableType(baseType string) func(gorm.ColumnType) string {
+	return func(c gorm.ColumnType) string {
+		if nullable, _ := c.Nullable(); nullable {
+			return presenceType(baseType)
+		}
+
+		return baseType
+	}
+}
+
+// FieldType forces the generated type of columnName to presence.Of[baseType].
+func FieldType(columnName, baseType string) gen.ModelOpt {
+	return gen.FieldType(columnName, presenceType(baseType))
+}
+
+// GenTypes makes the query DSL of presence.Of[T] fields use the typed gen field
+// (field.String, field.Int64, field.Time…) of T instead of the generic field.Field,
+// so that conditions can be built with Eq, Neq and Is.
+func GenTypes() gen.ModelOpt {
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		base, ok := baseType(f.Type)
+		if !ok || f.CustomGenType != "" {
+			return f
+		}
+
+		if genType := genTypeOf(base); genType != "" {
+			f.CustomGenType = genType
+		}
+
+		return f
+	})
+}
+
+func presenceType(baseType string) string {
+	return fmt.Sprintf("%s%s%s", presenceTypePrefix, baseType, presenceTypeSuffix)
+}
+
+// baseType returns T from the "presence.Of[T]" type name.
+func baseType(typ string) (string, bool) {
+	typ = strings.TrimLeft(typ, "*")
+	if !strings.HasPrefix(typ, presenceTypePrefix) || !strings.HasSuffix(typ, presenceTypeSuffix) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(typ, presenceTypePrefix), presenceTypeSuffix), true
+}
+
+// genTypeOf mirrors the gen type resolution of gorm.io/gen for the base types.
+func genTypeOf(typ string) string {
+	switch typ {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return strings.ToUpper(typ[:1]) + typ[1:]
+	case "time.Time":
+		return "Time"
+	case "[]byte", "json.RawMessage":
+		return "Bytes"
+	}
+
+	return ""
+}
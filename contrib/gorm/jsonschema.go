@@ -0,0 +1,70 @@
+package presencegorm
+
+import (
+	"strings"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+)
+
+// jsonSchemaTagKey is the struct tag read by github.com/invopop/jsonschema.
+const jsonSchemaTagKey = "jsonschema"
+
+// JSONSchemaTags adds a jsonschema struct tag (github.com/invopop/jsonschema syntax) to the
+// generated fields, derived from the column metadata, so that the schema reflected from the
+// models validates payloads the way the database does:
+//   - "required" for NOT NULL columns without default, which inserts must provide;
+//   - "nullable" for nullable columns;
+//   - "type=…" and "format=…" for presence.Of[T] fields, whose Go type would reflect as an object.
+func JSONSchemaTags() gen.ModelOpt {
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		if tag := jsonSchemaTag(f); tag != "" {
+			f.Tag.Set(jsonSchemaTagKey, tag)
+		}
+
+		return f
+	})
+}
+
+// jsonSchemaTag returns the jsonschema tag value describing f.
+func jsonSchemaTag(f gen.Field) string {
+	var keywords []string
+
+	if base, ok := baseType(f.Type); ok {
+		keywords = append(keywords, jsonSchemaTypeOf(base)...)
+	}
+
+	_, notNull := f.GORMTag[field.TagKeyGormNotNull]
+	_, primaryKey := f.GORMTag[field.TagKeyGormPrimaryKey]
+	_, hasDefault := f.GORMTag[field.TagKeyGormDefault]
+
+	switch {
+	case primaryKey:
+	case !notNull:
+		keywords = append(keywords, "nullable")
+	case !hasDefault:
+		keywords = append(keywords, "required")
+	}
+
+	return strings.Join(keywords, ",")
+}
+
+// jsonSchemaTypeOf returns the JSON schema type keywords of the Go type typ.
+func jsonSchemaTypeOf(typ string) []string {
+	switch typ {
+	case "string":
+		return []string{"type=string"}
+	case "bool":
+		return []string{"type=boolean"}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return []string{"type=integer"}
+	case "float32", "float64":
+		return []string{"type=number"}
+	case "time.Time":
+		return []string{"type=string", "format=date-time"}
+	case "uuid.UUID":
+		return []string{"type=string", "format=uuid"}
+	}
+
+	return nil
+}
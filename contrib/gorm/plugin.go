@@ -0,0 +1,104 @@
+package presencegorm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// unsetter is implemented by *presence.Of[T] and the types embedding it.
+type unsetter interface {
+	IsUnset() bool
+}
+
+var (
+	unsetterType = reflect.TypeFor[unsetter]()
+	// schemaCache holds the schemas of the update destinations which are not the statement model.
+	schemaCache sync.Map
+)
+
+// OmitUnset is a gorm plugin removing unset presence fields from the UPDATE and
+// INSERT statements built from structs. A full struct Save then only writes the
+// fields the caller touched instead of nulling every other column.
+//
+//	db.Use(presencegorm.OmitUnset{})
+type OmitUnset struct{}
+
+// Name implements gorm.Plugin.
+func (OmitUnset) Name() string {
+	return "presence:omit_unset"
+}
+
+// Initialize implements gorm.Plugin.
+func (p OmitUnset) Initialize(db *gorm.DB) error {
+	err := db.Callback().Update().Before("gorm:update").Register(p.Name(), omitUnsetFields)
+	if err != nil {
+		return fmt.Errorf("presence registering update callback : %w", err)
+	}
+
+	// Save falls back to an upsert when the update did not touch any row.
+	err = db.Callback().Create().Before("gorm:create").Register(p.Name(), omitUnsetFields)
+	if err != nil {
+		return fmt.Errorf("presence registering create callback : %w", err)
+	}
+
+	return nil
+}
+
+// omitUnsetFields adds the columns of the unset presence fields of the
+// statement destination to the omitted columns.
+func omitUnsetFields(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.Dest == nil {
+		return
+	}
+
+	value := reflect.ValueOf(stmt.Dest)
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		// Maps only hold explicit assignments, slices need uniform columns.
+		return
+	}
+
+	sch := stmt.Schema
+	if value.Type() != sch.ModelType {
+		var err error
+		sch, err = schema.Parse(stmt.Dest, &schemaCache, db.NamingStrategy)
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("presence parsing update destination : %w", err))
+
+			return
+		}
+	}
+
+	for _, field := range sch.Fields {
+		if field.DBName == "" || !isUnset(field.ReflectValueOf(stmt.Context, value)) {
+			continue
+		}
+
+		stmt.Omits = append(stmt.Omits, field.DBName)
+	}
+}
+
+// isUnset reports whether v holds an unset presence value.
+func isUnset(v reflect.Value) bool {
+	if !v.IsValid() || !reflect.PointerTo(v.Type()).Implements(unsetterType) {
+		return false
+	}
+
+	if !v.CanAddr() {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr.Elem()
+	}
+
+	u, ok := v.Addr().Interface().(unsetter)
+
+	return ok && u.IsUnset()
+}
@@ -0,0 +1,30 @@
+package presencegorm
+
+import (
+	"maps"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ToAssignmentColumns returns the DoUpdates set of a GORM upsert updating, on conflict,
+// the columns of the set presence fields of v from the inserted row, so that the
+// upsert leaves the columns of the unset fields untouched:
+//
+//	set, err := presencegorm.ToAssignmentColumns(db, user)
+//	err = db.Clauses(clause.OnConflict{
+//		Columns:   []clause.Column{{Name: "email"}},
+//		DoUpdates: set,
+//	}).Create(&user).Error
+//
+// Null fields are set NULL. The columns are named like by ToMap, the fields of embedded
+// structs included.
+func ToAssignmentColumns(db *gorm.DB, v any) (clause.Set, error) {
+	updates, err := ToMap(db, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return clause.AssignmentColumns(slices.Sorted(maps.Keys(updates))), nil
+}
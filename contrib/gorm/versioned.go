@@ -0,0 +1,73 @@
+package presencegorm
+
+import (
+	"fmt"
+
+	"github.com/pivaldi/presence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateVersioned updates the row selected by db with the set presence fields of patch,
+// guarded by its version field (see presence.VersionedUpdate), and increments the
+// version column. The error of the returned *gorm.DB is presence.ErrConflict when no
+// row matches the version.
+//
+//	err := presencegorm.UpdateVersioned(db.Model(&User{ID: id}), patch).Error
+//
+// The columns are named like by ToMap, the fields of embedded structs included.
+func UpdateVersioned(db *gorm.DB, patch any) *gorm.DB {
+	version, expected, err := versionOf(db, patch)
+	if err != nil {
+		_ = db.AddError(err)
+
+		return db
+	}
+
+	updates, err := ToMap(db, patch)
+	if err != nil {
+		_ = db.AddError(err)
+
+		return db
+	}
+
+	updates[version] = gorm.Expr("? + 1", clause.Column{Name: version})
+
+	tx := db.Where(clause.Eq{Column: clause.Column{Name: version}, Value: expected}).Updates(updates)
+	if tx.Error == nil && !tx.DryRun && tx.RowsAffected == 0 {
+		_ = tx.AddError(presence.ErrConflict)
+	}
+
+	return tx
+}
+
+// versionOf returns the column of the field of patch tagged presence:"version" and the
+// version it holds.
+func versionOf(db *gorm.DB, patch any) (string, any, error) {
+	sch, value, err := parseStruct(db, patch)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, field := range sch.Fields {
+		if field.DBName == "" || !hasOption(field, "version") {
+			continue
+		}
+
+		p, ok := presenceField(db, field, value)
+		if !ok {
+			v, _ := field.ValueOf(db.Statement.Context, value)
+
+			return field.DBName, v, nil
+		}
+
+		if p.State() != presence.StateValue {
+			return "", nil, fmt.Errorf("presence versioned update : version field %s has no value", field.Name)
+		}
+
+		return field.DBName, p.Any(), nil
+	}
+
+	return "", nil, fmt.Errorf("presence versioned update : %s has no field tagged presence:\"version\"",
+		sch.ModelType)
+}
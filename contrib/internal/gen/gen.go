@@ -0,0 +1,179 @@
+// Package gen holds the scaffolding shared by the presence code generators: package
+// loading, annotation lookup, import tracking and presence type inspection.
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// PresencePkgPath is the import path of the presence package.
+const PresencePkgPath = "github.com/pivaldi/presence"
+
+// ErrNoTypes is returned by the generators when no struct type is annotated nor named.
+var ErrNoTypes = errors.New("presence : no struct type to generate")
+
+// LoadPackage loads the package in dir with its syntax and types.
+func LoadPackage(dir string) (*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax,
+		Dir:  dir,
+	}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("loading %s : %w", dir, err)
+	}
+
+	if len(pkgs) != 1 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("loading %s : no package", dir)
+	}
+
+	return pkgs[0], nil
+}
+
+// Annotated is a type whose declaration has an annotation.
+type Annotated struct {
+	// Name is the name of the type.
+	Name string
+	// Arg is the text following the annotation, e.g. the entity of //presence:mapper.
+	Arg string
+}
+
+// AnnotatedTypes returns the types of files whose declaration has a comment holding
+// annotation, alone or followed by an argument.
+func AnnotatedTypes(files []*ast.File, annotation string) []Annotated {
+	var annotated []Annotated
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+
+				arg, found := annotationArg(ts.Doc, annotation)
+				if !found && len(gen.Specs) == 1 {
+					arg, found = annotationArg(gen.Doc, annotation)
+				}
+
+				if found {
+					annotated = append(annotated, Annotated{Name: ts.Name.Name, Arg: arg})
+				}
+			}
+		}
+	}
+
+	return annotated
+}
+
+// annotationArg returns the argument of the annotation of doc.
+func annotationArg(doc *ast.CommentGroup, annotation string) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+
+	for _, c := range doc.List {
+		text := strings.TrimSpace(c.Text)
+		if text == annotation {
+			return "", true
+		}
+
+		if arg, ok := strings.CutPrefix(text, annotation+" "); ok {
+			return strings.TrimSpace(arg), true
+		}
+	}
+
+	return "", false
+}
+
+// Imports records the imports of the types written in the code generated for the
+// package pkg.
+type Imports struct {
+	pkg   *types.Package
+	paths map[string]bool
+}
+
+// NewImports returns the Imports of the code generated for pkg.
+func NewImports(pkg *types.Package) *Imports {
+	return &Imports{pkg: pkg, paths: map[string]bool{}}
+}
+
+// Import records the import of path.
+func (im *Imports) Import(path string) {
+	im.paths[path] = true
+}
+
+// Qualifier names the packages of the types written, importing them.
+func (im *Imports) Qualifier(p *types.Package) string {
+	if p == im.pkg {
+		return ""
+	}
+
+	im.Import(p.Path())
+
+	return p.Name()
+}
+
+// TypeString returns the Go expression of typ.
+func (im *Imports) TypeString(typ types.Type) string {
+	return types.TypeString(typ, im.Qualifier)
+}
+
+// WriteDecl writes the import declaration of the recorded imports, nothing if none.
+func (im *Imports) WriteDecl(w *bytes.Buffer) {
+	if len(im.paths) == 0 {
+		return
+	}
+
+	w.WriteString("import (\n")
+	for _, path := range slices.Sorted(maps.Keys(im.paths)) {
+		fmt.Fprintf(w, "\t%q\n", path)
+	}
+
+	w.WriteString(")\n")
+}
+
+// Write formats the generated source src and writes it to w.
+func Write(w io.Writer, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("formatting : %w", err)
+	}
+
+	_, err = w.Write(formatted)
+	if err != nil {
+		return fmt.Errorf("writing : %w", err)
+	}
+
+	return nil
+}
+
+// PresenceOfElem returns T when typ is presence.Of[T].
+func PresenceOfElem(typ types.Type) (types.Type, bool) {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return nil, false
+	}
+
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != PresencePkgPath || obj.Name() != "Of" || named.TypeArgs().Len() != 1 {
+		return nil, false
+	}
+
+	return named.TypeArgs().At(0), true
+}
@@ -0,0 +1,11 @@
+module github.com/pivaldi/presence/contrib/internal
+
+go 1.25.0
+
+require golang.org/x/tools v0.47.0
+
+require (
+	github.com/google/go-cmp v0.7.0 // indirect
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
@@ -0,0 +1,15 @@
+/*
+Package presencejsoniter integrates presence values with [github.com/json-iterator/go].
+
+json-iterator calls the MarshalJSON and UnmarshalJSON methods of presence values, so
+null and unset values round-trip, but it ignores the omitzero option that skips unset
+fields configured with presence.UnsetSkip. Register installs an extension honoring it:
+
+	presencejsoniter.Register()
+	json := jsoniter.ConfigCompatibleWithStandardLibrary
+	b, err := json.Marshal(patch) // unset `json:",omitzero"` fields are skipped
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencejsoniter
@@ -0,0 +1,13 @@
+module github.com/pivaldi/presence/contrib/jsoniter
+
+go 1.25.0
+
+require (
+	github.com/json-iterator/go v1.1.12
+	github.com/modern-go/reflect2 v1.0.2
+)
+
+require (
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/stretchr/testify v1.11.1 // indirect
+)
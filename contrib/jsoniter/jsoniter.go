@@ -0,0 +1,113 @@
+package presencejsoniter
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// zeroer is implemented by the presence values, whose IsZero reports the unset values
+// to skip.
+type zeroer interface {
+	IsZero() bool
+}
+
+var zeroerType = reflect.TypeFor[zeroer]()
+
+// Extension makes json-iterator skip the fields tagged omitzero whose IsZero method
+// reports true, as encoding/json does since Go 1.24. Nil pointers count as zero.
+type Extension struct {
+	jsoniter.DummyExtension
+}
+
+// Register registers the Extension for all the json-iterator configurations.
+func Register() {
+	jsoniter.RegisterExtension(&Extension{})
+}
+
+// RegisterTo registers the Extension for the api configuration only.
+func RegisterTo(api jsoniter.API) {
+	api.RegisterExtension(&Extension{})
+}
+
+// UpdateStructDescriptor turns omitzero into omitempty for the fields having an IsZero
+// method, json-iterator then asking the encoder whether the field is empty.
+func (e *Extension) UpdateStructDescriptor(desc *jsoniter.StructDescriptor) {
+	for _, binding := range desc.Fields {
+		typ := binding.Field.Type().Type1()
+		if !hasZeroer(typ) {
+			continue
+		}
+
+		tag, ok := omitZeroTag(binding.Field.Tag())
+		if !ok {
+			continue
+		}
+
+		binding.Field = taggedField{StructField: binding.Field, tag: tag}
+		binding.Encoder = zeroEncoder{ValEncoder: binding.Encoder, typ: binding.Field.Type()}
+	}
+}
+
+// hasZeroer reports whether typ, or the type it points to, has an IsZero method.
+func hasZeroer(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return typ.Implements(zeroerType) || reflect.PointerTo(typ).Implements(zeroerType)
+}
+
+// omitZeroTag returns tag, with omitzero replaced by omitempty in the json key,
+// and whether it had omitzero.
+func omitZeroTag(tag reflect.StructTag) (reflect.StructTag, bool) {
+	value, ok := tag.Lookup("json")
+	if !ok {
+		return tag, false
+	}
+
+	parts := strings.Split(value, ",")
+	i := slices.Index(parts[1:], "omitzero")
+	if i < 0 {
+		return tag, false
+	}
+
+	parts[i+1] = "omitempty"
+	old, updated := `json:"`+value+`"`, `json:"`+strings.Join(parts, ",")+`"`
+
+	return reflect.StructTag(strings.Replace(string(tag), old, updated, 1)), true
+}
+
+// taggedField overrides the tag of a struct field.
+type taggedField struct {
+	reflect2.StructField
+	tag reflect.StructTag
+}
+
+func (f taggedField) Tag() reflect.StructTag {
+	return f.tag
+}
+
+// zeroEncoder reports the values whose IsZero method returns true as empty.
+type zeroEncoder struct {
+	jsoniter.ValEncoder
+	typ reflect2.Type
+}
+
+func (e zeroEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	v := e.typ.UnsafeIndirect(ptr)
+	if e.typ.Kind() == reflect.Pointer && reflect2.IsNil(v) {
+		return true
+	}
+
+	z, ok := v.(zeroer)
+	if !ok {
+		z, ok = e.typ.PackEFace(ptr).(zeroer)
+	}
+
+	return ok && z.IsZero()
+}
@@ -0,0 +1,22 @@
+/*
+Package presencejsonschema validates the JSON values of presence fields against a
+JSON Schema, compiled by [github.com/santhosh-tekuri/jsonschema/v6]:
+
+	type Settings map[string]any
+
+	err := presencejsonschema.Register[Settings]([]byte(`{
+		"type": "object",
+		"properties": {"theme": {"enum": ["light", "dark"]}},
+		"required": ["theme"]
+	}`))
+
+	var s presence.Of[Settings]
+	err = s.Scan(`{"theme": "pink"}`) // errors.Is(err, presencejsonschema.ErrViolation)
+
+The schema is registered with presence.RegisterValidator, so Scan and UnmarshalJSON
+reject the JSON column contents and payloads violating it.
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencejsonschema
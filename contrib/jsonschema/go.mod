@@ -0,0 +1,15 @@
+module github.com/pivaldi/presence/contrib/jsonschema
+
+go 1.25.0
+
+require (
+	github.com/pivaldi/presence v0.0.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+replace github.com/pivaldi/presence => ../..
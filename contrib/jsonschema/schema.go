@@ -0,0 +1,104 @@
+package presencejsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/pivaldi/presence"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrViolation is returned for the values violating a JSON Schema.
+var ErrViolation = errors.New("presence: JSON schema violation")
+
+// schemaURL names the compiled document, whose $refs resolve against it.
+const schemaURL = "presence.json"
+
+// Schema is a compiled JSON Schema. Documents without $schema are compiled as draft
+// 2020-12; annotations such as format are not asserted.
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// Compile compiles the JSON Schema document data.
+func Compile(data []byte) (*Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("presence decoding the JSON schema : %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("presence adding the JSON schema : %w", err)
+	}
+
+	s, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("presence compiling the JSON schema : %w", err)
+	}
+
+	return &Schema{schema: s}, nil
+}
+
+// MustCompile is Compile panicking on error, for schemas declared as package variables.
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Validate returns an error wrapping ErrViolation, locating the violations with JSON
+// pointers, when v violates the schema. v is validated in its JSON encoding.
+func (s *Schema) Validate(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("presence encoding %T : %w", v, err)
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("presence decoding %T : %w", v, err)
+	}
+
+	if err := s.schema.Validate(doc); err != nil {
+		return fmt.Errorf("%w : %w", ErrViolation, err)
+	}
+
+	return nil
+}
+
+// Register compiles the JSON Schema data and adds it to the validator of T (see
+// presence.RegisterValidator), so that Scan and UnmarshalJSON reject the values of T
+// violating it with an error wrapping ErrViolation. The validator already registered
+// for T, if any, keeps running first: the schema is composed with it, not replacing it.
+//
+// Validators are registered per Go type, as Scan and UnmarshalJSON do not know the
+// column or the field they decode: declare a named type per JSON column, such as
+// `type UserSettings map[string]any`, to give each column its own schema. Registering a
+// schema for any applies it to every Of[any]. presence.UnregisterValidator removes the
+// composed validator.
+func Register[T any](data []byte) error {
+	s, err := Compile(data)
+	if err != nil {
+		return err
+	}
+
+	prev := presence.GetValidator[T]()
+	presence.RegisterValidator(func(v T) error {
+		if prev != nil {
+			if err := prev(v); err != nil {
+				return err
+			}
+		}
+
+		return s.Validate(v)
+	})
+
+	return nil
+}
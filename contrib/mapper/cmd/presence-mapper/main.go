@@ -0,0 +1,57 @@
+// Command presence-mapper generates the mapping functions between the entity structs
+// and the presence DTOs of the package in the current directory, see package
+// presencemapper.
+//
+//	presence-mapper [-pair Entity=DTO,Entity2=DTO2] [-output presence_mapper.go]
+//
+// The DTO types annotated with //presence:mapper Entity are generated along with the
+// pairs of -pair.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	presencemapper "github.com/pivaldi/presence/contrib/mapper"
+)
+
+func main() {
+	pairList := flag.String("pair", "", "comma separated Entity=DTO pairs to generate, besides the annotated ones")
+	output := flag.String("output", "presence_mapper.go", "generated file")
+	flag.Parse()
+
+	var (
+		pairs []presencemapper.Pair
+		out   bytes.Buffer
+		err   error
+	)
+
+	if *pairList != "" {
+		for s := range strings.SplitSeq(*pairList, ",") {
+			var p presencemapper.Pair
+
+			p, err = presencemapper.ParsePair(s)
+			if err != nil {
+				break
+			}
+
+			pairs = append(pairs, p)
+		}
+	}
+
+	if err == nil {
+		err = presencemapper.Generate(&out, ".", pairs...)
+	}
+
+	if err == nil {
+		err = os.WriteFile(*output, out.Bytes(), 0o600)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "presence-mapper:", err)
+		os.Exit(1)
+	}
+}
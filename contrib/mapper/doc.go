@@ -0,0 +1,33 @@
+/*
+Package presencemapper generates the mapping functions between entity structs and
+their presence DTOs, like goverter does for plain structs.
+
+The presence-mapper command writes, for each DTO type annotated with
+//presence:mapper followed by its entity type, or paired with -pair Entity=DTO, a
+To<DTO> function building the DTO from the entity and a From<DTO> function building
+the entity from the DTO:
+
+	//go:generate go run github.com/pivaldi/presence/contrib/mapper/cmd/presence-mapper
+
+	//presence:mapper User
+	type UserDTO struct {
+		ID    int64               `json:"id"`
+		Email presence.Of[string] `json:"email"`
+		Age   presence.Of[int32]  `json:"age"`
+	}
+
+	// generated
+	func ToUserDTO(v User) UserDTO
+	func FromUserDTO(dto UserDTO) User
+
+Fields are matched by name, those without counterpart being left out. Presence fields
+wrap the values of the entity, nil pointers giving null, and their values are
+unwrapped back, null and unset giving nil pointers or zero values. Values are
+converted when their types differ, e.g. from int to int32 or from a defined type to
+its underlying type. The entity type is declared in the package of the DTO, or in
+one of its imports, named by package, e.g. //presence:mapper domain.User.
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presencemapper
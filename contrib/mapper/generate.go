@@ -0,0 +1,337 @@
+package presencemapper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pivaldi/presence/contrib/internal/gen"
+)
+
+// Annotation marks the DTO types to generate the functions of, followed by their
+// entity type.
+const Annotation = "//presence:mapper"
+
+// ErrNoPairs is returned by Generate when no DTO type is annotated nor paired.
+var ErrNoPairs = errors.New("presence mapper : no type pair to generate")
+
+// Pair is an entity struct type and its presence DTO.
+type Pair struct {
+	// Entity is the name of the entity type, qualified by its package name when it is
+	// declared in an import of the package of the DTO, e.g. "domain.User".
+	Entity string
+	// DTO is the name of the DTO type.
+	DTO string
+}
+
+// ParsePair parses a pair written Entity=DTO.
+func ParsePair(s string) (Pair, error) {
+	entity, dto, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok || entity == "" || dto == "" {
+		return Pair{}, fmt.Errorf("presence mapper : invalid pair %q, want Entity=DTO", s)
+	}
+
+	return Pair{Entity: entity, DTO: dto}, nil
+}
+
+// Generate writes to w the mapping functions of the DTO types of the package in dir
+// annotated with //presence:mapper, and of pairs.
+func Generate(w io.Writer, dir string, pairs ...Pair) error {
+	pkg, err := gen.LoadPackage(dir)
+	if err != nil {
+		return fmt.Errorf("presence mapper : %w", err)
+	}
+
+	var annotated []Pair
+	for _, t := range gen.AnnotatedTypes(pkg.Syntax, Annotation) {
+		if t.Arg != "" {
+			annotated = append(annotated, Pair{Entity: t.Arg, DTO: t.Name})
+		}
+	}
+
+	pairs = append(annotated, pairs...)
+
+	slices.SortFunc(pairs, func(a, b Pair) int { return strings.Compare(a.DTO, b.DTO) })
+	pairs = slices.Compact(pairs)
+
+	if len(pairs) == 0 {
+		return ErrNoPairs
+	}
+
+	g := &generator{pkg: pkg.Types, Imports: gen.NewImports(pkg.Types)}
+
+	var body bytes.Buffer
+	for _, p := range pairs {
+		err := g.generatePair(&body, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by presence-mapper. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	g.WriteDecl(&src)
+	src.Write(body.Bytes())
+
+	err = gen.Write(w, src.Bytes())
+	if err != nil {
+		return fmt.Errorf("presence mapper : %w", err)
+	}
+
+	return nil
+}
+
+// generator writes the functions of the pairs of the package pkg, recording the
+// imports of the types it names.
+type generator struct {
+	*gen.Imports
+
+	pkg *types.Package
+}
+
+// presence returns the qualifier of the presence package, importing it.
+func (g *generator) presence() string {
+	g.Import(gen.PresencePkgPath)
+
+	return "presence."
+}
+
+// lookup returns the struct type named name, in the package of g or in one of its
+// imports.
+func (g *generator) lookup(name string) (types.Type, *types.Struct, error) {
+	scope := g.pkg.Scope()
+	if pkgName, typeName, ok := strings.Cut(name, "."); ok {
+		i := slices.IndexFunc(g.pkg.Imports(), func(p *types.Package) bool { return p.Name() == pkgName })
+		if i < 0 {
+			return nil, nil, fmt.Errorf("presence mapper : unknown package %s of %s", pkgName, name)
+		}
+
+		scope, name = g.pkg.Imports()[i].Scope(), typeName
+	}
+
+	obj, ok := scope.Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, nil, fmt.Errorf("presence mapper : unknown type %s", name)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil, fmt.Errorf("presence mapper : %s is not a struct", name)
+	}
+
+	return obj.Type(), st, nil
+}
+
+// generatePair writes the To and From functions of p.
+func (g *generator) generatePair(w *bytes.Buffer, p Pair) error {
+	if strings.Contains(p.DTO, ".") {
+		return fmt.Errorf("presence mapper : DTO %s must be declared in the generated package", p.DTO)
+	}
+
+	entityType, entity, err := g.lookup(p.Entity)
+	if err != nil {
+		return err
+	}
+
+	dtoType, dto, err := g.lookup(p.DTO)
+	if err != nil {
+		return err
+	}
+
+	var to, from bytes.Buffer
+
+	for i := range dto.NumFields() {
+		d := dto.Field(i)
+		e := structField(entity, d.Name())
+		if !d.Exported() || e == nil {
+			continue
+		}
+
+		err := g.toField(&to, e, d)
+		if err == nil {
+			err = g.fromField(&from, e, d)
+		}
+
+		if err != nil {
+			return fmt.Errorf("presence mapper generating %s : %w", p.DTO, err)
+		}
+	}
+
+	entityName, dtoName := g.TypeString(entityType), g.TypeString(dtoType)
+
+	fmt.Fprintf(w, "\n// To%[1]s maps a %[2]s to a %[1]s.\n", dtoName, entityName)
+	fmt.Fprintf(w, "func To%[1]s(v %[2]s) %[1]s {\n\treturn %[1]s{\n%[3]s\t}\n}\n", dtoName, entityName, to.String())
+	fmt.Fprintf(w, "\n// From%[1]s maps a %[1]s to a %[2]s, null and unset fields giving zero values.\n",
+		dtoName, entityName)
+	fmt.Fprintf(w, "func From%[1]s(dto %[1]s) %[2]s {\n\tvar out %[2]s\n%[3]s\n\treturn out\n}\n",
+		dtoName, entityName, from.String())
+
+	return nil
+}
+
+// structField returns the exported field of st named name, nil when there is none.
+func structField(st *types.Struct, name string) *types.Var {
+	for i := range st.NumFields() {
+		if f := st.Field(i); f.Exported() && f.Name() == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// toField writes the element of the DTO composite literal setting d from e.
+func (g *generator) toField(w *bytes.Buffer, e, d *types.Var) error {
+	src := "v." + e.Name()
+
+	elem, of, isPresence := presenceElem(d.Type())
+	if !isPresence {
+		expr, ok := g.convert(src, e.Type(), d.Type())
+		if !ok {
+			return mappingError(e, d)
+		}
+
+		fmt.Fprintf(w, "\t\t%s: %s,\n", d.Name(), expr)
+
+		return nil
+	}
+
+	expr, ok := g.wrap(src, e.Type(), elem)
+	if !ok {
+		return mappingError(e, d)
+	}
+
+	if of != "" {
+		expr = fmt.Sprintf("%s{%s: %s}", g.TypeString(d.Type()), of, expr)
+	}
+
+	fmt.Fprintf(w, "\t\t%s: %s,\n", d.Name(), expr)
+
+	return nil
+}
+
+// wrap returns the presence.Of[T] expression of src, of type typ, elem being T.
+func (g *generator) wrap(src string, typ, elem types.Type) (string, bool) {
+	if expr, ok := g.convert(src, typ, elem); ok {
+		return g.presence() + "FromValue(" + expr + ")", true
+	}
+
+	ptr, ok := typ.(*types.Pointer)
+	if !ok {
+		return "", false
+	}
+
+	if types.Identical(ptr.Elem(), elem) {
+		return g.presence() + "FromPtr(" + src + ")", true
+	}
+
+	expr, ok := g.convert("x", ptr.Elem(), elem)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("%[1]sMap(%[1]sFromPtr(%[2]s), func(x %[3]s) %[4]s { return %[5]s })",
+		g.presence(), src, g.TypeString(ptr.Elem()), g.TypeString(elem), expr), true
+}
+
+// fromField writes the statements setting the entity field e from d.
+func (g *generator) fromField(w *bytes.Buffer, e, d *types.Var) error {
+	src, dst := "dto."+d.Name(), "out."+e.Name()
+
+	elem, _, isPresence := presenceElem(d.Type())
+	if !isPresence {
+		expr, ok := g.convert(src, d.Type(), e.Type())
+		if !ok {
+			return mappingError(e, d)
+		}
+
+		fmt.Fprintf(w, "\t%s = %s\n", dst, expr)
+
+		return nil
+	}
+
+	if types.Identical(elem, e.Type()) {
+		fmt.Fprintf(w, "\t%s, _ = %s.Get()\n", dst, src)
+
+		return nil
+	}
+
+	if expr, ok := g.convert("x", elem, e.Type()); ok {
+		fmt.Fprintf(w, "\tif x, ok := %s.Get(); ok {\n\t\t%s = %s\n\t}\n", src, dst, expr)
+
+		return nil
+	}
+
+	ptr, ok := e.Type().(*types.Pointer)
+	if !ok {
+		return mappingError(e, d)
+	}
+
+	expr, ok := g.convert("x", elem, ptr.Elem())
+	if !ok {
+		return mappingError(e, d)
+	}
+
+	if expr == "x" {
+		fmt.Fprintf(w, "\tif x, ok := %s.Get(); ok {\n\t\t%s = &x\n\t}\n", src, dst)
+
+		return nil
+	}
+
+	fmt.Fprintf(w, "\tif x, ok := %s.Get(); ok {\n\t\ty := %s\n\t\t%s = &y\n\t}\n", src, expr, dst)
+
+	return nil
+}
+
+// convert returns the expression of src, of type from, as a value of type to, false
+// when it cannot be converted. Integers are not converted to strings, Go converting
+// them to runes.
+func (g *generator) convert(src string, from, to types.Type) (string, bool) {
+	if types.Identical(from, to) {
+		return src, true
+	}
+
+	if !types.ConvertibleTo(from, to) || isInteger(from) && isString(to) {
+		return "", false
+	}
+
+	return g.TypeString(to) + "(" + src + ")", true
+}
+
+func isInteger(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// presenceElem reports whether typ is presence.Of[T] or a type only embedding it, like
+// presence.String, returning T and the name of the embedded presence.Of[T] field.
+func presenceElem(typ types.Type) (types.Type, string, bool) {
+	if elem, ok := gen.PresenceOfElem(typ); ok {
+		return elem, "", true
+	}
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok || st.NumFields() != 1 || !st.Field(0).Embedded() {
+		return nil, "", false
+	}
+
+	elem, ok := gen.PresenceOfElem(st.Field(0).Type())
+
+	return elem, st.Field(0).Name(), ok
+}
+
+// mappingError reports that the entity field e cannot be mapped to the DTO field d.
+func mappingError(e, d *types.Var) error {
+	return fmt.Errorf("cannot map field %s of type %s to %s", d.Name(), e.Type(), d.Type())
+}
@@ -0,0 +1,14 @@
+/*
+Package presenceprometheus exports the decoding failures of presence values as
+[github.com/prometheus/client_golang] counters, labeled by value type:
+
+	presence.SetMetricsHook(presenceprometheus.NewMetrics(prometheus.DefaultRegisterer))
+
+	// presence_unmarshal_errors_total{type="int16"} 3
+	// presence_scan_errors_total{type="uuid.UUID"} 1
+	// presence_overflows_total{type="int16"} 2
+
+It is a contrib module of its own, so that the core presence package keeps its
+zero-dependency policy and only its users pull its dependencies.
+*/
+package presenceprometheus
@@ -0,0 +1,59 @@
+package presenceprometheus
+
+import (
+	"github.com/pivaldi/presence"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ presence.MetricsHook = (*Metrics)(nil)
+
+// Metrics is a presence.MetricsHook counting the failures per value type.
+type Metrics struct {
+	unmarshalErrors *prometheus.CounterVec
+	scanErrors      *prometheus.CounterVec
+	overflows       *prometheus.CounterVec
+}
+
+// NewMetrics returns the Metrics registered with reg, nil registering nothing.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		unmarshalErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "presence_unmarshal_errors_total",
+			Help: "Presence values whose JSON decoding failed.",
+		}, []string{"type"}),
+		scanErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "presence_scan_errors_total",
+			Help: "Presence values whose database scanning failed.",
+		}, []string{"type"}),
+		overflows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "presence_overflows_total",
+			Help: "Presence values whose decoding failed on a number out of range.",
+		}, []string{"type"}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(m.unmarshalErrors, m.scanErrors, m.overflows)
+	}
+
+	return m
+}
+
+// UnmarshalError implements presence.MetricsHook.
+func (m *Metrics) UnmarshalError(typ string, _ error) {
+	m.unmarshalErrors.WithLabelValues(typ).Inc()
+}
+
+// ScanError implements presence.MetricsHook.
+func (m *Metrics) ScanError(typ string, _ error) {
+	m.scanErrors.WithLabelValues(typ).Inc()
+}
+
+// Overflow implements presence.MetricsHook.
+func (m *Metrics) Overflow(typ string) {
+	m.overflows.WithLabelValues(typ).Inc()
+}
+
+// Collectors returns the counters, for registries built by hand.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.unmarshalErrors, m.scanErrors, m.overflows}
+}
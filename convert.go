@@ -0,0 +1,102 @@
+package presence
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConvertStruct copies the same-named fields of the struct src into the struct pointed
+// to by dst, converting between presence, pointer and plain fields, for instance from
+// presence structs to GraphQL or API response models with pointer fields, and back:
+//
+//	var out model.User
+//	err := presence.ConvertStruct(&out, user) // user.Bio null or unset: out.Bio is nil
+//
+//	var user User
+//	err = presence.ConvertStruct(&user, in) // in.Bio nil: user.Bio is null
+//
+// Values are converted like Go conversions do. Nulls become nil pointers and null
+// presence fields, and return ErrNullNotAllowed for plain fields. Unset fields unset
+// presence fields and zero the others. Unlike PatchStruct, every field of src is
+// copied; src fields missing from dst are ignored, dst fields missing from src are
+// left untouched.
+func ConvertStruct(dst, src any, opts ...Option) error {
+	o := newOptions(opts)
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence conversion destination must be a non-nil struct pointer, got %T", dst)
+	}
+
+	sv, err := structValue(src)
+	if err != nil {
+		return err
+	}
+
+	dv = dv.Elem()
+	targets := fieldIndexes(dv.Type(), o)
+
+	walkFields(sv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		target, ok := targets[name]
+		if err != nil || !ok {
+			return
+		}
+
+		err = convertField(dv.FieldByIndex(target), sv.FieldByIndex(index), isPresence)
+		if err != nil {
+			err = fmt.Errorf("presence converting field %s : %w", name, err)
+		}
+	})
+
+	return err
+}
+
+// convertField sets the field dst from the field src, presence or not.
+func convertField(dst, src reflect.Value, isPresence bool) error {
+	state, value := StateValue, any(nil)
+
+	switch {
+	case isPresence:
+		pf := src.Addr().Interface().(presenceField)
+		state, value = pf.State(), pf.anyValue()
+	case src.Kind() == reflect.Pointer && src.IsNil():
+		state = StateNull
+	case src.Kind() == reflect.Pointer:
+		value = src.Elem().Interface()
+	default:
+		value = src.Interface()
+	}
+
+	target, isTarget := dst.Addr().Interface().(presenceField)
+
+	switch state {
+	case StateUnset:
+		if isTarget {
+			target.Unset()
+		} else {
+			dst.SetZero()
+		}
+
+		return nil
+	case StateNull:
+		return nullField(dst)
+	case StateValue:
+	}
+
+	if isTarget {
+		return target.setAny(value)
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		ptr := reflect.New(dst.Type().Elem())
+		err := assign(ptr.Elem(), value)
+		if err != nil {
+			return err
+		}
+
+		dst.Set(ptr)
+
+		return nil
+	}
+
+	return assign(dst, value)
+}
@@ -0,0 +1,105 @@
+package presence
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ANSI escape sequences of DebugStringColor.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiFaint  = "\x1b[2m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// debugStyle colors the parts of a DebugString rendering.
+type debugStyle struct {
+	value, null, unset string
+}
+
+func (s debugStyle) paint(color, text string) string {
+	if color == "" {
+		return text
+	}
+
+	return color + text + ansiReset
+}
+
+// DebugString renders the struct v with the state of its presence fields, for logs and
+// debugging sessions of PATCH handling:
+//
+//	{name: "x", age: <null>, email: <unset>}
+//
+// Fields are named as by ToMap, embedded structs are flattened and plain fields are
+// rendered as values. A presence value renders alone, any other value with fmt.
+func DebugString(v any, opts ...Option) string {
+	return debugString(v, newOptions(opts), debugStyle{})
+}
+
+// DebugStringColor is DebugString colorized with ANSI escape sequences for terminals:
+// values in green, nulls in yellow and unset fields faint.
+func DebugStringColor(v any, opts ...Option) string {
+	return debugString(v, newOptions(opts), debugStyle{value: ansiGreen, null: ansiYellow, unset: ansiFaint})
+}
+
+func debugString(v any, o *options, style debugStyle) string {
+	if state, value, ok := presenceOf(v); ok {
+		return debugValue(state, value, style)
+	}
+
+	rv, err := structValue(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	var parts []string
+	walkFields(rv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		field := rv.FieldByIndex(index)
+		if isPresence {
+			pf := field.Addr().Interface().(presenceField)
+			parts = append(parts, name+": "+debugValue(pf.State(), pf.anyValue(), style))
+
+			return
+		}
+
+		parts = append(parts, name+": "+style.paint(style.value, formatDebugValue(field.Interface())))
+	})
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func debugValue(state State, value any, style debugStyle) string {
+	switch state {
+	case StateUnset:
+		return style.paint(style.unset, "<unset>")
+	case StateNull:
+		return style.paint(style.null, "<null>")
+	case StateValue:
+	}
+
+	return style.paint(style.value, formatDebugValue(value))
+}
+
+// formatDebugValue formats v, quoting strings so that empty ones remain visible.
+func formatDebugValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case big.Int:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		return formatDebugValue(rv.Elem().Interface())
+	}
+
+	return fmt.Sprintf("%v", v)
+}
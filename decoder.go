@@ -0,0 +1,182 @@
+package presence
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// defaultDecoderChunkSize is the chunk size of NewDecoder when none is given.
+const defaultDecoderChunkSize = 1024
+
+// Decoder decodes presence values into storage it allocates by chunks of values and
+// reuses after Reset, instead of allocating each value on its own. It targets ETL
+// workloads decoding millions of rows or JSON lines one batch at a time:
+//
+//	dec := presence.NewDecoder[Event](0)
+//	for batch := range batches {
+//		for i, line := range batch {
+//			err := dec.DecodeJSON(&events[i], line)
+//			...
+//		}
+//		flush(events)
+//		dec.Reset() // events now point to storage about to be reused
+//	}
+//
+// The values decoded before a Reset must not be used after it: their storage then
+// holds the next values. Copy them (WithValue, or Get then FromValue) to keep them.
+// A Decoder is not safe for concurrent use; use one per goroutine, or a sync.Pool.
+type Decoder[T any] struct {
+	chunkSize int
+	chunks    [][]T
+	chunk     int
+	next      int
+}
+
+// NewDecoder returns a Decoder allocating its storage by chunks of chunkSize values,
+// 1024 when chunkSize is not positive.
+func NewDecoder[T any](chunkSize int) *Decoder[T] {
+	if chunkSize <= 0 {
+		chunkSize = defaultDecoderChunkSize
+	}
+
+	return &Decoder[T]{chunkSize: chunkSize}
+}
+
+// DecodeJSON decodes data into dst like dst.UnmarshalJSON, the value being stored
+// in the Decoder.
+func (d *Decoder[T]) DecodeJSON(dst *Of[T], data []byte) error {
+	prev, slot := dst.val, d.slot()
+	dst.val = slot
+
+	err := dst.UnmarshalJSON(data)
+	if dst.val != slot || err != nil {
+		d.release()
+	}
+
+	if err != nil && dst.val == slot {
+		dst.val = prev
+	}
+
+	return err
+}
+
+// Scan scans src into dst like dst.Scan, the value being stored in the Decoder.
+func (d *Decoder[T]) Scan(dst *Of[T], src any) error {
+	prev, slot := dst.val, d.slot()
+	dst.val = slot
+	dst.flags |= flagArena
+
+	err := dst.Scan(src)
+	stored := dst.flags&flagArena == 0 && dst.val == slot
+	dst.flags &^= flagArena
+
+	if stored && err == nil {
+		return nil
+	}
+
+	// The value did not go to the slot: null, or an error.
+	d.release()
+
+	if dst.val == slot {
+		dst.val = prev
+	}
+
+	return err
+}
+
+// Reset makes the whole storage available again, invalidating the values decoded so far.
+// It keeps the allocated chunks.
+func (d *Decoder[T]) Reset() {
+	d.chunk, d.next = 0, 0
+}
+
+// slot returns zeroed storage for the next value.
+func (d *Decoder[T]) slot() *T {
+	if d.next == d.chunkSize {
+		d.chunk++
+		d.next = 0
+	}
+
+	if d.chunk == len(d.chunks) {
+		d.chunks = append(d.chunks, make([]T, d.chunkSize))
+	}
+
+	p := &d.chunks[d.chunk][d.next]
+	d.next++
+
+	var zero T
+	*p = zero
+
+	return p
+}
+
+// release gives back the storage of the last slot call.
+func (d *Decoder[T]) release() {
+	d.next--
+}
+
+// defaultLineBufferSize is the initial size of the line buffer of DecodeLines.
+const defaultLineBufferSize = 64 * 1024
+
+// DecodeLines decodes the JSON Lines (NDJSON) stream r one line at a time, calling yield
+// with each decoded struct, for import pipelines:
+//
+//	err := presence.DecodeLines(file, func(p *UserPatch) error {
+//		return store.Apply(ctx, p)
+//	})
+//
+// The line buffer and the struct are reused across lines, so that decoding allocates
+// only what the values themselves need: the struct passed to yield is zeroed before each
+// line, its presence fields absent from the line being unset, and must not be retained
+// after yield returns. Blank lines are skipped. Decoding stops at the first error, from
+// the stream, a line or yield, reported with the line number.
+func DecodeLines[T any](r io.Reader, yield func(*T) error) error {
+	br := bufio.NewReaderSize(r, defaultLineBufferSize)
+
+	var (
+		v    T
+		zero T
+		long []byte
+	)
+
+	for n := 1; ; n++ {
+		line, err := br.ReadSlice('\n')
+
+		// Lines longer than the buffer are accumulated in long, reused as well.
+		if errors.Is(err, bufio.ErrBufferFull) {
+			long = append(long[:0], line...)
+			for errors.Is(err, bufio.ErrBufferFull) {
+				line, err = br.ReadSlice('\n')
+				long = append(long, line...)
+			}
+
+			line = long
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("presence JSON line %d : %w", n, err)
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			v = zero
+
+			decodeErr := json.Unmarshal(line, &v)
+			if decodeErr != nil {
+				return fmt.Errorf("presence JSON line %d : %w", n, decodeErr)
+			}
+
+			decodeErr = yield(&v)
+			if decodeErr != nil {
+				return fmt.Errorf("presence JSON line %d : %w", n, decodeErr)
+			}
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package presence
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is returned for JSON objects with duplicate keys when
+// DuplicateKeysReject is configured, and by CheckDuplicateKeys.
+var ErrDuplicateKey = errors.New("presence: duplicate JSON object key")
+
+// keyFrame is an array or object being read by CheckDuplicateKeys.
+type keyFrame struct {
+	// keys holds the keys read so far, nil for arrays.
+	keys map[string]bool
+	// expectKey reports whether the next token is a key.
+	expectKey bool
+}
+
+// CheckDuplicateKeys returns ErrDuplicateKey when an object of the JSON document data,
+// at any depth, has duplicate keys. UnmarshalJSON and UnmarshalMerge check their
+// payload with DuplicateKeysReject, but encoding/json decodes the objects of plain
+// structs itself: check the documents decoded into presence structs beforehand.
+//
+//	err := presence.CheckDuplicateKeys(body)
+//	if err == nil {
+//		err = json.Unmarshal(body, &input)
+//	}
+//
+// Invalid documents are left to the decoding to report.
+func CheckDuplicateKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var stack []keyFrame
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// io.EOF ends the document, syntax errors are reported by the decoding.
+			return nil
+		}
+
+		if tok == json.Delim('}') || tok == json.Delim(']') {
+			stack = stack[:len(stack)-1]
+
+			continue
+		}
+
+		var top *keyFrame
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+
+		if top != nil && top.expectKey {
+			key, _ := tok.(string)
+			if top.keys[key] {
+				return fmt.Errorf("%w : %q", ErrDuplicateKey, key)
+			}
+
+			top.keys[key] = true
+			top.expectKey = false
+
+			continue
+		}
+
+		if top != nil && top.keys != nil {
+			top.expectKey = true
+		}
+
+		if tok == json.Delim('{') {
+			stack = append(stack, keyFrame{keys: map[string]bool{}, expectKey: true})
+		} else if tok == json.Delim('[') {
+			stack = append(stack, keyFrame{})
+		}
+	}
+}
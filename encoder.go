@@ -0,0 +1,159 @@
+package presence
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encoder writes JSON objects field by field to an io.Writer, for hand-rolled encoders
+// of objects too large to be held in a struct, or whose fields are only known at
+// runtime:
+//
+//	enc := presence.NewEncoder(w)
+//	_ = enc.BeginObject()
+//	_ = enc.Field("id", user.ID)
+//	_ = presence.EncodeField(enc, "name", user.Name) // left out when unset
+//	err := enc.EndObject()
+//
+// Presence fields follow their MarshalUnsetBehavior like struct fields tagged omitzero:
+// unset values are left out with UnsetSkip, written as null with UnsetNull and rejected
+// with UnsetError. The first error is kept and returned by the later calls, which write
+// nothing. Writes are small: buffer w, e.g. with a bufio.Writer.
+type Encoder struct {
+	w io.Writer
+	// fields holds, per open object, whether a field was written.
+	fields     []bool
+	escapeHTML bool
+	err        error
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, escapeHTML: true}
+}
+
+// SetEscapeHTML sets whether the HTML characters <, > and & inside the strings of the
+// values are escaped, as they are by default. Like WithEscapeHTML for Marshal, turning
+// it off writes them as is, the values of presence fields included.
+func (e *Encoder) SetEscapeHTML(on bool) {
+	e.escapeHTML = on
+}
+
+// BeginObject opens the top-level object.
+func (e *Encoder) BeginObject() error {
+	if e.err == nil && len(e.fields) > 0 {
+		e.err = errors.New("presence encoder : object already open, use BeginObjectField")
+	}
+
+	e.write([]byte{'{'})
+	e.fields = append(e.fields, false)
+
+	return e.err
+}
+
+// BeginObjectField opens an object as the field name of the current object.
+func (e *Encoder) BeginObjectField(name string) error {
+	e.writeName(name)
+	e.write([]byte{'{'})
+	e.fields = append(e.fields, false)
+
+	return e.err
+}
+
+// EndObject closes the current object.
+func (e *Encoder) EndObject() error {
+	if e.err == nil && len(e.fields) == 0 {
+		e.err = errors.New("presence encoder : no open object")
+	}
+
+	e.write([]byte{'}'})
+	if len(e.fields) > 0 {
+		e.fields = e.fields[:len(e.fields)-1]
+	}
+
+	return e.err
+}
+
+// Field writes v, encoded like Marshal, as the field name of the current object.
+func (e *Encoder) Field(name string, v any) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	b, err := marshalDBOnly(v)
+	if err != nil {
+		e.err = fmt.Errorf("presence encoding field %s : %w", name, err)
+
+		return e.err
+	}
+
+	e.writeName(name)
+	e.writeValue(b)
+
+	return e.err
+}
+
+// EncodeField writes n as the field name of the current object of e, unless it is
+// unset with UnsetSkip.
+func EncodeField[T any](e *Encoder, name string, n Of[T]) error {
+	if e.err != nil || n.IsZero() {
+		return e.err
+	}
+
+	b, err := n.MarshalJSON()
+	if err != nil {
+		e.err = fmt.Errorf("presence encoding field %s : %w", name, err)
+
+		return e.err
+	}
+
+	e.writeName(name)
+	e.writeValue(b)
+
+	return e.err
+}
+
+// writeName writes the separator and the name of a field of the current object.
+func (e *Encoder) writeName(name string) {
+	if e.err == nil && len(e.fields) == 0 {
+		e.err = errors.New("presence encoder : field outside of an object")
+	}
+
+	if e.err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	if e.fields[len(e.fields)-1] {
+		buf.WriteByte(',')
+	}
+
+	writeCanonicalString(&buf, name)
+	buf.WriteByte(':')
+	e.fields[len(e.fields)-1] = true
+	e.write(buf.Bytes())
+}
+
+// writeValue writes the encoded value b, unescaping its HTML characters unless
+// escapeHTML is set.
+func (e *Encoder) writeValue(b []byte) {
+	if !e.escapeHTML {
+		b = unescapeHTML(b)
+	}
+
+	e.write(b)
+}
+
+// write writes b unless an error occurred before.
+func (e *Encoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+
+	_, err := e.w.Write(b)
+	if err != nil {
+		e.err = fmt.Errorf("presence encoder writing : %w", err)
+	}
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
-	"fmt"
 	"path/filepath"
 
+	presencegorm "github.com/pivaldi/presence/contrib/gorm"
+	"github.com/pivaldi/presence/naming"
 	"gorm.io/gen"
 	"gorm.io/gorm"
 )
 
+var (
+	stringMapFunc  = presencegorm.NullableType("string")
+	integerMapFunc = presencegorm.NullableType("int64")
+	floatMapFunc   = presencegorm.NullableType("float64")
+	boolMapFunc    = presencegorm.NullableType("bool")
+	timeMapFunc    = presencegorm.NullableType("time.Time")
+	uuidMapFunc    = presencegorm.NullableType("uuid.UUID")
+)
+
 // dataTypeMap defines custom type mappings for PostgreSQL column types.
 // For nullable columns, we wrap the type with presence.Of[T].
 var dataTypeMap = map[string]func(gorm.ColumnType) string{
@@ -37,11 +47,11 @@ var dataTypeMap = map[string]func(gorm.ColumnType) string{
 	"boolean": boolMapFunc,
 
 	// Date/Time types
-	"date":        dateMapFunc,
+	"date":        timeMapFunc,
 	"time":        timeMapFunc,
 	"timetz":      timeMapFunc,
-	"timestamp":   timestampMapFunc,
-	"timestamptz": timestampMapFunc,
+	"timestamp":   timeMapFunc,
+	"timestamptz": timeMapFunc,
 
 	// JSON types
 	"json":  jsonMapFunc,
@@ -51,52 +61,11 @@ var dataTypeMap = map[string]func(gorm.ColumnType) string{
 	"uuid": uuidMapFunc,
 }
 
-// Type mapping functions
-
-func wrapNullable(c gorm.ColumnType, baseType string) string {
-	if nullable, _ := c.Nullable(); nullable {
-		return fmt.Sprintf("presence.Of[%s]", baseType)
-	}
-	return baseType
-}
-
-func stringMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "string")
-}
-
-func integerMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "int64")
-}
-
-func floatMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "float64")
-}
-
-func boolMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "bool")
-}
-
-func dateMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "time.Time")
-}
-
-func timeMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "time.Time")
-}
-
-func timestampMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "time.Time")
-}
-
-func jsonMapFunc(c gorm.ColumnType) string {
+func jsonMapFunc(gorm.ColumnType) string {
 	// JSON columns always use presence.Of[any] for convenient handling
 	return "presence.Of[any]"
 }
 
-func uuidMapFunc(c gorm.ColumnType) string {
-	return wrapNullable(c, "uuid.UUID")
-}
-
 func getGenerator(outputDir string, db *gorm.DB) *gen.Generator {
 	dalPath := filepath.Join(outputDir, "dal")
 	modelPath := getModelOutDir(outputDir)
@@ -119,7 +88,7 @@ func getGenerator(outputDir string, db *gorm.DB) *gen.Generator {
 	)
 
 	// Configure JSON tag naming strategy
-	config.WithJSONTagNameStrategy(snakeToCamelCase)
+	config.WithJSONTagNameStrategy(naming.Camel.Convert)
 
 	// Create the generator
 	g := gen.NewGenerator(config)
@@ -130,6 +99,9 @@ func getGenerator(outputDir string, db *gorm.DB) *gen.Generator {
 	// Apply custom data type mappings
 	g.WithDataTypeMap(dataTypeMap)
 
+	// Generate typed query fields (field.String, field.Int64…) for presence columns
+	g.WithOpts(presencegorm.GenTypes(), presencegorm.JSONSchemaTags())
+
 	// Config to generate models for all tables
 	g.ApplyBasic(g.GenerateAllTable()...)
 
@@ -0,0 +1,23 @@
+// Command gqlgen runs gqlgen with the presence model plugin.
+// Run it from the example directory: go run ./cmd/gqlgen
+package main
+
+import (
+	"log"
+
+	"github.com/99designs/gqlgen/api"
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/pivaldi/presence/examples/gqlgen/presencegql"
+)
+
+func main() {
+	cfg, err := config.LoadConfigFromDefaultLocations()
+	if err != nil {
+		log.Fatalf("loading gqlgen config: %v", err)
+	}
+
+	err = api.Generate(cfg, api.ReplacePlugin(presencegql.New()))
+	if err != nil {
+		log.Fatalf("generating: %v", err)
+	}
+}
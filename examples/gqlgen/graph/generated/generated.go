@@ -274,7 +274,12 @@ func (ec *executionContext) introspectType(name string) (*introspection.Type, er
 }
 
 var sources = []*ast.Source{
-	{Name: "../schema.graphqls", Input: `type User {
+	{Name: "../schema.graphqls", Input: `"""
+Generates the input field as presence.Of[T], telling fields not sent from fields sent as null.
+"""
+directive @presence on INPUT_FIELD_DEFINITION
+
+type User {
   id: ID!
   username: String!
   email: String
@@ -284,11 +289,11 @@ var sources = []*ast.Source{
 }
 
 input UpdateUserInput {
-  username: String
-  email: String
-  bio: String
-  website: String
-  age: Int
+  username: String @presence
+  email: String @presence
+  bio: String @presence
+  website: String @presence
+  age: Int @presence
 }
 
 type Query {
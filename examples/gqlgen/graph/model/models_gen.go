@@ -2,8 +2,20 @@
 
 package model
 
+import (
+	"github.com/pivaldi/presence"
+)
+
 type Mutation struct {
 }
 
 type Query struct {
 }
+
+type UpdateUserInput struct {
+	Username presence.Of[string] `json:"username,omitempty"`
+	Email    presence.Of[string] `json:"email,omitempty"`
+	Bio      presence.Of[string] `json:"bio,omitempty"`
+	Website  presence.Of[string] `json:"website,omitempty"`
+	Age      presence.Of[int]    `json:"age,omitempty"`
+}
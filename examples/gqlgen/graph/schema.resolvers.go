@@ -11,6 +11,7 @@ import (
 
 	"github.com/pivaldi/presence/examples/gqlgen/graph/generated"
 	"github.com/pivaldi/presence/examples/gqlgen/graph/model"
+	"github.com/pivaldi/presence/examples/gqlgen/presencegql"
 )
 
 // UpdateUser is the resolver for the updateUser field.
@@ -21,32 +22,8 @@ func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input mode
 		return nil, fmt.Errorf("user not found: %s", id)
 	}
 
-	// Username: required field, cannot be set to null
-	if input.Username.IsSet() {
-		if input.Username.IsNull() {
-			return nil, fmt.Errorf("username cannot be null")
-		}
-		user.Username = input.Username.MustGet()
-	}
-
-	// Email: optional, can be set to null to clear
-	if input.Email.IsSet() {
-		user.Email = input.Email.Ptr()
-	}
-
-	// Bio: optional, can be set to null to clear
-	if input.Bio.IsSet() {
-		user.Bio = input.Bio.Ptr()
-	}
-
-	// Website: optional, can be set to null to clear
-	if input.Website.IsSet() {
-		user.Website = input.Website.Ptr()
-	}
-
-	// Age: optional, can be set to null to clear
-	if input.Age.IsSet() {
-		user.Age = input.Age.Ptr()
+	if err := UpdateFromInput(user, input); err != nil {
+		return nil, err
 	}
 
 	return user, nil
@@ -72,27 +49,27 @@ func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
 
 // Username is the resolver for the username field.
 func (r *updateUserInputResolver) Username(ctx context.Context, obj *model.UpdateUserInput, data *string) error {
-	panic(fmt.Errorf("not implemented: Username - username"))
+	return presencegql.Set(&obj.Username, data)
 }
 
 // Email is the resolver for the email field.
 func (r *updateUserInputResolver) Email(ctx context.Context, obj *model.UpdateUserInput, data *string) error {
-	panic(fmt.Errorf("not implemented: Email - email"))
+	return presencegql.Set(&obj.Email, data)
 }
 
 // Bio is the resolver for the bio field.
 func (r *updateUserInputResolver) Bio(ctx context.Context, obj *model.UpdateUserInput, data *string) error {
-	panic(fmt.Errorf("not implemented: Bio - bio"))
+	return presencegql.Set(&obj.Bio, data)
 }
 
 // Website is the resolver for the website field.
 func (r *updateUserInputResolver) Website(ctx context.Context, obj *model.UpdateUserInput, data *string) error {
-	panic(fmt.Errorf("not implemented: Website - website"))
+	return presencegql.Set(&obj.Website, data)
 }
 
 // Age is the resolver for the age field.
 func (r *updateUserInputResolver) Age(ctx context.Context, obj *model.UpdateUserInput, data *int) error {
-	panic(fmt.Errorf("not implemented: Age - age"))
+	return presencegql.Set(&obj.Age, data)
 }
 
 // Mutation returns generated.MutationResolver implementation.
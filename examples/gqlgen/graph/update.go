@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/pivaldi/presence"
+	"github.com/pivaldi/presence/examples/gqlgen/graph/model"
+)
+
+// UpdateFromInput applies the fields sent in input to user with three-state semantics:
+// fields not sent are left untouched, fields sent as null are cleared and the others are
+// updated. The username is required and cannot be cleared.
+func UpdateFromInput(user *model.User, input model.UpdateUserInput) error {
+	if input.Username.IsNull() {
+		return errors.New("username cannot be null")
+	}
+
+	return presence.ApplyToPointers(user, input)
+}
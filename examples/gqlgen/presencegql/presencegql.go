@@ -0,0 +1,102 @@
+// Package presencegql wires presence.Of[T] into gqlgen.
+//
+// Input fields marked with the @presence directive are generated as presence.Of[T]
+// by the model plugin returned by New. gqlgen then generates an input field resolver
+// for them, called only when the field is present in the input, which Set implements.
+// UpdateMap turns the resulting input into an update map.
+package presencegql
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"github.com/99designs/gqlgen/plugin"
+	"github.com/99designs/gqlgen/plugin/modelgen"
+	"github.com/pivaldi/presence"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// DirectiveName is the name of the directive marking the presence input fields.
+// The schema declares it as:
+//
+//	directive @presence on INPUT_FIELD_DEFINITION
+//
+// and gqlgen.yml skips it at runtime (directives: presence: skip_runtime: true).
+const DirectiveName = "presence"
+
+const presencePkgPath = "github.com/pivaldi/presence"
+
+// ofType is the generic presence.Of type, instantiated for the marked fields.
+var ofType = func() *types.Named {
+	pkg := types.NewPackage(presencePkgPath, "presence")
+	param := types.NewTypeParam(types.NewTypeName(token.NoPos, pkg, "T", nil), types.Universe.Lookup("any").Type())
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Of", nil), types.NewStruct(nil, nil), nil)
+	named.SetTypeParams([]*types.TypeParam{param})
+
+	return named
+}()
+
+// New returns the gqlgen model plugin generating the @presence input fields as
+// presence.Of[T], to use with api.ReplacePlugin.
+func New() plugin.Plugin {
+	return &modelgen.Plugin{
+		MutateHook: modelgen.DefaultBuildMutateHook,
+		FieldHook:  FieldHook,
+	}
+}
+
+// FieldHook is a modelgen.FieldMutateHook typing the input fields marked with @presence
+// as presence.Of[T], T being the type gqlgen would have generated without the pointer.
+func FieldHook(td *ast.Definition, fd *ast.FieldDefinition, f *modelgen.Field) (*modelgen.Field, error) {
+	f, err := modelgen.DefaultFieldMutateHook(td, fd, f)
+	if err != nil {
+		return nil, fmt.Errorf("presencegql field hook : %w", err)
+	}
+
+	if fd.Directives.ForName(DirectiveName) == nil {
+		return f, nil
+	}
+
+	if td.Kind != ast.InputObject {
+		return nil, fmt.Errorf("presencegql: @%s on %s.%s, which is not an input field", DirectiveName, td.Name, fd.Name)
+	}
+
+	elem := f.Type
+	if ptr, ok := elem.(*types.Pointer); ok {
+		elem = ptr.Elem()
+	}
+
+	typ, err := types.Instantiate(nil, ofType, []types.Type{elem}, false)
+	if err != nil {
+		return nil, fmt.Errorf("presencegql typing %s.%s : %w", td.Name, fd.Name, err)
+	}
+
+	f.Type = typ
+
+	return f, nil
+}
+
+// Set implements the input field resolvers of the presence fields: gqlgen only calls
+// them for the fields present in the input, with a nil data for null.
+//
+//	func (r *updateUserInputResolver) Email(ctx context.Context, obj *model.UpdateUserInput, data *string) error {
+//		return presencegql.Set(&obj.Email, data)
+//	}
+func Set[T any](dst *presence.Of[T], data *T) error {
+	dst.SetValueP(data)
+
+	return nil
+}
+
+// UpdateMap returns the set presence fields of input keyed by their GraphQL names, nil
+// for null, leaving the fields absent from the input out. The map suits update builders
+// such as gorm's Updates(map[string]any); the options are those of presence.ToMap.
+func UpdateMap(input any, opts ...presence.Option) (map[string]any, error) {
+	m, err := presence.ToMap(input, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("presencegql update map : %w", err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,206 @@
+// Command protoc-gen-presence generates presence DTOs for the messages of protoc-gen-go.
+//
+// For each message M it writes, next to the protoc-gen-go output:
+//   - MDTO, a struct whose proto3 optional fields are presence.Of[T] and whose other
+//     fields keep their type, FieldMask fields excepted;
+//   - NewMDTO, converting a *M to MDTO;
+//   - MDTO.Proto, converting back to a *M.
+//
+// When M has a google.protobuf.FieldMask field, the optional fields are read with
+// presencepb.FromMasked, and Proto lists the set ones in the mask so that nulls survive.
+// M.PresenceDTO then returns NewMDTO(m), for presencepb.UnaryServerInterceptor.
+// The presence.v1 wrapper fields (presence.v1.StringValue…), which carry their nulls
+// themselves, are presence.Of[T] too, converted by presencepb.FromWrapper and ToWrapper.
+// Map and oneof fields are not supported and left out.
+//
+//	protoc -I proto --go_out=. --go_opt=module=github.com/pivaldi/presence/examples/grpc \
+//	  --presence_out=. --presence_opt=module=github.com/pivaldi/presence/examples/grpc user/v1/user.proto
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const (
+	fieldMaskName  protoreflect.FullName = "google.protobuf.FieldMask"
+	wrapperPackage protoreflect.FullName = "presence.v1"
+)
+
+var (
+	presencePackage   = protogen.GoImportPath("github.com/pivaldi/presence")
+	presencepbPackage = protogen.GoImportPath("github.com/pivaldi/presence/examples/grpc/presencepb")
+)
+
+func main() {
+	protogen.Options{}.Run(func(p *protogen.Plugin) error {
+		p.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range p.Files {
+			// The wrappers are presence values themselves.
+			if f.Generate && len(f.Messages) > 0 && f.Desc.Package() != wrapperPackage {
+				generateFile(p, f)
+			}
+		}
+
+		return nil
+	})
+}
+
+func generateFile(p *protogen.Plugin, f *protogen.File) {
+	g := p.NewGeneratedFile(f.GeneratedFilenamePrefix+".presence.go", f.GoImportPath)
+	g.P("// Code generated by protoc-gen-presence. DO NOT EDIT.")
+	g.P("// source: ", f.Desc.Path())
+	g.P()
+	g.P("package ", f.GoPackageName)
+
+	for _, m := range f.Messages {
+		generateMessage(g, m)
+	}
+}
+
+// dtoField is a message field carried by the DTO.
+type dtoField struct {
+	*protogen.Field
+
+	goType   string
+	optional bool
+	// wrapper marks the presence.v1 wrapper fields, optional too.
+	wrapper bool
+}
+
+func generateMessage(g *protogen.GeneratedFile, m *protogen.Message) {
+	var (
+		fields []dtoField
+		mask   *protogen.Field
+	)
+
+	for _, f := range m.Fields {
+		switch {
+		case f.Message != nil && f.Message.Desc.FullName() == fieldMaskName:
+			mask = f
+		case f.Desc.IsMap() || f.Oneof != nil && !f.Oneof.Desc.IsSynthetic():
+		case f.Message != nil && !f.Desc.IsList() && f.Message.Desc.ParentFile().Package() == wrapperPackage:
+			fields = append(fields, dtoField{Field: f, goType: scalarType(g, f.Message.Fields[0]), optional: true,
+				wrapper: true})
+		case f.Desc.HasOptionalKeyword():
+			fields = append(fields, dtoField{Field: f, goType: scalarType(g, f), optional: true})
+		default:
+			fields = append(fields, dtoField{Field: f, goType: fieldType(g, f)})
+		}
+	}
+
+	dto := m.GoIdent.GoName + "DTO"
+	of := g.QualifiedGoIdent(presencePackage.Ident("Of"))
+
+	g.P()
+	g.P("// ", dto, " is the presence DTO of ", m.GoIdent.GoName, ".")
+	g.P("type ", dto, " struct {")
+	for _, f := range fields {
+		if f.optional {
+			g.P(f.GoName, " ", of, "[", f.goType, "] `json:\"", f.Desc.JSONName(), ",omitzero\"`")
+		} else {
+			g.P(f.GoName, " ", f.goType, " `json:\"", f.Desc.JSONName(), "\"`")
+		}
+	}
+	g.P("}")
+
+	g.P()
+	g.P("// New", dto, " converts m to its presence DTO.")
+	g.P("func New", dto, "(m *", m.GoIdent, ") ", dto, " {")
+	g.P("return ", dto, "{")
+	for _, f := range fields {
+		switch {
+		case f.wrapper:
+			g.P(f.GoName, ": ", presencepbPackage.Ident("FromWrapper"), "(m.", f.GoName, "),")
+		case f.optional && mask != nil:
+			g.P(f.GoName, ": ", presencepbPackage.Ident("FromMasked"), "(m.", f.GoName, ", m.Get", mask.GoName, "(), \"",
+				f.Desc.Name(), "\"),")
+		case f.optional:
+			g.P(f.GoName, ": ", presencepbPackage.Ident("FromOptional"), "(m.", f.GoName, "),")
+		default:
+			g.P(f.GoName, ": m.Get", f.GoName, "(),")
+		}
+	}
+	g.P("}")
+	g.P("}")
+
+	g.P()
+	g.P("// Proto converts d to its message, nil optional fields standing for null and unset ones.")
+	g.P("func (d ", dto, ") Proto() *", m.GoIdent, " {")
+	g.P("m := &", m.GoIdent, "{")
+	for _, f := range fields {
+		switch {
+		case f.wrapper:
+			g.P(f.GoName, ": ", presencepbPackage.Ident("ToWrapper"), "[*", f.Message.GoIdent, "](d.", f.GoName, "),")
+		case f.optional:
+			g.P(f.GoName, ": ", presencepbPackage.Ident("ToOptional"), "(d.", f.GoName, "),")
+		default:
+			g.P(f.GoName, ": d.", f.GoName, ",")
+		}
+	}
+	g.P("}")
+	if mask != nil {
+		for _, f := range fields {
+			if f.optional {
+				g.P("m.", mask.GoName, " = ", presencepbPackage.Ident("MaskPath"), "(m.", mask.GoName, ", \"", f.Desc.Name(),
+					"\", d.", f.GoName, ")")
+			}
+		}
+	}
+	g.P()
+	g.P("return m")
+	g.P("}")
+
+	if mask != nil {
+		g.P()
+		g.P("// PresenceDTO returns the presence DTO of m, implementing presencepb.DTOMessage.")
+		g.P("func (m *", m.GoIdent, ") PresenceDTO() any {")
+		g.P("return New", dto, "(m)")
+		g.P("}")
+	}
+}
+
+// fieldType returns the Go type protoc-gen-go gives to the non-optional field f.
+func fieldType(g *protogen.GeneratedFile, f *protogen.Field) string {
+	typ := scalarType(g, f)
+	if f.Message != nil {
+		typ = "*" + typ
+	}
+
+	if f.Desc.IsList() {
+		typ = "[]" + typ
+	}
+
+	return typ
+}
+
+// scalarType returns the Go type of the values of f.
+func scalarType(g *protogen.GeneratedFile, f *protogen.Field) string {
+	switch f.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]byte"
+	case protoreflect.EnumKind:
+		return g.QualifiedGoIdent(f.Enum.GoIdent)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.QualifiedGoIdent(f.Message.GoIdent)
+	}
+
+	return "any"
+}
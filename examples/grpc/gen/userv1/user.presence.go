@@ -0,0 +1,113 @@
+// Code generated by protoc-gen-presence. DO NOT EDIT.
+// source: user/v1/user.proto
+
+package userv1
+
+import (
+	presence "github.com/pivaldi/presence"
+	presencev1 "github.com/pivaldi/presence/examples/grpc/gen/presencev1"
+	presencepb "github.com/pivaldi/presence/examples/grpc/presencepb"
+)
+
+// UserDTO is the presence DTO of User.
+type UserDTO struct {
+	Id       string              `json:"id"`
+	Username string              `json:"username"`
+	Email    presence.Of[string] `json:"email,omitzero"`
+	Bio      presence.Of[string] `json:"bio,omitzero"`
+	Age      presence.Of[int32]  `json:"age,omitzero"`
+	Nickname presence.Of[string] `json:"nickname,omitzero"`
+}
+
+// NewUserDTO converts m to its presence DTO.
+func NewUserDTO(m *User) UserDTO {
+	return UserDTO{
+		Id:       m.GetId(),
+		Username: m.GetUsername(),
+		Email:    presencepb.FromOptional(m.Email),
+		Bio:      presencepb.FromOptional(m.Bio),
+		Age:      presencepb.FromOptional(m.Age),
+		Nickname: presencepb.FromWrapper(m.Nickname),
+	}
+}
+
+// Proto converts d to its message, nil optional fields standing for null and unset ones.
+func (d UserDTO) Proto() *User {
+	m := &User{
+		Id:       d.Id,
+		Username: d.Username,
+		Email:    presencepb.ToOptional(d.Email),
+		Bio:      presencepb.ToOptional(d.Bio),
+		Age:      presencepb.ToOptional(d.Age),
+		Nickname: presencepb.ToWrapper[*presencev1.StringValue](d.Nickname),
+	}
+
+	return m
+}
+
+// GetUserRequestDTO is the presence DTO of GetUserRequest.
+type GetUserRequestDTO struct {
+	Id string `json:"id"`
+}
+
+// NewGetUserRequestDTO converts m to its presence DTO.
+func NewGetUserRequestDTO(m *GetUserRequest) GetUserRequestDTO {
+	return GetUserRequestDTO{
+		Id: m.GetId(),
+	}
+}
+
+// Proto converts d to its message, nil optional fields standing for null and unset ones.
+func (d GetUserRequestDTO) Proto() *GetUserRequest {
+	m := &GetUserRequest{
+		Id: d.Id,
+	}
+
+	return m
+}
+
+// UpdateUserRequestDTO is the presence DTO of UpdateUserRequest.
+type UpdateUserRequestDTO struct {
+	Id       string              `json:"id"`
+	Username presence.Of[string] `json:"username,omitzero"`
+	Email    presence.Of[string] `json:"email,omitzero"`
+	Bio      presence.Of[string] `json:"bio,omitzero"`
+	Age      presence.Of[int32]  `json:"age,omitzero"`
+	Nickname presence.Of[string] `json:"nickname,omitzero"`
+}
+
+// NewUpdateUserRequestDTO converts m to its presence DTO.
+func NewUpdateUserRequestDTO(m *UpdateUserRequest) UpdateUserRequestDTO {
+	return UpdateUserRequestDTO{
+		Id:       m.GetId(),
+		Username: presencepb.FromMasked(m.Username, m.GetUpdateMask(), "username"),
+		Email:    presencepb.FromMasked(m.Email, m.GetUpdateMask(), "email"),
+		Bio:      presencepb.FromMasked(m.Bio, m.GetUpdateMask(), "bio"),
+		Age:      presencepb.FromMasked(m.Age, m.GetUpdateMask(), "age"),
+		Nickname: presencepb.FromWrapper(m.Nickname),
+	}
+}
+
+// Proto converts d to its message, nil optional fields standing for null and unset ones.
+func (d UpdateUserRequestDTO) Proto() *UpdateUserRequest {
+	m := &UpdateUserRequest{
+		Id:       d.Id,
+		Username: presencepb.ToOptional(d.Username),
+		Email:    presencepb.ToOptional(d.Email),
+		Bio:      presencepb.ToOptional(d.Bio),
+		Age:      presencepb.ToOptional(d.Age),
+		Nickname: presencepb.ToWrapper[*presencev1.StringValue](d.Nickname),
+	}
+	m.UpdateMask = presencepb.MaskPath(m.UpdateMask, "username", d.Username)
+	m.UpdateMask = presencepb.MaskPath(m.UpdateMask, "email", d.Email)
+	m.UpdateMask = presencepb.MaskPath(m.UpdateMask, "bio", d.Bio)
+	m.UpdateMask = presencepb.MaskPath(m.UpdateMask, "age", d.Age)
+	m.UpdateMask = presencepb.MaskPath(m.UpdateMask, "nickname", d.Nickname)
+
+	return m
+}
+
+// PresenceDTO returns the presence DTO of m, implementing presencepb.DTOMessage.
+func (m *UpdateUserRequest) PresenceDTO() any {
+	return NewUpdateUserRequestDTO(m)
+}
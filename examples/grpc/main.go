@@ -0,0 +1,69 @@
+// Command grpc demonstrates PATCH updates over gRPC with presence.Of: the client builds
+// its request from a presence DTO and the server reads it back as one, converted by the
+// presencepb interceptor through the code generated by protoc-gen-presence.
+package main
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=github.com/pivaldi/presence/examples/grpc --go-grpc_out=. --go-grpc_opt=module=github.com/pivaldi/presence/examples/grpc --presence_out=. --presence_opt=module=github.com/pivaldi/presence/examples/grpc user/v1/user.proto presence/v1/presence.proto
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/pivaldi/presence"
+	"github.com/pivaldi/presence/examples/grpc/gen/userv1"
+	"github.com/pivaldi/presence/examples/grpc/presencepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const bufSize = 1 << 20
+
+func main() {
+	listener := bufconn.Listen(bufSize)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(presencepb.UnaryServerInterceptor()))
+	userv1.RegisterUserServiceServer(srv, newServer())
+
+	go func() {
+		err := srv.Serve(listener)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := userv1.NewUserServiceClient(conn)
+	ctx := context.Background()
+
+	// Clear the bio and the nickname, set the age and leave the other fields untouched.
+	patch := userv1.UpdateUserRequestDTO{
+		Id:       "1",
+		Bio:      presence.Null[string](),
+		Age:      presence.FromValue[int32](31),
+		Nickname: presence.Null[string](),
+	}
+
+	req := patch.Proto()
+	fmt.Println("request:", protojson.Format(req)) // update_mask: "bio,age,nickname", bio not sent, nickname: {}
+
+	user, err := client.UpdateUser(ctx, req)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("updated:", protojson.Format(user))
+}
@@ -0,0 +1,58 @@
+package presencepb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// DTOMessage is implemented by the update requests, the messages having a field mask,
+// for which protoc-gen-presence generates a PresenceDTO method converting them to their
+// presence DTO.
+type DTOMessage interface {
+	PresenceDTO() any
+}
+
+// dtoKey is the context key of the presence DTO of the request.
+type dtoKey struct{}
+
+// ContextWithDTO returns ctx carrying the presence DTO of req when req is a DTOMessage,
+// ctx otherwise. Interceptors of other RPC frameworks call it with the request:
+//
+//	// ConnectRPC
+//	connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+//		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+//			return next(presencepb.ContextWithDTO(ctx, req.Any()), req)
+//		}
+//	})
+//
+//	// twirp
+//	func(next twirp.Method) twirp.Method {
+//		return func(ctx context.Context, req any) (any, error) {
+//			return next(presencepb.ContextWithDTO(ctx, req), req)
+//		}
+//	}
+func ContextWithDTO(ctx context.Context, req any) context.Context {
+	m, ok := req.(DTOMessage)
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, dtoKey{}, m.PresenceDTO())
+}
+
+// DTOFromContext returns the presence DTO of type D stored by ContextWithDTO, so that
+// the service layer reads partial updates as presence structs, without proto types.
+func DTOFromContext[D any](ctx context.Context) (D, bool) {
+	dto, ok := ctx.Value(dtoKey{}).(D)
+
+	return dto, ok
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor storing the presence DTO of the
+// update requests in their context, see ContextWithDTO.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(ContextWithDTO(ctx, req), req)
+	}
+}
@@ -0,0 +1,115 @@
+// Package presencepb bridges proto3 optional fields and presence.Of.
+//
+// A proto3 optional field only has two states: set (HasX, a non-nil pointer with the
+// open API) or not. The third one, null, travels as a field mask listing a field which
+// is not set, as AIP-134 update requests do, or as a presence.v1 wrapper message without
+// value (see FromWrapper). The generated DTOs of protoc-gen-presence are built on these
+// functions.
+package presencepb
+
+import (
+	"slices"
+
+	"github.com/pivaldi/presence"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// wrapperValue is the number of the value field of the presence.v1 wrapper messages.
+const wrapperValue protoreflect.FieldNumber = 1
+
+// Wrapper is implemented by the presence.v1 wrapper messages (presencev1.StringValue,
+// presencev1.Int64Value…) holding values of type T.
+type Wrapper[T any] interface {
+	proto.Message
+	GetValue() T
+}
+
+// FromOptional returns the presence of a proto3 optional field: its value when set,
+// unset otherwise.
+func FromOptional[T any](v *T) presence.Of[T] {
+	if v == nil {
+		return presence.Of[T]{}
+	}
+
+	return presence.FromValue(*v)
+}
+
+// FromMasked returns the presence of the proto3 optional field at path of an update
+// request masked by mask. Without mask it is FromOptional. With a mask, fields out of
+// it are unset, and fields in it are null when not set.
+func FromMasked[T any](v *T, mask *fieldmaskpb.FieldMask, path string) presence.Of[T] {
+	if len(mask.GetPaths()) == 0 {
+		return FromOptional(v)
+	}
+
+	switch {
+	case !slices.Contains(mask.GetPaths(), path):
+		return presence.Of[T]{}
+	case v == nil:
+		return presence.Null[T]()
+	default:
+		return presence.FromValue(*v)
+	}
+}
+
+// ToOptional returns the proto3 optional value of o: a pointer to a copy of its value,
+// nil when null or unset.
+func ToOptional[T any](o presence.Of[T]) *T {
+	v, ok := o.Get()
+	if !ok {
+		return nil
+	}
+
+	return &v
+}
+
+// MaskPath adds path to mask when o is set, null or not, so that the receiver tells
+// the null fields from the unset ones. It allocates the mask when needed.
+func MaskPath[T any](mask *fieldmaskpb.FieldMask, path string, o presence.Of[T]) *fieldmaskpb.FieldMask {
+	if !o.IsSet() {
+		return mask
+	}
+
+	if mask == nil {
+		mask = &fieldmaskpb.FieldMask{}
+	}
+
+	mask.Paths = append(mask.Paths, path)
+
+	return mask
+}
+
+// FromWrapper returns the presence of a presence.v1 wrapper field: unset when the
+// message is nil, null when it has no value, its value otherwise.
+func FromWrapper[T any](m Wrapper[T]) presence.Of[T] {
+	r := m.ProtoReflect()
+
+	switch {
+	case !r.IsValid():
+		return presence.Of[T]{}
+	case !r.Has(r.Descriptor().Fields().ByNumber(wrapperValue)):
+		return presence.Null[T]()
+	default:
+		return presence.FromValue(m.GetValue())
+	}
+}
+
+// ToWrapper returns the presence.v1 wrapper message M of o: nil when unset, a message
+// without value when null.
+//
+//	m.Nickname = presencepb.ToWrapper[*presencev1.StringValue](d.Nickname)
+func ToWrapper[M Wrapper[T], T any](o presence.Of[T]) M {
+	var m M
+	if !o.IsSet() {
+		return m
+	}
+
+	r := m.ProtoReflect().Type().New()
+	if v, ok := o.Get(); ok {
+		r.Set(r.Descriptor().Fields().ByNumber(wrapperValue), protoreflect.ValueOf(v))
+	}
+
+	return r.Interface().(M)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pivaldi/presence/examples/grpc/gen/presencev1"
+	"github.com/pivaldi/presence/examples/grpc/gen/userv1"
+	"github.com/pivaldi/presence/examples/grpc/presencepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// server is the UserService with in-memory storage.
+type server struct {
+	userv1.UnimplementedUserServiceServer
+
+	mu    sync.Mutex
+	users map[string]*userv1.User
+}
+
+// newServer creates a server with seed data.
+func newServer() *server {
+	return &server{
+		users: map[string]*userv1.User{
+			"1": {
+				Id:       "1",
+				Username: "alice",
+				Email:    proto.String("alice@example.com"),
+				Bio:      proto.String("Software developer"),
+				Age:      proto.Int32(30),
+				Nickname: &presencev1.StringValue{Value: proto.String("ali")},
+			},
+		},
+	}
+}
+
+// GetUser returns the user of the given id.
+func (s *server) GetUser(_ context.Context, req *userv1.GetUserRequest) (*userv1.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[req.GetId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user not found: %s", req.GetId())
+	}
+
+	return user, nil
+}
+
+// UpdateUser demonstrates the 3-state handling of the request read as a presence DTO,
+// converted by presencepb.UnaryServerInterceptor: unset fields are left untouched, null
+// fields are cleared.
+func (s *server) UpdateUser(ctx context.Context, _ *userv1.UpdateUserRequest) (*userv1.User, error) {
+	patch, ok := presencepb.DTOFromContext[userv1.UpdateUserRequestDTO](ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "presencepb interceptor not installed")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[patch.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user not found: %s", patch.Id)
+	}
+
+	// Username: required field, cannot be set to null
+	if patch.Username.IsNull() {
+		return nil, status.Error(codes.InvalidArgument, "username cannot be null")
+	}
+
+	dto := userv1.NewUserDTO(user)
+	if patch.Username.IsValue() {
+		dto.Username = patch.Username.MustGet()
+	}
+
+	// Optional fields: set to the value, or cleared when null
+	if patch.Email.IsSet() {
+		dto.Email = patch.Email
+	}
+
+	if patch.Bio.IsSet() {
+		dto.Bio = patch.Bio
+	}
+
+	if patch.Age.IsSet() {
+		dto.Age = patch.Age
+	}
+
+	if patch.Nickname.IsSet() {
+		dto.Nickname = patch.Nickname
+	}
+
+	s.users[patch.Id] = dto.Proto()
+
+	return s.users[patch.Id], nil
+}
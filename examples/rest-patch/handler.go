@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pivaldi/presence"
+	"gorm.io/gorm"
+)
+
+const maxBioLength = 280
+
+// handler serves the users API.
+type handler struct {
+	db *gorm.DB
+}
+
+func newRouter(db *gorm.DB) *gin.Engine {
+	h := &handler{db: db}
+	r := gin.Default()
+	r.GET("/users/:id", h.getUser)
+	r.PATCH("/users/:id", h.patchUser)
+	r.GET("/users/:id/audit", h.getAudit)
+
+	return r
+}
+
+func (h *handler) getUser(c *gin.Context) {
+	var user User
+	if !h.find(c, &user) {
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *handler) getAudit(c *gin.Context) {
+	var entries []AuditEntry
+	err := h.db.Where("user_id = ?", c.Param("id")).Order("id").Find(&entries).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// patchUser is the end-to-end PATCH flow: bind the body into a presence struct,
+// validate it, then update the changed columns and record an audit entry per change.
+func (h *handler) patchUser(c *gin.Context) {
+	var patch UserPatch
+	err := c.ShouldBindJSON(&patch)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+
+		return
+	}
+
+	if errs := patch.validate(); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, errs) // {"errors":{"username":"cannot be null"}}
+
+		return
+	}
+
+	var user User
+	if !h.find(c, &user) {
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		return applyPatch(tx, &user, patch)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// find loads the user of the id parameter, writing the error response when it fails.
+func (h *handler) find(c *gin.Context, user *User) bool {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+
+		return false
+	}
+
+	err = h.db.First(user, id).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+
+		return false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+		return false
+	}
+
+	return true
+}
+
+// validate returns the errors of the patch keyed by field.
+// Fields which are not sent are not validated.
+func (p *UserPatch) validate() presence.FieldErrors {
+	errs := presence.FieldErrors{}
+
+	// Required column: it may be left out, but not cleared
+	if p.Username.IsNull() {
+		errs["username"] = errors.New("cannot be null")
+	}
+
+	if v, ok := p.Username.Get(); ok && strings.TrimSpace(v) == "" {
+		errs["username"] = errors.New("cannot be empty")
+	}
+
+	if v, ok := p.Email.Get(); ok && !strings.Contains(v, "@") {
+		errs["email"] = errors.New("must be an email address")
+	}
+
+	if v, ok := p.Bio.Get(); ok && len(v) > maxBioLength {
+		errs["bio"] = fmt.Errorf("must be at most %d characters", maxBioLength)
+	}
+
+	if v, ok := p.Age.Get(); ok && v < 0 {
+		errs["age"] = errors.New("must be positive")
+	}
+
+	return errs
+}
+
+// applyPatch updates the columns of user changed by patch and audits the changes.
+func applyPatch(tx *gorm.DB, user *User, patch UserPatch) error {
+	// Audit diff: the current values against the patched ones
+	before := snapshot(*user)
+	after := before
+	err := presence.PatchStruct(&after, patch)
+	if err != nil {
+		return fmt.Errorf("patching: %w", err)
+	}
+
+	changes, err := presence.Diff(before, after, presence.WithTag("db"))
+	if err != nil {
+		return fmt.Errorf("diffing: %w", err)
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// GORM update map: the sent fields keyed by column, nil for null
+	updates, err := presence.ToMap(patch, presence.WithTag("db"))
+	if err != nil {
+		return fmt.Errorf("building updates: %w", err)
+	}
+
+	err = tx.Model(user).Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("updating user: %w", err)
+	}
+
+	entries := make([]AuditEntry, 0, len(changes))
+	for _, change := range changes {
+		entries = append(entries, AuditEntry{
+			UserID: user.ID,
+			Field:  change.Field,
+			Before: jsonString(change.From),
+			After:  jsonString(change.To),
+		})
+	}
+
+	err = tx.Create(&entries).Error
+	if err != nil {
+		return fmt.Errorf("auditing: %w", err)
+	}
+
+	return tx.First(user, user.ID).Error
+}
+
+func jsonString(v any) string {
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
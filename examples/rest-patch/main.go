@@ -0,0 +1,52 @@
+// Package main demonstrates the primary use case of presence.Of: a REST PATCH endpoint.
+//
+// PATCH /users/:id binds the body into a presence struct, validates it (required
+// columns cannot be cleared), updates the sent columns through a GORM update map and
+// records an audit diff of the changes.
+//
+// Usage:
+//
+//	cd examples/rest-patch
+//	go run .
+//
+// The data lives in an in-memory SQLite database, which needs cgo.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func main() {
+	db, err := openDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Println("REST server running at http://localhost:8182/")
+	log.Fatal(newRouter(db).Run(":8182"))
+}
+
+// openDB opens the in-memory database with seed data.
+func openDB() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	err = db.AutoMigrate(&User{}, &AuditEntry{})
+	if err != nil {
+		return nil, fmt.Errorf("migrating: %w", err)
+	}
+
+	email, bio, age := "alice@example.com", "Software developer", 30
+	err = db.Create(&User{ID: 1, Username: "alice", Email: &email, Bio: &bio, Age: &age}).Error
+	if err != nil {
+		return nil, fmt.Errorf("seeding: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"time"
+
+	"github.com/pivaldi/presence"
+)
+
+// User is the GORM model of the users table.
+// Nullable columns use pointers.
+type User struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Email     *string   `json:"email"`
+	Bio       *string   `json:"bio"`
+	Age       *int      `json:"age"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserPatch is the body of PATCH /users/:id, using presence.Of[T] to distinguish:
+// - Field not sent (IsUnset): left untouched
+// - Field explicitly set to null (IsNull): cleared
+// - Field has a value (IsValue): updated
+//
+// The json tags name the API fields, the db tags the columns.
+type UserPatch struct {
+	Username presence.Of[string] `json:"username" db:"username"`
+	Email    presence.Of[string] `json:"email"    db:"email"`
+	Bio      presence.Of[string] `json:"bio"      db:"bio"`
+	Age      presence.Of[int]    `json:"age"      db:"age"`
+}
+
+// snapshot returns the patchable fields of u, all set, to diff against.
+func snapshot(u User) UserPatch {
+	var s UserPatch
+	s.Username.SetValue(u.Username)
+	s.Email.SetValueP(u.Email)
+	s.Bio.SetValueP(u.Bio)
+	s.Age.SetValueP(u.Age)
+
+	return s
+}
+
+// AuditEntry records the change of a user column.
+// Before and After hold the JSON encoding of the values, null included.
+type AuditEntry struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Field     string    `json:"field"`
+	Before    string    `json:"before"`
+	After     string    `json:"after"`
+	CreatedAt time.Time `json:"created_at"`
+}
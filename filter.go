@@ -0,0 +1,199 @@
+package presence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSort is returned for sort fields the API does not allow.
+var ErrInvalidSort = errors.New("presence: invalid sort field")
+
+// Range is an optional range filter of list endpoints, such as ?from=…&to=… on a date.
+// Its bounds are inclusive, and open when unset or null:
+//
+//	type ListOrders struct {
+//		CreatedAt presence.Range[time.Time] `json:"created_at"`
+//		Sort      presence.Sort             `json:"sort"`
+//		Page      presence.Page             `json:"page"`
+//	}
+type Range[T any] struct {
+	From Of[T] `json:"from"`
+	To   Of[T] `json:"to"`
+}
+
+// IsOpen reports whether r has no bound, filtering nothing.
+func (r Range[T]) IsOpen() bool {
+	return !r.From.IsValue() && !r.To.IsValue()
+}
+
+// SQL returns the condition of r on column, with ? placeholders, and its args, "" when
+// r is open:
+//
+//	cond, args := req.CreatedAt.SQL("created_at") // created_at >= ? AND created_at <= ?
+func (r Range[T]) SQL(column string) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+
+	if from, ok := r.From.Get(); ok {
+		conds, args = append(conds, column+" >= ?"), append(args, from)
+	}
+
+	if to, ok := r.To.Get(); ok {
+		conds, args = append(conds, column+" <= ?"), append(args, to)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// Mongo returns the filter of r on field, nil when r is open, to be merged into a
+// MongoDB filter document:
+//
+//	filter := bson.M{"status": "paid"}
+//	maps.Copy(filter, req.CreatedAt.Mongo("created_at")) // {"created_at": {"$gte": …, "$lte": …}}
+func (r Range[T]) Mongo(field string) map[string]any {
+	cond := map[string]any{}
+	if from, ok := r.From.Get(); ok {
+		cond["$gte"] = from
+	}
+
+	if to, ok := r.To.Get(); ok {
+		cond["$lte"] = to
+	}
+
+	if len(cond) == 0 {
+		return nil
+	}
+
+	return map[string]any{field: cond}
+}
+
+// Sort is the optional sort order of list endpoints. It is written as the field name,
+// prefixed with "-" for a descending order, e.g. ?sort=-created_at, in text and JSON.
+type Sort struct {
+	Field Of[string]
+	Desc  bool
+}
+
+// ParseSort parses a sort order written like "-created_at", "" giving an unset field.
+func ParseSort(s string) Sort {
+	var sort Sort
+	if s == "" {
+		return sort
+	}
+
+	field, desc := strings.CutPrefix(s, "-")
+	sort.Field.SetValue(strings.TrimPrefix(field, "+"))
+	sort.Desc = desc
+
+	return sort
+}
+
+// String returns the sort order written like "-created_at", "" when the field is unset.
+func (s Sort) String() string {
+	field, ok := s.Field.Get()
+	if !ok {
+		return ""
+	}
+
+	if s.Desc {
+		return "-" + field
+	}
+
+	return field
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s Sort) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (s *Sort) UnmarshalText(text []byte) error {
+	*s = ParseSort(string(text))
+
+	return nil
+}
+
+// SQL returns the ORDER BY clause of s, "" when the field is unset. columns maps the
+// sort fields allowed by the API to their columns, the others being rejected with
+// ErrInvalidSort so that no client input reaches the query:
+//
+//	orderBy, err := req.Sort.SQL(map[string]string{"created_at": "o.created_at", "total": "o.total"})
+func (s Sort) SQL(columns map[string]string) (string, error) {
+	column, ok, err := s.lookup(columns)
+	if !ok || err != nil {
+		return "", err
+	}
+
+	if s.Desc {
+		return "ORDER BY " + column + " DESC", nil
+	}
+
+	return "ORDER BY " + column + " ASC", nil
+}
+
+// Mongo returns the sort document of s, nil when the field is unset, fields mapping the
+// sort fields allowed by the API to their document fields like columns does for SQL.
+func (s Sort) Mongo(fields map[string]string) (map[string]any, error) {
+	field, ok, err := s.lookup(fields)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	if s.Desc {
+		return map[string]any{field: -1}, nil
+	}
+
+	return map[string]any{field: 1}, nil
+}
+
+// lookup returns the name mapped to the field of s by names, reporting false when the
+// field is unset.
+func (s Sort) lookup(names map[string]string) (string, bool, error) {
+	field, ok := s.Field.Get()
+	if !ok {
+		return "", false, nil
+	}
+
+	name, ok := names[field]
+	if !ok {
+		return "", false, fmt.Errorf("%w : %q", ErrInvalidSort, field)
+	}
+
+	return name, true, nil
+}
+
+// Page is the optional pagination of list endpoints, its Number starting at 1.
+type Page struct {
+	Number Of[int] `json:"number"`
+	Size   Of[int] `json:"size"`
+}
+
+// Bounds returns the limit and the offset of p: unset, null or invalid sizes give
+// defaultSize, sizes above maxSize give maxSize, and numbers below 1 give the first
+// page. With MongoDB:
+//
+//	limit, offset := req.Page.Bounds(20, 100)
+//	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset))
+func (p Page) Bounds(defaultSize, maxSize int) (int, int) {
+	size := p.Size.GetOr(defaultSize)
+	if size < 1 {
+		size = defaultSize
+	}
+
+	size = min(size, maxSize)
+	number := max(p.Number.GetOr(1), 1)
+
+	return size, (number - 1) * size
+}
+
+// SQL returns the LIMIT and OFFSET clause of p, see Bounds.
+func (p Page) SQL(defaultSize, maxSize int) string {
+	limit, offset := p.Bounds(defaultSize, maxSize)
+
+	return "LIMIT " + strconv.Itoa(limit) + " OFFSET " + strconv.Itoa(offset)
+}
@@ -0,0 +1,209 @@
+package presence
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of the hashed values, written before them so that values of different types
+// held by an Of[any] do not collide.
+const (
+	hashString byte = iota + 1
+	hashBool
+	hashInt
+	hashUint
+	hashFloat
+	hashTime
+	hashBytes
+	hashUUID
+	hashJSON
+	hashBigInt
+)
+
+// Hash writes the state of n, and its value when it has one, to h, so that caches and
+// change detection can fingerprint presence values: unset, null and each value hash
+// differently. Strings, booleans, numbers, big integers, times, byte slices and UUIDs
+// are written in binary, other values as their canonical JSON (see MarshalCanonical).
+func Hash[T any](n Of[T], h hash.Hash64) error {
+	var value any
+	if n.IsValue() {
+		value = *n.val
+	}
+
+	return hashValue(h, n.State(), value)
+}
+
+// HashStruct writes the names of the fields of the struct v, the states of its presence
+// fields and their values to h, as Hash does. Plain fields are hashed as values.
+//
+//	h := fnv.New64a()
+//	err := presence.HashStruct(patch, h)
+//	key := h.Sum64()
+func HashStruct(v any, h hash.Hash64, opts ...Option) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	walkFields(rv.Type(), nil, newOptions(opts), func(name string, index []int, isPresence bool) {
+		if err != nil {
+			return
+		}
+
+		writeHashString(h, name)
+
+		field := rv.FieldByIndex(index)
+		if isPresence {
+			pf := field.Addr().Interface().(presenceField)
+			err = hashValue(h, pf.State(), pf.anyValue())
+
+			return
+		}
+
+		err = hashValue(h, StateValue, field.Interface())
+		if err != nil {
+			err = fmt.Errorf("presence hashing field %s : %w", name, err)
+		}
+	})
+
+	return err
+}
+
+// CacheKey returns a stable key of the struct v, the hex SHA-256 of its fields sorted
+// by name, so that request DTOs with presence filters key memoization caches
+// deterministically:
+//
+//	key, err := presence.CacheKey(req)
+//	if users, ok := cache.Get("users:" + key); ok {
+//		return users
+//	}
+//
+// Values are hashed like HashStruct does, but only the set fields: unset presence fields
+// are left out, so that adding an optional filter to a DTO keeps the keys of the
+// requests which do not send it. Keys do not depend on the type of v, prefix them per
+// cache.
+func CacheKey(v any, opts ...Option) (string, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return "", err
+	}
+
+	type cacheField struct {
+		name  string
+		state State
+		value any
+	}
+
+	var fields []cacheField
+
+	walkFields(rv.Type(), nil, newOptions(opts), func(name string, index []int, isPresence bool) {
+		field := rv.FieldByIndex(index)
+		if !isPresence {
+			fields = append(fields, cacheField{name: name, state: StateValue, value: field.Interface()})
+
+			return
+		}
+
+		if pf := field.Addr().Interface().(presenceField); pf.State() != StateUnset {
+			fields = append(fields, cacheField{name: name, state: pf.State(), value: pf.anyValue()})
+		}
+	})
+
+	slices.SortStableFunc(fields, func(a, b cacheField) int { return strings.Compare(a.name, b.name) })
+
+	h := sha256.New()
+	for _, f := range fields {
+		writeHashString(h, f.name)
+
+		err := hashValue(h, f.state, f.value)
+		if err != nil {
+			return "", fmt.Errorf("presence hashing field %s : %w", f.name, err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashValue(h hash.Hash, state State, v any) error {
+	_, _ = h.Write([]byte{byte(state)})
+	if state != StateValue {
+		return nil
+	}
+
+	var buf [9]byte
+
+	switch v := v.(type) {
+	case string:
+		writeHashString(h, v)
+	case bool:
+		_, _ = h.Write([]byte{hashBool, boolByte(v)})
+	case int:
+		writeHashUint64(h, hashInt, &buf, uint64(v))
+	case int16:
+		writeHashUint64(h, hashInt, &buf, uint64(v))
+	case int32:
+		writeHashUint64(h, hashInt, &buf, uint64(v))
+	case int64:
+		writeHashUint64(h, hashInt, &buf, uint64(v))
+	case uint64:
+		writeHashUint64(h, hashUint, &buf, v)
+	case float64:
+		writeHashUint64(h, hashFloat, &buf, math.Float64bits(v))
+	case time.Time:
+		writeHashUint64(h, hashTime, &buf, uint64(v.Unix()))
+		writeHashUint64(h, hashTime, &buf, uint64(v.Nanosecond()))
+	case []byte:
+		writeHashUint64(h, hashBytes, &buf, uint64(len(v)))
+		_, _ = h.Write(v)
+	case uuid.UUID:
+		_, _ = h.Write([]byte{hashUUID})
+		_, _ = h.Write(v[:])
+	case big.Int:
+		b := v.Append(nil, 10)
+		writeHashUint64(h, hashBigInt, &buf, uint64(len(b)))
+		_, _ = h.Write(b)
+	default:
+		b, err := MarshalCanonical(v)
+		if err != nil {
+			return err
+		}
+
+		writeHashUint64(h, hashJSON, &buf, uint64(len(b)))
+		_, _ = h.Write(b)
+	}
+
+	return nil
+}
+
+// writeHashString writes s prefixed with its length, so that consecutive strings are
+// delimited.
+func writeHashString(h hash.Hash, s string) {
+	var buf [9]byte
+
+	writeHashUint64(h, hashString, &buf, uint64(len(s)))
+	_, _ = h.Write([]byte(s))
+}
+
+func writeHashUint64(h hash.Hash, kind byte, buf *[9]byte, v uint64) {
+	buf[0] = kind
+	binary.LittleEndian.PutUint64(buf[1:], v)
+	_, _ = h.Write(buf[:])
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,173 @@
+package presence
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidHstore is returned by Scan for text that is neither an hstore literal nor a
+// JSON object.
+var ErrInvalidHstore = errors.New("presence: invalid hstore")
+
+// hstoreValue encodes m as an hstore literal, its keys sorted.
+func hstoreValue(m map[string]string) string {
+	var b strings.Builder
+
+	for i, key := range slices.Sorted(maps.Keys(m)) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		writeHstoreText(&b, key)
+		b.WriteString("=>")
+		writeHstoreText(&b, m[key])
+	}
+
+	return b.String()
+}
+
+// writeHstoreText writes the double-quoted key or value s to b.
+func writeHstoreText(b *strings.Builder, s string) {
+	b.WriteByte('"')
+
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	b.WriteByte('"')
+}
+
+// scanHstore decodes v, an hstore literal or a JSON object for the databases without
+// hstore, into n, which holds a map[string]string.
+func (n *Of[T]) scanHstore(v any) error {
+	var text string
+
+	switch value := v.(type) {
+	case nil:
+		n.handleScanNull()
+
+		return nil
+	case string:
+		text = value
+	case []byte:
+		text = string(value)
+	default:
+		return n.scanJSON(v)
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return n.scanJSON(v)
+	}
+
+	m, err := parseHstore(text)
+	if err != nil {
+		return err
+	}
+
+	n.setScanned(any(m).(T))
+
+	return nil
+}
+
+// parseHstore decodes the hstore literal s. Keys holding NULL are left out, a map having
+// no absent value distinct from NULL.
+func parseHstore(s string) (map[string]string, error) {
+	p := hstoreParser{s: s}
+	m := map[string]string{}
+
+	for p.skipSpaces(); p.pos < len(p.s); p.skipSpaces() {
+		key, quoted, err := p.text()
+		if err != nil {
+			return nil, err
+		}
+
+		if !quoted && key == "" {
+			return nil, p.errorf("missing key")
+		}
+
+		p.skipSpaces()
+		if !strings.HasPrefix(p.s[p.pos:], "=>") {
+			return nil, p.errorf("missing =>")
+		}
+
+		p.pos += len("=>")
+		p.skipSpaces()
+
+		value, quoted, err := p.text()
+		if err != nil {
+			return nil, err
+		}
+
+		if !quoted && value == "" {
+			return nil, p.errorf("missing value")
+		}
+
+		if quoted || !strings.EqualFold(value, "NULL") {
+			m[key] = value
+		}
+
+		p.skipSpaces()
+		if p.pos < len(p.s) {
+			if p.s[p.pos] != ',' {
+				return nil, p.errorf("missing ,")
+			}
+
+			p.pos++
+		}
+	}
+
+	return m, nil
+}
+
+// hstoreParser reads an hstore literal s from pos.
+type hstoreParser struct {
+	s   string
+	pos int
+}
+
+func (p *hstoreParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// text reads a key or a value, reporting whether it was quoted.
+func (p *hstoreParser) text() (string, bool, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		var b strings.Builder
+
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			switch c := p.s[p.pos]; {
+			case c == '\\' && p.pos+1 < len(p.s):
+				p.pos++
+				b.WriteByte(p.s[p.pos])
+			case c == '"':
+				p.pos++
+
+				return b.String(), true, nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+
+		return "", false, p.errorf("unterminated quote")
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r,=", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+
+	return p.s[start:p.pos], false, nil
+}
+
+func (p *hstoreParser) errorf(reason string) error {
+	return fmt.Errorf("%w : %s at %d in %q", ErrInvalidHstore, reason, p.pos, p.s)
+}
@@ -0,0 +1,228 @@
+package presence
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Insert is the insertion of a row from a presence struct. The columns of the unset
+// fields are left out, so that the database fills them with their DEFAULT, while null
+// fields are inserted as NULL. Plain fields are inserted as they are, overriding the
+// column default, except the generated ones holding their zero value: the fields tagged
+// presence:"auto", gorm:"primaryKey" or gorm:"autoIncrement":
+//
+//	type CreateUserRequest struct {
+//		ID      int64               `db:"id" presence:"auto"` // serial
+//		Email   string              `db:"email"`
+//		Name    presence.Of[string] `db:"name"`
+//		Country presence.Of[string] `db:"country"` // DEFAULT 'FR'
+//	}
+type Insert struct {
+	// Columns holds the columns of the plain fields and of the set presence fields,
+	// in the order of the fields.
+	Columns []string
+	// Values holds the values of Columns, presence values being encoded by their
+	// driver.Valuer, null ones as NULL.
+	Values []any
+}
+
+// NewInsert returns the insertion of the struct row, whose fields are named according
+// to the options. Fields tagged presence:"apionly" are left out, like by ToMap.
+func NewInsert(row any, opts ...Option) (Insert, error) {
+	o := newOptions(opts)
+	rv, err := structValue(row)
+	if err != nil {
+		return Insert{}, err
+	}
+
+	var ins Insert
+
+	walkFields(rv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		f := rv.FieldByIndex(index)
+		sf := rv.Type().FieldByIndex(index)
+		if isPresence && f.Addr().Interface().(presenceField).State() == StateUnset ||
+			!isPresence && f.IsZero() && isGenerated(sf) || hasPresenceOption(sf, "apionly") {
+			return
+		}
+
+		ins.Columns = append(ins.Columns, name)
+		ins.Values = append(ins.Values, f.Interface())
+	})
+
+	return ins, nil
+}
+
+// isGenerated reports whether the database generates the column of the plain field f:
+// f is tagged presence:"auto", or gorm:"primaryKey" or gorm:"autoIncrement".
+func isGenerated(f reflect.StructField) bool {
+	if hasPresenceOption(f, "auto") {
+		return true
+	}
+
+	for o := range strings.SplitSeq(f.Tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(o), ":")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "primarykey", "primary_key", "autoincrement":
+			return !strings.EqualFold(strings.TrimSpace(value), "false")
+		}
+	}
+
+	return false
+}
+
+// SQL returns the INSERT statement of table, with ? placeholders (rebind them for
+// PostgreSQL, e.g. with sqlx's Rebind), and its args:
+//
+//	query, args := ins.SQL("users")
+//	res, err := db.ExecContext(ctx, db.Rebind(query), args...)
+//	// INSERT INTO users (email, name) VALUES (?, ?)
+//
+// Without columns it returns INSERT INTO table DEFAULT VALUES, which PostgreSQL and
+// SQLite accept but MySQL does not. The table and the column names are written as they
+// are: they must be trusted identifiers, quoted beforehand when they need to be.
+func (i Insert) SQL(table string) (string, []any) {
+	if len(i.Columns) == 0 {
+		return "INSERT INTO " + table + " DEFAULT VALUES", nil
+	}
+
+	return InsertSQL(table, i.Columns, [][]any{i.Values})
+}
+
+// InsertSQL returns the INSERT statement of the rows of values of columns into table,
+// such as returned by InsertColumnsValues, with ? placeholders, and its args. The Default
+// sentinel and the unset presence values configured with ValueUnsetDefault are written
+// as DEFAULT rather than bound, database/sql rejecting them as args, so that the padded
+// fields get their column default:
+//
+//	columns, rows, err := presence.InsertColumnsValues(users, presence.WithTag("db"),
+//		presence.WithPadding(presence.Default))
+//	query, args := presence.InsertSQL("users", columns, rows)
+//	// INSERT INTO users (id, name, age) VALUES (?, ?, DEFAULT), (?, ?, ?)
+//
+// The table and the column names are written as they are, like by Insert.SQL.
+func InsertSQL(table string, columns []string, rows [][]any) (string, []any) {
+	var b strings.Builder
+	b.WriteString("INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES ")
+
+	var args []any
+
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteByte('(')
+
+		for j, v := range row {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+
+			if isDefault(v) {
+				b.WriteString("DEFAULT")
+
+				continue
+			}
+
+			b.WriteByte('?')
+			args = append(args, v)
+		}
+
+		b.WriteByte(')')
+	}
+
+	return b.String(), args
+}
+
+// isDefault reports whether v stands for the SQL DEFAULT keyword: the Default sentinel
+// or an unset presence value configured with ValueUnsetDefault.
+func isDefault(v any) bool {
+	if _, ok := v.(defaultValue); ok {
+		return true
+	}
+
+	d, ok := v.(interface{ isDefault() bool })
+
+	return ok && d.isDefault()
+}
+
+// Map returns the values of the insertion by column, for the query builders taking
+// maps, such as squirrel's SetMap or GORM's Create from a map:
+//
+//	query, args, err := sq.Insert("users").SetMap(ins.Map()).ToSql()
+//	err = db.Table("users").Create(ins.Map()).Error
+func (i Insert) Map() map[string]any {
+	m := make(map[string]any, len(i.Columns))
+	for j, column := range i.Columns {
+		m[column] = i.Values[j]
+	}
+
+	return m
+}
+
+// BuildUpsert returns the INSERT statement of row into table, built like NewInsert, with
+// an ON CONFLICT clause updating the set fields when the row already exists, and its
+// args. The conflict target is made of the fields tagged presence:"conflict", which
+// must be set:
+//
+//	type UpsertUserRequest struct {
+//		Email string              `db:"email" presence:"conflict"`
+//		Name  presence.Of[string] `db:"name"`
+//		Bio   presence.Of[string] `db:"bio"`
+//	}
+//
+//	query, args, err := presence.BuildUpsert("users", req, presence.WithTag("db"))
+//	// INSERT INTO users (email, name) VALUES (?, ?)
+//	//   ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name
+//
+// Unset fields, and the fields inserted as DEFAULT (see InsertSQL), keep their column
+// value on conflict, and their DEFAULT on insert. The
+// clause follows PostgreSQL and SQLite, with ? placeholders; without fields to update
+// it is DO NOTHING. The table and the column names are written as they are, like by
+// Insert.SQL.
+func BuildUpsert(table string, row any, opts ...Option) (string, []any, error) {
+	ins, err := NewInsert(row, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	o := newOptions(opts)
+	rv, _ := structValue(row)
+
+	var conflict []string
+
+	walkFields(rv.Type(), nil, o, func(name string, index []int, _ bool) {
+		if hasPresenceOption(rv.Type().FieldByIndex(index), "conflict") {
+			conflict = append(conflict, name)
+		}
+	})
+
+	if len(conflict) == 0 {
+		return "", nil, fmt.Errorf("presence upsert : %s has no field tagged presence:\"conflict\"", rv.Type())
+	}
+
+	for _, column := range conflict {
+		if !slices.Contains(ins.Columns, column) {
+			return "", nil, fmt.Errorf("presence upsert : conflict field %s is unset", column)
+		}
+	}
+
+	var updates []string
+
+	for j, column := range ins.Columns {
+		if !slices.Contains(conflict, column) && !isDefault(ins.Values[j]) {
+			updates = append(updates, column+" = EXCLUDED."+column)
+		}
+	}
+
+	query, args := ins.SQL(table)
+	query += " ON CONFLICT (" + strings.Join(conflict, ", ") + ")"
+
+	if len(updates) == 0 {
+		return query + " DO NOTHING", args, nil
+	}
+
+	return query + " DO UPDATE SET " + strings.Join(updates, ", "), args, nil
+}
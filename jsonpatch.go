@@ -0,0 +1,294 @@
+package presence
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrPatchTest is returned by ApplyJSONPatch when a test operation fails.
+var ErrPatchTest = errors.New("presence: JSON patch test failed")
+
+// errPathNotFound is returned for paths to unset or missing fields.
+var errPathNotFound = errors.New("path not found")
+
+// patchOperation is an operation of a JSON Patch document.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// JSONPatchOption configures ApplyJSONPatch: an Option or WithRemoveNull.
+type JSONPatchOption interface {
+	applyJSONPatch(o *options)
+}
+
+func (f Option) applyJSONPatch(o *options) { f(o) }
+
+type jsonPatchOption func(*options)
+
+func (f jsonPatchOption) applyJSONPatch(o *options) { f(o) }
+
+// WithRemoveNull makes ApplyJSONPatch set the presence fields removed by remove and move
+// operations to null instead of unsetting them.
+func WithRemoveNull() JSONPatchOption {
+	return jsonPatchOption(func(o *options) {
+		o.removeNull = true
+	})
+}
+
+// ApplyJSONPatch applies the JSON Patch (RFC 6902) document patch to the struct pointed
+// to by dst, with the semantics of presence values: unset fields are absent from the
+// document, so that add sets them while replace, remove and test require them set,
+// and remove unsets them, or sets them null with WithRemoveNull.
+//
+//	err := presence.ApplyJSONPatch(&user, []byte(`[
+//		{"op": "test", "path": "/version", "value": 3},
+//		{"op": "replace", "path": "/email", "value": "ada@example.com"},
+//		{"op": "remove", "path": "/nickname"}
+//	]`))
+//
+// Paths name the fields as given by the options (json tags by default), nested structs
+// being reached through struct, pointer and presence fields; array elements and map
+// entries cannot be addressed, the whole field must be replaced. Plain fields are
+// always present and removing them zeroes them. The patch is atomic: dst is left
+// untouched when an operation fails.
+func ApplyJSONPatch(dst any, patch []byte, opts ...JSONPatchOption) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence JSON patch destination must be a non-nil struct pointer, got %T", dst)
+	}
+
+	var ops []patchOperation
+
+	err := json.Unmarshal(patch, &ops)
+	if err != nil {
+		return fmt.Errorf("presence JSON patch unmarshaling : %w", err)
+	}
+
+	// Operations apply to a copy, nested structs being copied before being written.
+	o := newOptions(nil)
+	for _, opt := range opts {
+		opt.applyJSONPatch(o)
+	}
+
+	o.nested = NestedValue
+	work := reflect.New(dv.Elem().Type()).Elem()
+	work.Set(dv.Elem())
+
+	for i, op := range ops {
+		err := o.applyOperation(work, op)
+		if err != nil {
+			return fmt.Errorf("presence JSON patch operation %d (%s %s) : %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	dv.Elem().Set(work)
+
+	return nil
+}
+
+// applyOperation applies op to the struct root.
+func (o *options) applyOperation(root reflect.Value, op patchOperation) error {
+	switch op.Op {
+	case "add", "replace":
+		if op.Value == nil {
+			return errors.New("missing value")
+		}
+
+		return o.atPath(root, op.Path, func(f reflect.Value) error {
+			if op.Op == "replace" && isUnsetField(f) {
+				return errPathNotFound
+			}
+
+			return o.mergeField(f, op.Value)
+		})
+	case "remove":
+		return o.atPath(root, op.Path, o.removeField)
+	case "test":
+		if op.Value == nil {
+			return errors.New("missing value")
+		}
+
+		return o.testPath(root, op.Path, op.Value)
+	case "move", "copy":
+		value, err := o.readPath(root, op.From)
+		if err != nil {
+			return fmt.Errorf("from %s : %w", op.From, err)
+		}
+
+		if op.Op == "move" {
+			err = o.atPath(root, op.From, o.removeField)
+			if err != nil {
+				return err
+			}
+		}
+
+		return o.atPath(root, op.Path, func(f reflect.Value) error { return o.mergeField(f, value) })
+	}
+
+	return fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// readPath returns the JSON encoding of the field at path in the struct root.
+func (o *options) readPath(root reflect.Value, path string) (json.RawMessage, error) {
+	// Reading through presence values writes them back: read from a copy.
+	scratch := reflect.New(root.Type()).Elem()
+	scratch.Set(root)
+
+	var value json.RawMessage
+
+	err := o.atPath(scratch, path, func(f reflect.Value) error {
+		if isUnsetField(f) {
+			return errPathNotFound
+		}
+
+		b, err := json.Marshal(f.Addr().Interface())
+		if err != nil {
+			return fmt.Errorf("presence JSON patch marshaling : %w", err)
+		}
+
+		value = b
+
+		return nil
+	})
+
+	return value, err
+}
+
+// testPath checks that the field at path in the struct root equals the JSON value.
+func (o *options) testPath(root reflect.Value, path string, value json.RawMessage) error {
+	current, err := o.readPath(root, path)
+	if err != nil {
+		return err
+	}
+
+	a, err := CanonicalizeJSON(current)
+	if err != nil {
+		return err
+	}
+
+	b, err := CanonicalizeJSON(value)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(a, b) {
+		return fmt.Errorf("%w : %s is %s", ErrPatchTest, path, current)
+	}
+
+	return nil
+}
+
+// removeField unsets the field f, or sets it null with WithRemoveNull.
+func (o *options) removeField(f reflect.Value) error {
+	if isUnsetField(f) {
+		return errPathNotFound
+	}
+
+	pf, ok := f.Addr().Interface().(presenceField)
+	if !ok {
+		f.SetZero()
+
+		return nil
+	}
+
+	if o.removeNull {
+		pf.SetNull()
+	} else {
+		pf.Unset()
+	}
+
+	return nil
+}
+
+// atPath calls fn with the field at the JSON Pointer path in the struct v, copying the
+// nested structs it goes through so that their copies in other values are untouched.
+func (o *options) atPath(v reflect.Value, path string, fn func(f reflect.Value) error) error {
+	if path == "" || path[0] != '/' {
+		return fmt.Errorf("invalid path %q", path)
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return o.atTokens(v, tokens, fn)
+}
+
+// atTokens calls fn with the field at the path tokens in the struct v.
+func (o *options) atTokens(v reflect.Value, tokens []string, fn func(f reflect.Value) error) error {
+	var index []int
+	walkFields(v.Type(), nil, o, func(name string, i []int, _ bool) {
+		if name == tokens[0] && index == nil {
+			index = i
+		}
+	})
+
+	if index == nil {
+		return errPathNotFound
+	}
+
+	f := v.FieldByIndex(index)
+	if len(tokens) == 1 {
+		return fn(f)
+	}
+
+	if pf, ok := f.Addr().Interface().(presenceField); ok {
+		if pf.State() != StateValue {
+			return errPathNotFound
+		}
+
+		nested := reflect.New(reflect.TypeOf(pf.anyValue())).Elem()
+		nested.Set(reflect.ValueOf(pf.anyValue()))
+
+		err := o.atNested(nested, tokens[1:], fn)
+		if err != nil {
+			return err
+		}
+
+		return pf.setAny(nested.Interface())
+	}
+
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return errPathNotFound
+		}
+
+		nested := reflect.New(f.Type().Elem())
+		nested.Elem().Set(f.Elem())
+
+		err := o.atNested(nested.Elem(), tokens[1:], fn)
+		if err != nil {
+			return err
+		}
+
+		f.Set(nested)
+
+		return nil
+	}
+
+	return o.atNested(f, tokens[1:], fn)
+}
+
+// atNested calls fn with the field at the path tokens in v, which must be a struct.
+func (o *options) atNested(v reflect.Value, tokens []string, fn func(f reflect.Value) error) error {
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot address %q in %s", tokens[0], v.Type())
+	}
+
+	return o.atTokens(v, tokens, fn)
+}
+
+// isUnsetField reports whether f is an unset presence field.
+func isUnsetField(f reflect.Value) bool {
+	pf, ok := f.Addr().Interface().(presenceField)
+
+	return ok && pf.State() == StateUnset
+}
@@ -0,0 +1,229 @@
+package presence
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSON is a presence JSON document of unknown shape, such as a json or jsonb column or a
+// free-form payload field, with helpers navigating its contents without type assertions.
+// It embeds Of[any] and therefore marshals and scans the same way: objects hold
+// map[string]any, arrays []any, and numbers float64 or json.Number according to the
+// NumberDecodingBehavior.
+//
+//	var meta presence.JSON
+//	_ = json.Unmarshal([]byte(`{"tags":[{"name":"go"}]}`), &meta)
+//	name, ok := meta.GetPath("tags[0].name") // "go", true
+//
+// An Of[any] is navigated by wrapping it: presence.JSON{Of: v}.GetPath("a.b").
+type JSON struct{ Of[any] }
+
+// NewJSON is a JSON constructor from the given value.
+func NewJSON(v any) JSON {
+	return JSON{FromValue(v)}
+}
+
+// AsMap returns the value when it is an object, false otherwise, null and unset included.
+func (j JSON) AsMap() (map[string]any, bool) {
+	m, ok := j.anyValue().(map[string]any)
+
+	return m, ok
+}
+
+// AsSlice returns the value when it is an array, false otherwise, null and unset
+// included.
+func (j JSON) AsSlice() ([]any, bool) {
+	s, ok := j.anyValue().([]any)
+
+	return s, ok
+}
+
+// GetPath returns the value at path, object keys separated by dots and array indexes in
+// brackets, like "items[0].price" or "[2]", the whole value for an empty path. It
+// returns false when the value is null or unset, when path is invalid, or when a key or
+// an index of path is missing. A null found at path is returned as nil, true.
+func (j JSON) GetPath(path string) (any, bool) {
+	if !j.IsValue() {
+		return nil, false
+	}
+
+	steps, ok := parseJSONPath(path)
+	if !ok {
+		return nil, false
+	}
+
+	v := j.anyValue()
+	for _, s := range steps {
+		if s.key != nil {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+
+			v, ok = m[*s.key]
+			if !ok {
+				return nil, false
+			}
+
+			continue
+		}
+
+		a, ok := v.([]any)
+		if !ok || s.index >= len(a) {
+			return nil, false
+		}
+
+		v = a[s.index]
+	}
+
+	return v, true
+}
+
+// Query returns the value selected by path in the gjson syntax, keys separated by dots,
+// unset when path is absent from the document and null when it selects a JSON null:
+//
+//	doc.Query("items.0.name")    // the name of the first item
+//	doc.Query("items.#")         // the number of items, an int
+//	doc.Query("items.#.name")    // the names of the items holding one, a []any
+//	doc.Query(`meta.a\.b`)       // the key "a.b" of meta
+//	doc.Query("$.items[0].name") // JSONPath with a leading $, with indexes in brackets
+//
+// Numeric keys index arrays. The document itself is selected by an empty path.
+func (j JSON) Query(path string) Of[any] {
+	if path == "" || path == "$" {
+		return j.Of
+	}
+
+	if !j.IsValue() {
+		return Of[any]{}
+	}
+
+	if rest, ok := strings.CutPrefix(path, "$"); ok {
+		path = strings.TrimPrefix(strings.NewReplacer("[", ".", "]", "").Replace(rest), ".")
+	}
+
+	v, ok := queryJSON(j.anyValue(), splitQuery(path))
+	if !ok {
+		return Of[any]{}
+	}
+
+	if v == nil {
+		return Null[any]()
+	}
+
+	return FromValue(v)
+}
+
+// splitQuery returns the keys of the gjson path, split on the dots not escaped by a
+// backslash.
+func splitQuery(path string) []string {
+	var (
+		keys []string
+		b    strings.Builder
+	)
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '\\' && i+1 < len(path):
+			i++
+			b.WriteByte(path[i])
+		case c == '.':
+			keys = append(keys, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return append(keys, b.String())
+}
+
+// queryJSON returns the value of v selected by keys, false when it is absent.
+func queryJSON(v any, keys []string) (any, bool) {
+	for i, key := range keys {
+		switch value := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = value[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			if key == "#" {
+				return queryEach(value, keys[i+1:])
+			}
+
+			n, err := strconv.Atoi(key)
+			if err != nil || n < 0 || n >= len(value) {
+				return nil, false
+			}
+
+			v = value[n]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// queryEach returns the length of a when keys is empty, the values of its elements
+// selected by keys otherwise, those absent left out.
+func queryEach(a []any, keys []string) (any, bool) {
+	if len(keys) == 0 {
+		return len(a), true
+	}
+
+	out := []any{}
+	for _, elem := range a {
+		if v, ok := queryJSON(elem, keys); ok {
+			out = append(out, v)
+		}
+	}
+
+	return out, true
+}
+
+// jsonPathStep is an object key or, when key is nil, an array index of a path.
+type jsonPathStep struct {
+	key   *string
+	index int
+}
+
+// parseJSONPath returns the steps of path, false when it is invalid.
+func parseJSONPath(path string) ([]jsonPathStep, bool) {
+	var steps []jsonPathStep
+	if path == "" {
+		return steps, true
+	}
+
+	for i, segment := range strings.Split(path, ".") {
+		key, indexes, indexed := strings.Cut(segment, "[")
+		if key == "" && (i > 0 || !indexed) {
+			return nil, false
+		}
+
+		if key != "" {
+			steps = append(steps, jsonPathStep{key: &key})
+		}
+
+		if !indexed {
+			continue
+		}
+
+		for _, index := range strings.Split(indexes, "[") {
+			digits, ok := strings.CutSuffix(index, "]")
+			if !ok {
+				return nil, false
+			}
+
+			n, err := strconv.Atoi(digits)
+			if err != nil || n < 0 {
+				return nil, false
+			}
+
+			steps = append(steps, jsonPathStep{index: n})
+		}
+	}
+
+	return steps, true
+}
@@ -0,0 +1,105 @@
+package presence
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// LastWrite records the presence fields a patch wrote, to check that a later read
+// reflects them, for read-after-write consistency on eventually consistent replicas.
+type LastWrite struct {
+	// Fields holds the values of the set presence fields of the patch by name, nil for
+	// null.
+	Fields map[string]any
+
+	o *options
+}
+
+// LastWriteFields records the set presence fields of the struct patch, named according
+// to the options, which also drive the comparisons of Stale:
+//
+//	written, err := presence.LastWriteFields(patch, presence.WithTag("db"))
+//	// ... write to the primary, then read from a replica
+//	stale, err := written.Stale(user)
+//	if len(stale) > 0 {
+//		// read from the primary, or retry later
+//	}
+func LastWriteFields(patch any, opts ...Option) (LastWrite, error) {
+	o := newOptions(opts)
+	rv, err := structValue(patch)
+	if err != nil {
+		return LastWrite{}, err
+	}
+
+	w := LastWrite{Fields: map[string]any{}, o: o}
+	for _, f := range presenceFields(rv.Type(), o) {
+		pf := fieldOf(rv, f)
+		if pf.State() != StateUnset {
+			w.Fields[f.name] = pf.anyValue()
+		}
+	}
+
+	return w, nil
+}
+
+// Stale returns, sorted, the recorded fields the struct read does not reflect: fields
+// unset in read, and fields whose state or value differ, values being compared like
+// Diff does and converted to the written type when needed. read can be of another type
+// than the patch, such as an entity with pointer fields, nil pointers being null.
+func (w LastWrite) Stale(read any) ([]string, error) {
+	o := w.o
+	if o == nil {
+		o = newOptions(nil)
+	}
+
+	rv, err := structValue(read)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := fieldIndexes(rv.Type(), o)
+
+	var stale []string
+
+	for _, name := range slices.Sorted(maps.Keys(w.Fields)) {
+		index, ok := indexes[name]
+		if !ok {
+			return nil, fmt.Errorf("presence read-after-write check : %s has no field %s", rv.Type(), name)
+		}
+
+		f := rv.FieldByIndex(index)
+		_, isPresence := f.Addr().Interface().(presenceField)
+
+		state, value := fieldState(f, isPresence)
+		if !o.reflects(w.Fields[name], state, value) {
+			stale = append(stale, name)
+		}
+	}
+
+	return stale, nil
+}
+
+// reflects reports whether the field read in the given state and value reflects the
+// written one, nil for null.
+func (o *options) reflects(written any, state State, value any) bool {
+	switch state {
+	case StateUnset:
+		return false
+	case StateNull:
+		return written == nil
+	case StateValue:
+	}
+
+	if written == nil || value == nil {
+		return written == nil && value == nil
+	}
+
+	typ := reflect.TypeOf(written)
+	if v := reflect.ValueOf(value); v.Type() != typ && v.CanConvert(typ) {
+		value = v.Convert(typ).Interface()
+	}
+
+	return o.equalValues(written, value)
+}
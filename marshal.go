@@ -0,0 +1,454 @@
+package presence
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// htmlEscapes maps the escapes of the HTML characters written by encoding/json to the
+// characters.
+var htmlEscapes = map[string]byte{`\u003c`: '<', `\u003e`: '>', `\u0026`: '&'}
+
+// MarshalOption configures Marshal.
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	escapeHTML bool
+	prefix     string
+	indent     string
+}
+
+// WithEscapeHTML sets whether Marshal escapes the HTML characters <, > and & inside
+// strings, as encoding/json does by default.
+func WithEscapeHTML(on bool) MarshalOption {
+	return func(o *marshalOptions) {
+		o.escapeHTML = on
+	}
+}
+
+// WithIndent makes Marshal indent its output like json.MarshalIndent.
+func WithIndent(prefix, indent string) MarshalOption {
+	return func(o *marshalOptions) {
+		o.prefix, o.indent = prefix, indent
+	}
+}
+
+// Marshal returns the JSON encoding of v like json.Marshal, with the settings of
+// json.Encoder as options, for logs and templates:
+//
+//	b, err := presence.Marshal(event, presence.WithEscapeHTML(false), presence.WithIndent("", "  "))
+//
+// json.Encoder's SetEscapeHTML(false) leaves the HTML characters escaped by the
+// MarshalJSON methods, those of presence values included; WithEscapeHTML(false) writes
+// them as is everywhere. Unlike json.Encoder, no newline is appended.
+//
+// The fields tagged presence:"dbonly", such as internal columns, are left out of the
+// structs as they are encoded, wherever they are: nested, or elements of slices, arrays
+// and maps. A struct thus serves both the database and the API:
+//
+//	type User struct {
+//		Email        presence.Of[string] `json:"email" db:"email"`
+//		PasswordHash presence.Of[string] `json:"password_hash" db:"password_hash" presence:"dbonly"`
+//		Avatar       presence.Of[string] `json:"avatar" db:"-" presence:"apionly"`
+//	}
+//
+// Presence values holding such structs leave the fields out of their MarshalJSON, so
+// json.Marshal does too, as do MarshalCanonical and Encoder. A struct encoded by
+// json.Marshal itself keeps its own dbonly fields: encode it with Marshal.
+func Marshal(v any, opts ...MarshalOption) ([]byte, error) {
+	o := &marshalOptions{escapeHTML: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	b, err := marshalDBOnly(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.escapeHTML {
+		b = unescapeHTML(b)
+	}
+
+	if o.prefix == "" && o.indent == "" {
+		return b, nil
+	}
+
+	var indented bytes.Buffer
+
+	err = json.Indent(&indented, b, o.prefix, o.indent)
+	if err != nil {
+		return nil, fmt.Errorf("presence marshaling : %w", err)
+	}
+
+	return indented.Bytes(), nil
+}
+
+// isZeroerType is the interface of the types deciding omitzero.
+var isZeroerType = reflect.TypeFor[interface{ IsZero() bool }]()
+
+// marshalDBOnly returns the JSON encoding of v like json.Marshal, the fields tagged
+// presence:"dbonly" being left out of the structs as they are encoded.
+func marshalDBOnly(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !hasDBOnly(rv.Type()) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("presence marshaling : %w", err)
+		}
+
+		return b, nil
+	}
+
+	return appendDBOnly(nil, rv)
+}
+
+// appendDBOnly appends the JSON encoding of rv to buf, encoding by hand the structs with
+// fields tagged presence:"dbonly" and the slices, arrays, maps and interfaces which may
+// hold them, the other values with encoding/json.
+func appendDBOnly(buf []byte, rv reflect.Value) ([]byte, error) {
+	if !rv.IsValid() {
+		return append(buf, "null"...), nil
+	}
+
+	typ := rv.Type()
+	if !hasDBOnly(typ) || encodesItself(typ) || rv.CanAddr() && encodesItself(reflect.PointerTo(typ)) {
+		v := rv.Interface()
+		if rv.CanAddr() {
+			v = rv.Addr().Interface()
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("presence marshaling %s : %w", typ, err)
+		}
+
+		return append(buf, b...), nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return append(buf, "null"...), nil
+		}
+
+		return appendDBOnly(buf, rv.Elem())
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && rv.IsNil() {
+			return append(buf, "null"...), nil
+		}
+
+		return appendArray(buf, rv)
+	case reflect.Map:
+		if rv.IsNil() {
+			return append(buf, "null"...), nil
+		}
+
+		return appendMap(buf, rv)
+	default:
+		return appendStruct(buf, rv)
+	}
+}
+
+// encodesItself reports whether the values of type typ are encoded by their MarshalJSON
+// or MarshalText method.
+func encodesItself(typ reflect.Type) bool {
+	return typ.Implements(jsonMarshalerType) || typ.Implements(textMarshalerType)
+}
+
+// appendArray appends the JSON array of the elements of the slice or array rv.
+func appendArray(buf []byte, rv reflect.Value) ([]byte, error) {
+	buf = append(buf, '[')
+
+	for i := range rv.Len() {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		var err error
+
+		buf, err = appendDBOnly(buf, rv.Index(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(buf, ']'), nil
+}
+
+// appendMap appends the JSON object of the entries of the map rv, sorted by key as
+// encoding/json sorts them.
+func appendMap(buf []byte, rv reflect.Value) ([]byte, error) {
+	type entry struct {
+		name  string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, rv.Len())
+
+	iter := rv.MapRange()
+	for iter.Next() {
+		name, ok := mapKeyName(iter.Key())
+		if !ok {
+			return nil, fmt.Errorf("presence marshaling %s : unsupported map key", rv.Type())
+		}
+
+		entries = append(entries, entry{name: name, value: iter.Value()})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.name, b.name) })
+
+	buf = append(buf, '{')
+
+	for i, e := range entries {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		var err error
+
+		buf, err = appendDBOnly(appendName(buf, e.name), e.value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(buf, '}'), nil
+}
+
+// appendStruct appends the JSON object of the struct rv without its fields tagged
+// presence:"dbonly", following the omitempty, omitzero and string options of their json
+// tags.
+func appendStruct(buf []byte, rv reflect.Value) ([]byte, error) {
+	if !rv.CanAddr() {
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+
+	buf = append(buf, '{')
+	first := true
+
+	var err error
+
+	walkFields(rv.Type(), nil, newOptions(nil), func(name string, index []int, _ bool) {
+		sf := rv.Type().FieldByIndex(index)
+		f := rv.FieldByIndex(index)
+
+		if err != nil || hasPresenceOption(sf, "dbonly") || omitField(sf, f) {
+			return
+		}
+
+		if !first {
+			buf = append(buf, ',')
+		}
+
+		first = false
+		buf = appendName(buf, name)
+
+		if hasTagOption(sf, "string") {
+			buf, err = appendQuoted(buf, f)
+
+			return
+		}
+
+		buf, err = appendDBOnly(buf, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, '}'), nil
+}
+
+// omitField reports whether the field f, of the struct field sf, is left out by the
+// omitempty or omitzero option of its json tag.
+func omitField(sf reflect.StructField, f reflect.Value) bool {
+	if hasTagOption(sf, "omitempty") && isEmptyValue(f) {
+		return true
+	}
+
+	if !hasTagOption(sf, "omitzero") {
+		return false
+	}
+
+	switch {
+	case f.Kind() == reflect.Pointer && f.Type().Implements(isZeroerType):
+		return f.IsNil() || f.Interface().(interface{ IsZero() bool }).IsZero()
+	case f.Type().Implements(isZeroerType):
+		return f.Interface().(interface{ IsZero() bool }).IsZero()
+	case reflect.PointerTo(f.Type()).Implements(isZeroerType):
+		return f.Addr().Interface().(interface{ IsZero() bool }).IsZero()
+	default:
+		return f.IsZero()
+	}
+}
+
+// isEmptyValue reports whether v is empty for the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// hasTagOption reports whether the json tag of f has the option opt.
+func hasTagOption(f reflect.StructField, opt string) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+	return slices.Contains(strings.Split(opts, ","), opt)
+}
+
+// appendQuoted appends the scalar f, encoded as a JSON string by the string option of
+// its json tag. f cannot hold dbonly fields.
+func appendQuoted(buf []byte, f reflect.Value) ([]byte, error) {
+	b, err := json.Marshal(f.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("presence marshaling %s : %w", f.Type(), err)
+	}
+
+	typ := f.Type()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		if string(b) != "null" {
+			b, _ = json.Marshal(string(b))
+		}
+	default:
+	}
+
+	return append(buf, b...), nil
+}
+
+// appendName appends the JSON key name and its colon.
+func appendName(buf []byte, name string) []byte {
+	k, _ := json.Marshal(name)
+
+	return append(append(buf, k...), ':')
+}
+
+// mapKeyName returns the JSON key of the map key k, as encoding/json writes it.
+func mapKeyName(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.String {
+		return k.String(), true
+	}
+
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", true
+		}
+
+		b, err := tm.MarshalText()
+
+		return string(b), err == nil
+	}
+
+	if k.CanInt() {
+		return strconv.FormatInt(k.Int(), 10), true
+	}
+
+	if k.CanUint() {
+		return strconv.FormatUint(k.Uint(), 10), true
+	}
+
+	return "", false
+}
+
+// dbOnlyTypes caches hasDBOnly per type.
+var dbOnlyTypes sync.Map
+
+// hasDBOnly reports whether the values of type typ hold structs with fields tagged
+// presence:"dbonly", through pointers, slices, arrays and maps, or interfaces which may
+// hold them. Presence values filter their own encoding.
+func hasDBOnly(typ reflect.Type) bool {
+	if found, ok := dbOnlyTypes.Load(typ); ok {
+		return found.(bool)
+	}
+
+	found := walkDBOnly(typ, map[reflect.Type]bool{})
+	dbOnlyTypes.Store(typ, found)
+
+	return found
+}
+
+// walkDBOnly is hasDBOnly for the types not in visiting.
+func walkDBOnly(typ reflect.Type, visiting map[reflect.Type]bool) bool {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array ||
+		typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+
+	if reflect.PointerTo(typ).Implements(presenceFieldType) {
+		// The presence values leave the dbonly fields out of their own MarshalJSON.
+		return false
+	}
+
+	if typ.Kind() == reflect.Interface {
+		// The dynamic types of interface values are only known when encoding them.
+		return true
+	}
+
+	if typ.Kind() != reflect.Struct || visiting[typ] {
+		return false
+	}
+
+	visiting[typ] = true
+	found := false
+
+	walkFields(typ, nil, newOptions(nil), func(_ string, index []int, _ bool) {
+		f := typ.FieldByIndex(index)
+		found = found || hasPresenceOption(f, "dbonly") || walkDBOnly(f.Type, visiting)
+	})
+
+	return found
+}
+
+// unescapeHTML replaces the escapes of the HTML characters in the strings of the JSON
+// document data by the characters.
+func unescapeHTML(data []byte) []byte {
+	const escapeLen = len(`\u003c`)
+
+	if !bytes.Contains(data, []byte(`\u00`)) {
+		return data
+	}
+
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' || i+1 == len(data) {
+			out = append(out, data[i])
+
+			continue
+		}
+
+		if c, ok := htmlEscapes[string(data[i:min(i+escapeLen, len(data))])]; ok {
+			out = append(out, c)
+			i += escapeLen - 1
+
+			continue
+		}
+
+		// Other escapes, \\ included, are copied as is.
+		out = append(out, data[i], data[i+1])
+		i++
+	}
+
+	return out
+}
@@ -0,0 +1,221 @@
+package presence
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// UnmarshalMerge decodes the JSON object data into the struct pointed to by dst,
+// overwriting only the fields whose key is in data: the other fields, set or not, are
+// left intact, so that successive merges accumulate like successive PATCH requests.
+//
+//	err := presence.UnmarshalMerge(defaults, &cfg)
+//	err = presence.UnmarshalMerge(overrides, &cfg) // keeps the defaults not overridden
+//
+// Unlike json.Unmarshal, which decodes into the values already held and so merges maps
+// and mutates the values shared with copies of a presence field, the fields in data are
+// replaced by freshly decoded values. A null sets presence fields to null and pointers
+// to nil, and reports ErrNullNotAllowed for other fields.
+//
+// Keys match the field names exactly, as given by the options (json tags by default);
+// unknown keys are ignored, and duplicate keys fail with DuplicateKeysReject. With
+// WithNested(NestedMaps) or WithNested(NestedFlatten), the objects of nested presence
+// structs are merged into their fields as well.
+func UnmarshalMerge(data []byte, dst any, opts ...Option) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence merge destination must be a non-nil struct pointer, got %T", dst)
+	}
+
+	if GetDefaultDuplicateKeys() == DuplicateKeysReject {
+		err := CheckDuplicateKeys(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return newOptions(opts).unmarshalMerge(data, dv.Elem())
+}
+
+// unmarshalMerge decodes the JSON object data into the fields of the struct dv.
+func (o *options) unmarshalMerge(data []byte, dv reflect.Value) error {
+	var object map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &object)
+	if err != nil {
+		return fmt.Errorf("presence merge unmarshaling : %w", err)
+	}
+
+	fields := map[string][]int{}
+	walkFields(dv.Type(), nil, o, func(name string, index []int, _ bool) {
+		if _, ok := fields[name]; !ok {
+			fields[name] = index
+		}
+	})
+
+	// Sorted keys make the reported error deterministic.
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		index, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		err := o.mergeField(dv.FieldByIndex(index), object[key])
+		if err != nil {
+			return fmt.Errorf("presence merging field %s : %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// mergeField replaces the field dst with the value decoded from raw.
+func (o *options) mergeField(dst reflect.Value, raw json.RawMessage) error {
+	if string(raw) == "null" {
+		return nullField(dst)
+	}
+
+	if o.nested != NestedValue && raw[0] == '{' {
+		merged, err := o.mergeNested(dst, raw)
+		if merged || err != nil {
+			return err
+		}
+	}
+
+	fresh := reflect.New(dst.Type())
+
+	err := json.Unmarshal(raw, fresh.Interface())
+	if err != nil {
+		return fmt.Errorf("presence merge unmarshaling : %w", err)
+	}
+
+	// Presence fields keep their own behaviors, only their value changes.
+	if target, ok := dst.Addr().Interface().(presenceField); ok {
+		return target.setAny(fresh.Interface().(presenceField).anyValue())
+	}
+
+	dst.Set(fresh.Elem())
+
+	return nil
+}
+
+// mergeNested merges the JSON object raw into the field dst when it holds a nested
+// presence struct, reporting whether it did.
+func (o *options) mergeNested(dst reflect.Value, raw json.RawMessage) (bool, error) {
+	if target, ok := dst.Addr().Interface().(presenceField); ok {
+		value := reflect.ValueOf(target.anyValue())
+		if value.Kind() != reflect.Struct {
+			return false, nil
+		}
+
+		// nestedStruct copies the value, which copies of the field may share.
+		current, ok := o.nestedStruct(value)
+		if !ok {
+			return false, nil
+		}
+
+		err := o.unmarshalMerge(raw, current)
+		if err != nil {
+			return true, err
+		}
+
+		return true, target.setAny(current.Interface())
+	}
+
+	typ := dst.Type()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || len(presenceFields(typ, o)) == 0 {
+		return false, nil
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(typ))
+		}
+
+		dst = dst.Elem()
+	}
+
+	return true, o.unmarshalMerge(raw, dst)
+}
+
+// MergeOption configures MergeJSON.
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	keepNulls bool
+}
+
+// WithKeepNulls makes MergeJSON set the keys patched with null to null, an explicit
+// null as for presence values, instead of deleting them.
+func WithKeepNulls() MergeOption {
+	return func(o *mergeOptions) {
+		o.keepNulls = true
+	}
+}
+
+// MergeJSON merges the JSON document patch into base like a JSON Merge Patch
+// (RFC 7386), with the semantics of presence values: keys absent from patch are kept,
+// keys patched with null are deleted, and objects are merged recursively while other
+// values, arrays included, replace the base ones.
+//
+//	merged, err := presence.MergeJSON(stored, body)
+//
+// WithKeepNulls sets the keys patched with null to null instead of deleting them. An
+// empty base stands for null. The result is encoded like MarshalCanonical does.
+func MergeJSON(base, patch []byte, opts ...MergeOption) ([]byte, error) {
+	o := &mergeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var baseDoc any
+	if len(bytes.TrimSpace(base)) > 0 {
+		var err error
+
+		baseDoc, err = decodeDocument(base, "merge decoding base")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	patchDoc, err := decodeDocument(patch, "merge decoding patch")
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeCanonical(o.mergeDocuments(baseDoc, patchDoc))
+}
+
+// mergeDocuments returns the merge of the decoded JSON documents patch into base,
+// modifying base.
+func (o *mergeOptions) mergeDocuments(base, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	baseObject, ok := base.(map[string]any)
+	if !ok {
+		baseObject = make(map[string]any, len(patchObject))
+	}
+
+	for key, value := range patchObject {
+		if value == nil && !o.keepNulls {
+			delete(baseObject, key)
+
+			continue
+		}
+
+		baseObject[key] = o.mergeDocuments(baseObject[key], value)
+	}
+
+	return baseObject
+}
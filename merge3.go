@@ -0,0 +1,132 @@
+package presence
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldConflict describes a field changed differently by both sides of a three-way merge.
+// Base, Mine and Theirs are nil when the field is null or unset, the states of plain
+// fields being StateValue.
+type FieldConflict struct {
+	Field       string
+	Base        any
+	Mine        any
+	Theirs      any
+	BaseState   State
+	MineState   State
+	TheirsState State
+}
+
+// Merge3 merges the concurrent edits mine and theirs of the struct base, all of the same
+// type, for collaborative editing backends. It returns the merged struct, a value of that
+// type, and the fields both sides changed differently:
+//
+//	merged, conflicts, err := presence.Merge3(stored, clientPatch, concurrentPatch)
+//	doc := merged.(Document)
+//
+// Presence fields tell which side actually touched them: a side leaving a field unset
+// did not touch it, so that mine and theirs can be full structs as well as PATCH
+// payloads; a side setting it to null or to a value different from base changed it.
+// Plain fields, which have no unset state, are changed when they differ from base.
+// Conflicting fields keep their base value.
+// Values are compared like Diff does, according to the comparison options.
+func Merge3(base, mine, theirs any, opts ...Option) (any, []FieldConflict, error) {
+	o := newOptions(opts)
+	bv, err := structValue(base)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mv, err := structValue(mine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tv, err := structValue(theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bv.Type() != mv.Type() || bv.Type() != tv.Type() {
+		return nil, nil, fmt.Errorf("presence cannot merge %s, %s and %s", bv.Type(), mv.Type(), tv.Type())
+	}
+
+	merged := reflect.New(bv.Type()).Elem()
+	merged.Set(bv)
+
+	var conflicts []FieldConflict
+
+	walkFields(bv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		if !isPresence {
+			conflict, ok := o.merge3Plain(name, merged.FieldByIndex(index), mv.FieldByIndex(index), tv.FieldByIndex(index))
+			if ok {
+				conflicts = append(conflicts, conflict)
+			}
+
+			return
+		}
+
+		b := bv.FieldByIndex(index).Addr().Interface().(presenceField)
+		m := mv.FieldByIndex(index).Addr().Interface().(presenceField)
+		t := tv.FieldByIndex(index).Addr().Interface().(presenceField)
+		mineChanged, theirsChanged := o.touched(b, m), o.touched(b, t)
+
+		switch {
+		case mineChanged && theirsChanged && !o.samePresence(m, t):
+			conflicts = append(conflicts, FieldConflict{
+				Field:       name,
+				Base:        b.anyValue(),
+				Mine:        m.anyValue(),
+				Theirs:      t.anyValue(),
+				BaseState:   b.State(),
+				MineState:   m.State(),
+				TheirsState: t.State(),
+			})
+		case mineChanged:
+			merged.FieldByIndex(index).Set(mv.FieldByIndex(index))
+		case theirsChanged:
+			merged.FieldByIndex(index).Set(tv.FieldByIndex(index))
+		}
+	})
+
+	return merged.Interface(), conflicts, nil
+}
+
+// touched reports whether side changed the presence field base: set, to null or to
+// another value.
+func (o *options) touched(base, side presenceField) bool {
+	return side.State() != StateUnset && !o.samePresence(base, side)
+}
+
+// samePresence reports whether the presence fields a and b hold the same state and value.
+func (o *options) samePresence(a, b presenceField) bool {
+	return a.State() == b.State() && o.equalValues(a.anyValue(), b.anyValue())
+}
+
+// merge3Plain merges the plain field name of mine or theirs into merged, which holds the
+// base value, reporting the conflict when both differ from it differently.
+func (o *options) merge3Plain(name string, merged, mine, theirs reflect.Value) (FieldConflict, bool) {
+	base := merged.Interface()
+	mineChanged := !o.equalValues(base, mine.Interface())
+	theirsChanged := !o.equalValues(base, theirs.Interface())
+
+	switch {
+	case mineChanged && theirsChanged && !o.equalValues(mine.Interface(), theirs.Interface()):
+		return FieldConflict{
+			Field:       name,
+			Base:        base,
+			Mine:        mine.Interface(),
+			Theirs:      theirs.Interface(),
+			BaseState:   StateValue,
+			MineState:   StateValue,
+			TheirsState: StateValue,
+		}, true
+	case mineChanged:
+		merged.Set(mine)
+	case theirsChanged:
+		merged.Set(theirs)
+	}
+
+	return FieldConflict{}, false
+}
@@ -0,0 +1,92 @@
+package presence
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorTranslator returns the message of err in the language lang, a BCP 47 tag such as
+// "fr" or "pt-BR", reporting false when it has none.
+type ErrorTranslator func(err error, lang string) (string, bool)
+
+// Catalog holds error messages per language tag, in the order they are looked up.
+// Its Translate method is an ErrorTranslator:
+//
+//	presence.SetErrorTranslator(presence.Catalog{
+//		"fr": {
+//			{Err: presence.ErrRequired, Text: "obligatoire"},
+//			{Err: presence.ErrNotNullable, Text: "ne doit pas être nul"},
+//			{Err: presence.ErrNullNotAllowed, Text: "ne doit pas être nul"},
+//		},
+//	}.Translate)
+type Catalog map[string][]Message
+
+// Message is the text of the errors matching Err with errors.Is.
+type Message struct {
+	Err  error
+	Text string
+}
+
+// Translate returns the text of the first message of the catalog whose error err matches
+// with errors.Is, in the language lang, else in its base language ("pt" for "pt-BR").
+// Messages are tried in order, so that an error matching several of them, such as a
+// FieldErrors or an errors.Join, always gets the same one.
+func (c Catalog) Translate(err error, lang string) (string, bool) {
+	for lang != "" {
+		for _, m := range c[lang] {
+			if errors.Is(err, m.Err) {
+				return m.Text, true
+			}
+		}
+
+		base, _, ok := strings.Cut(lang, "-")
+		if !ok {
+			base, _, _ = strings.Cut(lang, "_")
+		}
+
+		if base == lang {
+			break
+		}
+
+		lang = base
+	}
+
+	return "", false
+}
+
+// englishMessages are the messages of the errors surfaced to API consumers, used when
+// the translator has none.
+var englishMessages = Catalog{"en": {
+	{Err: ErrRequired, Text: ErrRequired.Error()},
+	{Err: ErrNotNullable, Text: ErrNotNullable.Error()},
+	{Err: ErrEmpty, Text: ErrEmpty.Error()},
+	{Err: ErrNullNotAllowed, Text: "must not be null"},
+	{Err: ErrConflict, Text: "was modified in the meantime"},
+	{Err: ErrInvalidMoney, Text: "invalid amount"},
+	{Err: ErrInvalidPoint, Text: "invalid point"},
+	{Err: ErrInvalidSort, Text: "invalid sort field"},
+}}
+
+// SetErrorTranslator sets the translator of ErrorMessage, nil restoring the English
+// messages.
+func SetErrorTranslator(t ErrorTranslator) {
+	updateDefaults(func(d *defaults) { d.errorTranslator = t })
+}
+
+// ErrorMessage returns the message of err for API consumers in the language lang,
+// typically taken from the Accept-Language header: the message of the translator set
+// by SetErrorTranslator, else the English one of the errors of this package, else the
+// text of err.
+func ErrorMessage(err error, lang string) string {
+	if t := loadDefaults().errorTranslator; t != nil {
+		if msg, ok := t(err, lang); ok {
+			return msg
+		}
+	}
+
+	if msg, ok := englishMessages.Translate(err, "en"); ok {
+		return msg
+	}
+
+	return err.Error()
+}
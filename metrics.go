@@ -0,0 +1,73 @@
+package presence
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MetricsHook receives the decoding failures of presence values, so that operators can
+// track the rate of malformed input per value type in production. Types are named like
+// reflect.Type.String does, e.g. "int16" or "uuid.UUID". The methods are called
+// concurrently and must not block.
+type MetricsHook interface {
+	// UnmarshalError counts a failed UnmarshalJSON.
+	UnmarshalError(typ string, err error)
+	// ScanError counts a failed Scan.
+	ScanError(typ string, err error)
+	// Overflow counts the failures of either kind due to a number out of the range of
+	// the type, e.g. 70000 for an int16.
+	Overflow(typ string)
+}
+
+// SetMetricsHook sets the hook receiving the decoding failures, nil removing it.
+func SetMetricsHook(h MetricsHook) {
+	updateDefaults(func(d *defaults) { d.metricsHook = h })
+}
+
+// metric is the kind of failure reported to the MetricsHook.
+type metric int
+
+const (
+	metricUnmarshal metric = iota
+	metricScan
+)
+
+// observeError reports the failure err of a value of type T to the MetricsHook.
+func observeError[T any](m metric, err error) {
+	h := loadDefaults().metricsHook
+	if h == nil {
+		return
+	}
+
+	typ := reflect.TypeFor[T]().String()
+	if m == metricScan {
+		h.ScanError(typ, err)
+	} else {
+		h.UnmarshalError(typ, err)
+	}
+
+	if isOverflow(err) {
+		h.Overflow(typ)
+	}
+}
+
+// isOverflow reports whether err is due to a number out of range.
+func isOverflow(err error) bool {
+	if errors.Is(err, strconv.ErrRange) {
+		return true
+	}
+
+	// encoding/json reports overflows as type errors on integer literals.
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		literal, ok := strings.CutPrefix(typeErr.Value, "number ")
+
+		return ok && !strings.ContainsAny(literal, ".eE")
+	}
+
+	// database/sql formats the strconv errors of its conversions without wrapping them.
+	return strings.Contains(err.Error(), "value out of range")
+}
@@ -0,0 +1,257 @@
+package presence
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMoney is returned for amounts and currencies Money cannot represent.
+var ErrInvalidMoney = errors.New("presence: invalid money")
+
+// currencyExponents are the ISO 4217 currencies whose minor unit is not the hundredth.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Money is an amount in a currency, kept exact as a count of the minor unit of the
+// currency, e.g. cents. Optional monetary fields are Of[Money]:
+//
+//	type Order struct {
+//		Discount presence.Of[presence.Money] `json:"discount,omitzero"`
+//	}
+//
+// It encodes in JSON as an object whose amount is a decimal string, read from strings
+// and numbers alike:
+//
+//	{"amount":"12.34","currency":"EUR"}
+//
+// As a database value, Of[Money] is stored as that JSON object. MoneyValues and
+// MoneyScanners map it to an amount NUMERIC column and a currency CHAR(3) column instead.
+type Money struct {
+	// Units is the amount in the minor unit of the currency: 1234 for 12.34 EUR.
+	Units int64
+	// Currency is the ISO 4217 code, e.g. "EUR".
+	Currency string
+}
+
+// NewMoney returns the amount in the currency, amount being a decimal such as "12.34".
+// It fails when amount has more decimals than the minor unit of the currency allows,
+// trailing zeros excepted.
+func NewMoney(amount, currency string) (Money, error) {
+	exponent, err := currencyExponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	units, err := parseUnits(amount, exponent)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Units: units, Currency: currency}, nil
+}
+
+// Amount returns the amount as a decimal, with as many decimals as the minor unit of the
+// currency: "12.34" for 1234 EUR cents.
+func (m Money) Amount() string {
+	exponent, err := currencyExponent(m.Currency)
+	if err != nil {
+		exponent = 2
+	}
+
+	digits := strconv.FormatUint(absUnits(m.Units), 10)
+	if exponent > 0 {
+		if len(digits) <= exponent {
+			digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+		}
+
+		digits = digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+	}
+
+	if m.Units < 0 {
+		return "-" + digits
+	}
+
+	return digits
+}
+
+// String returns the amount followed by the currency, e.g. "12.34 EUR".
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency
+}
+
+// moneyJSON is the JSON encoding of Money.
+type moneyJSON struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON implements the encoding json interface.
+func (m Money) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Amount(), m.Currency})
+	if err != nil {
+		return nil, fmt.Errorf("presence money marshaling : %w", err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalJSON implements the decoding json interface.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return fmt.Errorf("presence money unmarshaling : %w", err)
+	}
+
+	money, err := NewMoney(v.Amount.String(), v.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+
+	return nil
+}
+
+// MoneyValues returns the amount and the currency of n as the values of a NUMERIC and a
+// CHAR(3) column, both nil when n is null. Unset values follow the ValueUnsetBehavior.
+func MoneyValues(n Of[Money]) (amount, currency driver.Value, err error) {
+	if n.IsUnset() {
+		v, err := n.Value()
+
+		return v, v, err
+	}
+
+	m, ok := n.Get()
+	if !ok {
+		return nil, nil, nil
+	}
+
+	return m.Amount(), m.Currency, nil
+}
+
+// MoneyScanners returns the scan destinations of an amount column and of the currency
+// column following it, which set n once both are scanned. Both columns must be NULL
+// for n to be null (or unset with ScanNullAsUnset):
+//
+//	amount, currency := presence.MoneyScanners(&order.Discount)
+//	err := row.Scan(&order.ID, amount, currency)
+func MoneyScanners(n *Of[Money]) (amount, currency sql.Scanner) {
+	s := &moneyScan{n: n}
+
+	return moneyAmountScanner{s}, moneyCurrencyScanner{s}
+}
+
+// moneyScan holds the amount scanned before the currency.
+type moneyScan struct {
+	n      *Of[Money]
+	amount sql.NullString
+}
+
+type moneyAmountScanner struct{ *moneyScan }
+
+func (s moneyAmountScanner) Scan(v any) error {
+	err := s.amount.Scan(v)
+	if err != nil {
+		return fmt.Errorf("presence database scanning money amount : %w", err)
+	}
+
+	return nil
+}
+
+type moneyCurrencyScanner struct{ *moneyScan }
+
+func (s moneyCurrencyScanner) Scan(v any) error {
+	var currency sql.NullString
+
+	err := currency.Scan(v)
+	if err != nil {
+		return fmt.Errorf("presence database scanning money currency : %w", err)
+	}
+
+	switch {
+	case !s.amount.Valid && !currency.Valid:
+		s.n.handleScanNull()
+
+		return nil
+	case !s.amount.Valid || !currency.Valid:
+		return fmt.Errorf("%w : amount and currency must both be NULL or not", ErrInvalidMoney)
+	}
+
+	// CHAR(3) columns may come padded.
+	m, err := NewMoney(s.amount.String, strings.TrimSpace(currency.String))
+	if err != nil {
+		return err
+	}
+
+	s.n.SetValue(m)
+
+	return nil
+}
+
+// currencyExponent returns the number of decimals of the minor unit of the currency.
+func currencyExponent(currency string) (int, error) {
+	isCode := len(currency) == 3 && !strings.ContainsFunc(currency, func(r rune) bool { return r < 'A' || r > 'Z' })
+	if !isCode {
+		return 0, fmt.Errorf("%w : currency %q is not an ISO 4217 code", ErrInvalidMoney, currency)
+	}
+
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent, nil
+	}
+
+	return 2, nil
+}
+
+// parseUnits converts the decimal amount into a count of minor units.
+func parseUnits(amount string, exponent int) (int64, error) {
+	invalid := fmt.Errorf("%w : amount %q", ErrInvalidMoney, amount)
+
+	digits, negative := strings.CutPrefix(amount, "-")
+	integer, fraction, _ := strings.Cut(digits, ".")
+	if integer == "" || strings.Trim(integer+fraction, "0123456789") != "" {
+		return 0, invalid
+	}
+
+	significant := strings.TrimRight(fraction, "0")
+	if len(significant) > exponent {
+		return 0, fmt.Errorf("%w : amount %q has more than %d decimals", ErrInvalidMoney, amount, exponent)
+	}
+
+	units, err := strconv.ParseInt(integer+significant+strings.Repeat("0", exponent-len(significant)), 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+
+	if negative {
+		units = -units
+	}
+
+	return units, nil
+}
+
+func absUnits(units int64) uint64 {
+	if units == math.MinInt64 {
+		return uint64(math.MaxInt64) + 1
+	}
+
+	if units < 0 {
+		return uint64(-units)
+	}
+
+	return uint64(units)
+}
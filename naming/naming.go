@@ -0,0 +1,119 @@
+/*
+Package naming converts field and column names between naming conventions.
+Its strategies are used by the presence struct helpers (WithNaming) and by the gorm-gen
+integration to name JSON tags after database columns.
+*/
+package naming
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strategy converts a name into a naming convention.
+type Strategy interface {
+	Convert(name string) string
+}
+
+// Func adapts a function to the Strategy interface.
+type Func func(name string) string
+
+// Convert implements Strategy.
+func (f Func) Convert(name string) string {
+	return f(name)
+}
+
+type (
+	camel struct{}
+	snake struct{}
+	kebab struct{}
+)
+
+func (camel) Convert(name string) string { return SnakeToCamelCase(name) }
+func (snake) Convert(name string) string { return CamelToSnakeCase(name) }
+func (kebab) Convert(name string) string { return ToKebabCase(name) }
+
+// Built-in strategies, also registered under the names "camel", "snake" and "kebab".
+var (
+	Camel Strategy = camel{}
+	Snake Strategy = snake{}
+	Kebab Strategy = kebab{}
+)
+
+var (
+	registry = map[string]Strategy{
+		"camel": Camel,
+		"snake": Snake,
+		"kebab": Kebab,
+	}
+	registryMu sync.RWMutex
+)
+
+// Register makes a strategy available by name, replacing any strategy registered under that name.
+func Register(name string, s Strategy) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = s
+}
+
+// Lookup returns the strategy registered under name.
+func Lookup(name string) (Strategy, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := registry[name]
+
+	return s, ok
+}
+
+// SnakeToCamelCase converts a snake_case name into lowerCamelCase: "created_at" becomes "createdAt".
+func SnakeToCamelCase(in string) string {
+	in = strings.TrimSpace(strings.ToLower(in))
+	if in == "" {
+		return in
+	}
+
+	tokens := strings.Split(in, "_")
+
+	var out strings.Builder
+	for i, token := range tokens {
+		if i == 0 {
+			out.WriteString(token)
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(token)
+		out.WriteRune(unicode.ToUpper(r))
+		out.WriteString(token[size:])
+	}
+
+	return out.String()
+}
+
+// CamelToSnakeCase converts a camelCase or PascalCase name into snake_case, keeping
+// acronyms together: "UserID" becomes "user_id" and "HTTPServer" becomes "http_server".
+func CamelToSnakeCase(in string) string {
+	runes := []rune(strings.TrimSpace(in))
+
+	var out strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextIsLower {
+				out.WriteByte('_')
+			}
+		}
+
+		out.WriteRune(unicode.ToLower(r))
+	}
+
+	return out.String()
+}
+
+// ToKebabCase converts a camelCase, PascalCase or snake_case name into kebab-case.
+func ToKebabCase(in string) string {
+	return strings.ReplaceAll(CamelToSnakeCase(in), "_", "-")
+}
@@ -1,35 +1,103 @@
 package presence
 
 import (
+	"bytes"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Both Of[T] and *Of[T] encode through the value receiver methods, so pointer
+// fields, map values and interface-boxed values marshal alike, and database/sql
+// binds both as arguments (a nil *Of[T] as NULL). Decoding needs a pointer.
+var (
+	_ json.Marshaler   = Of[int]{}
+	_ json.Marshaler   = (*Of[int])(nil)
+	_ json.Unmarshaler = (*Of[int])(nil)
+	_ driver.Valuer    = Of[int]{}
+	_ driver.Valuer    = (*Of[int])(nil)
+	_ sql.Scanner      = (*Of[int])(nil)
+
+	_ Getter[int]    = (*Of[int])(nil)
+	_ Setter[int]    = (*Of[int])(nil)
+	_ PresenceI[int] = (*Of[int])(nil)
+)
+
 type Of[T any] struct {
-	val          *T
-	isSet        bool
-	marshalUnset *MarshalUnsetBehavior
-	scanNull     *ScanNullBehavior
+	val   *T
+	flags flags
+}
+
+// flags packs the set state and the per-value behavior overrides so that Of[T] takes
+// two words, which matters for wide rows. Each override takes two bits holding the
+// behavior plus one, zero standing for the package default.
+type flags uint16
+
+const (
+	// flagSet marks set values, null or not.
+	flagSet flags = 1
+	// flagArena marks, during a Decoder.Scan call only, that val points to the storage
+	// the scanned value goes to.
+	flagArena flags = 1 << 7
+)
+
+const (
+	marshalUnsetShift = 1
+	scanNullShift     = 3
+	valueUnsetShift   = 5
+	overrideMask      = 0b11
+)
+
+// override returns the behavior stored at shift and whether it is set.
+func (f flags) override(shift uint) (int, bool) {
+	v := int(f>>shift) & overrideMask
+
+	return v - 1, v != 0
+}
+
+// withOverride returns f with the behavior b stored at shift.
+func (f flags) withOverride(shift uint, b int) flags {
+	return f&^(overrideMask<<shift) | flags(b+1)<<shift
+}
+
+// isSet reports whether the value is set (null or value).
+func (n *Of[T]) isSet() bool {
+	return n.flags&flagSet != 0
 }
 
 // IsNull returns true iff the value is nil and it is set
 func (n *Of[T]) IsNull() bool {
-	return n != nil && n.val == nil && n.isSet
+	return n != nil && n.val == nil && n.isSet()
 }
 
 // IsUnset returns true iff it is not set
 func (n *Of[T]) IsUnset() bool {
-	return n == nil || !n.isSet
+	return n == nil || !n.isSet()
 }
 
 // IsSet returns true iff it is set
 func (n *Of[T]) IsSet() bool {
-	return n != nil && n.isSet
+	return n != nil && n.isSet()
+}
+
+// State returns the presence state of the value.
+func (n *Of[T]) State() State {
+	switch {
+	case n.IsUnset():
+		return StateUnset
+	case n.val == nil:
+		return StateNull
+	default:
+		return StateValue
+	}
 }
 
 // GetValue implements the getter.
@@ -70,6 +138,19 @@ func (n *Of[T]) MustGet() T {
 	return *n.val
 }
 
+// MustGetNamed returns the value if present, otherwise panics with a message naming the
+// field and its state, which identifies the field in production stack traces:
+//
+//	email := user.Email.MustGetNamed("email")
+//	// panic: presence: MustGetNamed called on null field email (*presence.Of[string])
+func (n *Of[T]) MustGetNamed(field string) T {
+	if n == nil || n.val == nil {
+		panic(fmt.Sprintf("presence: MustGetNamed called on %s field %s (%T)", n.State(), field, n))
+	}
+
+	return *n.val
+}
+
 // Ptr returns a pointer to the value, or nil if null or unset.
 func (n *Of[T]) Ptr() *T {
 	if n == nil {
@@ -81,10 +162,12 @@ func (n *Of[T]) Ptr() *T {
 
 // IsValue returns true if the value is set and not null.
 func (n *Of[T]) IsValue() bool {
-	return n != nil && n.isSet && n.val != nil
+	return n != nil && n.isSet() && n.val != nil
 }
 
 // SetValue implements the setter.
+// Time values are normalized according to SetDefaultTimeNormalization, then values
+// by the normalizer registered for T if any (see RegisterNormalizer).
 func (n *Of[T]) SetValue(b T) {
 	if n == nil {
 		n = new(Of[T])
@@ -93,8 +176,85 @@ func (n *Of[T]) SetValue(b T) {
 		return
 	}
 
-	n.isSet = true
+	n.storeValue(&b)
+}
+
+// storeValue sets the value stored at p, normalizing it.
+func (n *Of[T]) storeValue(p *T) {
+	normalizeValue(p)
+
+	n.flags |= flagSet
+	n.val = p
+	n.trace("SetValue")
+}
+
+// SetValueChecked sets the value like SetValue, then runs the validator registered for T
+// on it (see RegisterValidator). A rejected value leaves n unchanged.
+func (n *Of[T]) SetValueChecked(b T) error {
+	if n == nil {
+		n = new(Of[T])
+	}
+
+	normalizeValue(&b)
+
+	err := validateValue(b)
+	if err != nil {
+		return err
+	}
+
+	n.flags |= flagSet
 	n.val = &b
+	n.trace("SetValue")
+
+	return nil
+}
+
+// normalizeValue applies the time normalization and the normalizer registered for T to *p.
+func normalizeValue[T any](p *T) {
+	if t, ok := any(p).(*time.Time); ok {
+		*t = GetDefaultTimeNormalization().normalize(*t)
+	}
+
+	if fn := normalizerOf[T](); fn != nil {
+		*p = fn(*p)
+	}
+}
+
+// validateValue runs the validator registered for T on v.
+func validateValue[T any](v T) error {
+	fn := validatorOf[T]()
+	if fn == nil {
+		return nil
+	}
+
+	err := fn(v)
+	if err != nil {
+		return fmt.Errorf("presence validating %T : %w", v, err)
+	}
+
+	return nil
+}
+
+// setScanned sets the value decoded by Scan, into the storage of a Decoder if any.
+func (n *Of[T]) setScanned(b T) {
+	if n.flags&flagArena == 0 {
+		n.SetValue(b)
+
+		return
+	}
+
+	n.flags &^= flagArena
+	*n.val = b
+	n.storeValue(n.val)
+}
+
+// scanStorage returns the storage of the value to decode, that of a Decoder if any.
+func (n *Of[T]) scanStorage() *T {
+	if n.flags&flagArena != 0 {
+		return n.val
+	}
+
+	return new(T)
 }
 
 // SetValueP implements the setter by pointer.
@@ -118,8 +278,9 @@ func (n *Of[T]) SetNull() {
 		n = new(Of[T])
 	}
 
-	n.isSet = true
+	n.flags |= flagSet
 	n.val = nil
+	n.trace("SetNull")
 }
 
 // Unset resets to unset state.
@@ -128,8 +289,31 @@ func (n *Of[T]) Unset() {
 		n = new(Of[T])
 	}
 
-	n.isSet = false
+	n.flags &^= flagSet
 	n.val = nil
+	n.trace("Unset")
+}
+
+// WithValue returns a copy of n holding b, keeping the behavior settings of n.
+// Unlike SetValue, it leaves n untouched, for functional-style code and table-driven tests.
+func (n Of[T]) WithValue(b T) Of[T] {
+	n.SetValue(b)
+
+	return n
+}
+
+// WithNull returns a null copy of n, keeping the behavior settings of n.
+func (n Of[T]) WithNull() Of[T] {
+	n.SetNull()
+
+	return n
+}
+
+// WithUnset returns an unset copy of n, keeping the behavior settings of n.
+func (n Of[T]) WithUnset() Of[T] {
+	n.Unset()
+
+	return n
 }
 
 // SetMarshalUnset sets per-value marshal unset behavior.
@@ -137,16 +321,23 @@ func (n *Of[T]) SetMarshalUnset(b MarshalUnsetBehavior) {
 	if n == nil {
 		return
 	}
-	n.marshalUnset = &b
+	n.flags = n.flags.withOverride(marshalUnsetShift, int(b))
 }
 
-// GetMarshalUnset returns the effective marshal unset behavior.
+// GetMarshalUnset returns the effective marshal unset behavior: the per-value setting,
+// else the one registered for T, else the package-level default.
 func (n *Of[T]) GetMarshalUnset() MarshalUnsetBehavior {
-	if n == nil || n.marshalUnset == nil {
-		return GetDefaultMarshalUnset()
+	if n != nil {
+		if b, ok := n.flags.override(marshalUnsetShift); ok {
+			return MarshalUnsetBehavior(b)
+		}
 	}
 
-	return *n.marshalUnset
+	if td, ok := typeDefaultsOf[T](); ok {
+		return td.marshalUnset
+	}
+
+	return GetDefaultMarshalUnset()
 }
 
 // SetScanNull sets per-value scan null behavior.
@@ -154,27 +345,82 @@ func (n *Of[T]) SetScanNull(b ScanNullBehavior) {
 	if n == nil {
 		return
 	}
-	n.scanNull = &b
+	n.flags = n.flags.withOverride(scanNullShift, int(b))
 }
 
-// GetScanNull returns the effective scan null behavior.
+// GetScanNull returns the effective scan null behavior: the per-value setting,
+// else the one registered for T, else the package-level default.
 func (n *Of[T]) GetScanNull() ScanNullBehavior {
-	if n == nil || n.scanNull == nil {
-		return GetDefaultScanNull()
+	if n != nil {
+		if b, ok := n.flags.override(scanNullShift); ok {
+			return ScanNullBehavior(b)
+		}
+	}
+
+	if td, ok := typeDefaultsOf[T](); ok {
+		return td.scanNull
 	}
 
-	return *n.scanNull
+	return GetDefaultScanNull()
+}
+
+// SetValueUnset sets per-value value unset behavior.
+func (n *Of[T]) SetValueUnset(b ValueUnsetBehavior) {
+	if n == nil {
+		return
+	}
+	n.flags = n.flags.withOverride(valueUnsetShift, int(b))
+}
+
+// GetValueUnset returns the effective value unset behavior.
+func (n *Of[T]) GetValueUnset() ValueUnsetBehavior {
+	if n == nil {
+		return GetDefaultValueUnset()
+	}
+
+	if b, ok := n.flags.override(valueUnsetShift); ok {
+		return ValueUnsetBehavior(b)
+	}
+
+	return GetDefaultValueUnset()
 }
 
 // MarshalJSON implements the encoding json interface.
-// Note: UnsetSkip behavior requires the struct field to have the `omitempty` tag.
-// When marshaling directly (not as a struct field), unset values marshal as null.
+// Note: UnsetSkip behavior requires the struct field to have the `omitzero` tag.
+// When marshaling directly (not as a struct field), unset values marshal as null,
+// unless UnsetError makes them fail with ErrUnsetMarshal.
+// Null containers marshal as {} or [] with MarshalNullAsEmpty.
+// Values of types implementing json.Marshaler or encoding.TextMarshaler, with a value
+// or a pointer receiver, are encoded by their own method.
+// The value receiver makes the method available on both Of[T] and *Of[T];
+// encoding/json writes null for a nil *Of[T] without calling it.
 func (n Of[T]) MarshalJSON() ([]byte, error) {
+	if n.IsUnset() && n.GetMarshalUnset() == UnsetError {
+		return nil, fmt.Errorf("%w : %T", ErrUnsetMarshal, n)
+	}
+
+	if n.IsNull() && GetDefaultMarshalNull() == MarshalNullAsEmpty {
+		if empty := emptyJSON[T](); empty != nil {
+			return empty, nil
+		}
+	}
+
 	if n.IsUnset() || n.IsNull() {
 		return []byte("null"), nil
 	}
 
-	b, err := json.Marshal(n.GetValue())
+	// Big integers encode as JSON strings, which parsers do not round to float64.
+	if i, ok := any(n.val).(*big.Int); ok {
+		return strconv.AppendQuote(nil, i.String()), nil
+	}
+
+	// Encoding the pointer lets encoding/json find the methods with a pointer receiver.
+	var value any = n.GetValue()
+	if isTypedUUID[T]() {
+		value, _ = typedValue(*n.val)
+	}
+
+	b, err := marshalDBOnly(value)
 	if err != nil {
 		return nil, fmt.Errorf("presence json marshaling %T : %w", n, err)
 	}
@@ -194,7 +440,24 @@ func (n Of[T]) IsZero() bool {
 }
 
 // UnmarshalJSON implements the decoding json interface.
+// Values of types implementing json.Unmarshaler or encoding.TextUnmarshaler are
+// decoded by their own method.
+// Values rejected by the validator registered for T leave n unchanged, like
+// SetValueChecked.
+// Payloads exceeding the JSONLimits fail with ErrLimitsExceeded, and objects with
+// duplicate keys fail with ErrDuplicateKey when DuplicateKeysReject is configured.
+// Errors are reported to the MetricsHook.
 func (n *Of[T]) UnmarshalJSON(data []byte) error {
+	err := n.unmarshalJSON(data)
+	if err != nil {
+		observeError[T](metricUnmarshal, err)
+	}
+
+	return err
+}
+
+// unmarshalJSON decodes data into n.
+func (n *Of[T]) unmarshalJSON(data []byte) error {
 	if n == nil {
 		n = new(Of[T])
 	}
@@ -205,30 +468,122 @@ func (n *Of[T]) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	if n.val == nil && string(data) != "undefined" {
-		n.val = new(T)
+	err := GetDefaultJSONLimits().check(data)
+	if err != nil {
+		return err
+	}
+
+	if GetDefaultDuplicateKeys() == DuplicateKeysReject {
+		err = CheckDuplicateKeys(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isTypedUUID[T]() {
+		return n.unmarshalTypedUUID(data)
+	}
+
+	// Decoded apart from n.val, which copies of n share, so that a rejected value
+	// reaches none of them.
+	v := new(T)
+	if i, ok := any(v).(*big.Int); ok {
+		err = unmarshalBigInt(i, data)
+	} else if GetDefaultNumberDecoding() == NumbersAsJSONNumber {
+		err = unmarshalUseNumber(data, v)
+	} else {
+		err = json.Unmarshal(data, v)
 	}
 
-	err := json.Unmarshal(data, n.val)
 	if err != nil {
 		return fmt.Errorf("presence Unmarshal Error : %w", err)
 	}
 
-	n.isSet = true
+	normalizeValue(v)
+
+	err = validateValue(*v)
+	if err != nil {
+		return err
+	}
+
+	n.flags |= flagSet
+	n.val = v
+	n.trace("SetValue")
+
+	return nil
+}
+
+// unmarshalUseNumber decodes the JSON value data into v like json.Unmarshal, the
+// numbers decoded into interfaces being json.Number.
+func unmarshalUseNumber(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	err := dec.Decode(v)
+	if err != nil {
+		return fmt.Errorf("decoding numbers as json.Number : %w", err)
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("data after the JSON value")
+	}
+
+	return nil
+}
+
+// unmarshalBigInt decodes into i a JSON string or number holding an integer.
+func unmarshalBigInt(i *big.Int, data []byte) error {
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	if _, ok := i.SetString(text, 10); !ok {
+		return fmt.Errorf("invalid integer %s", data)
+	}
 
 	return nil
 }
 
+// isDefault reports whether Value() returns the Default sentinel, for the statement
+// builders rendering it as DEFAULT. The value receiver covers the values stored in rows.
+func (n Of[T]) isDefault() bool {
+	return n.IsUnset() && n.GetValueUnset() == ValueUnsetDefault
+}
+
 // Value implements the driver.Valuer interface.
+// The value receiver lets database/sql bind Of[T] and *Of[T] arguments alike, whether
+// the model field is addressable or not; database/sql turns a nil *Of[T] into NULL
+// without calling it.
+// Null values are stored as NULL. Unset values depend on the ValueUnsetBehavior.
+// Values stored as JSON are encoded according to the JSONValueBehavior, map[string]string
+// values as JSON or hstore according to the MapValueBehavior.
 func (n Of[T]) Value() (driver.Value, error) {
+	if n.IsUnset() {
+		switch n.GetValueUnset() {
+		case ValueUnsetDefault:
+			return Default, nil
+		case ValueUnsetError:
+			return nil, ErrUnsetValue
+		case ValueUnsetNull:
+		}
+	}
+
 	if n.val == nil {
 		return nil, nil
 	}
 
+	if id, ok := any(n.val).(*uuid.UUID); ok {
+		return uuidValue(*id), nil
+	}
+
 	switch value := any(n.val).(type) {
 	case *string, *int16, *int32, *int, *int64, *float64, *bool, *time.Time, *uuid.UUID, string,
 		int16, int32, int, int64, float64, bool, time.Time, uuid.UUID:
 		return *n.val, nil
+	case *big.Int:
+		// NUMERIC columns take the decimal text, which no driver truncates.
+		return value.String(), nil
 	case any:
 		if value == nil {
 			return nil, nil
@@ -243,11 +598,35 @@ func (n Of[T]) Value() (driver.Value, error) {
 			return v, nil
 		}
 
+		if isComposite[T]() {
+			return compositeValue(value)
+		}
+
+		if m, ok := value.(*map[string]string); ok && GetDefaultMapValue() == MapValueHstore {
+			return hstoreValue(*m), nil
+		}
+
+		if base, ok := typedValue(*n.val); ok {
+			if id, ok := base.(uuid.UUID); ok {
+				return uuidValue(id), nil
+			}
+
+			return base, nil
+		}
+
+		if err := jsonFallbackDenied[T](); err != nil {
+			return nil, err
+		}
+
 		b, err := json.Marshal(value)
 		if err != nil {
 			return nil, fmt.Errorf("presence database value error : %w", err)
 		}
 
+		if GetDefaultJSONValue() == JSONValueBytes {
+			return b, nil
+		}
+
 		return string(b), nil
 	}
 
@@ -256,11 +635,31 @@ func (n Of[T]) Value() (driver.Value, error) {
 
 // Scan implements the sql.Scanner interface.
 // This method decodes a JSON-encoded value into the struct.
+// Values rejected by the validator registered for T leave n unset.
+// Errors are reported to the MetricsHook.
 func (n *Of[T]) Scan(v any) error {
 	if n == nil {
 		n = new(Of[T])
 	}
 
+	err := n.scan(v)
+	if err == nil && n.IsValue() {
+		err = validateValue(*n.val)
+		if err != nil {
+			n.Unset()
+		}
+	}
+
+	if err != nil {
+		observeError[T](metricScan, err)
+	}
+
+	return err
+}
+
+// scan decodes v according to the type of the values.
+func (n *Of[T]) scan(v any) error {
+
 	// Use a zero value of T to determine the type, since n.val may be nil
 	switch any(new(T)).(type) {
 	case *string:
@@ -271,10 +670,18 @@ func (n *Of[T]) Scan(v any) error {
 		return n.scanInt(v)
 	case *float64:
 		return n.scanFloat(v)
+	case *big.Int:
+		return n.scanBigInt(v)
 	case *bool:
 		return n.scanBool(v)
 	case *time.Time:
 		return n.scanTime(v)
+	case *map[string]string:
+		return n.scanHstore(v)
+	}
+
+	if isComposite[T]() {
+		return n.scanComposite(v)
 	}
 
 	if scaner, ok := v.(sql.Scanner); ok {
@@ -285,5 +692,24 @@ func (n *Of[T]) Scan(v any) error {
 		return nil
 	}
 
+	if ok, err := n.scanTyped(v); ok {
+		return err
+	}
+
+	if v != nil {
+		if err := jsonFallbackDenied[T](); err != nil {
+			return err
+		}
+	}
+
 	return n.scanJSON(v)
 }
+
+// uuidValue encodes id according to the UUIDValueBehavior.
+func uuidValue(id uuid.UUID) driver.Value {
+	if GetDefaultUUIDValue() == UUIDValueBinary {
+		return id[:]
+	}
+
+	return id
+}
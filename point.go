@@ -0,0 +1,173 @@
+package presence
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidPoint is returned for geometries and GeoJSON documents that are not a 2D point.
+var ErrInvalidPoint = errors.New("presence: invalid point")
+
+// EWKB geometry type of a point, and the flag announcing an SRID.
+const (
+	wkbPoint   uint32 = 1
+	ewkbSRID   uint32 = 0x20000000
+	ewkbFlags  uint32 = 0xF0000000
+	sridWGS84  uint32 = 4326
+	ewkbLength        = 1 + 4 + 4 + 8 + 8
+)
+
+// Point is a WGS 84 location (SRID 4326), in degrees. Nullable location columns are
+// Of[Point]:
+//
+//	type Shop struct {
+//		Location presence.Of[presence.Point] `db:"location" json:"location,omitzero"`
+//	}
+//
+// It is stored in PostGIS geometry and geography columns as hex EWKB, and scanned from
+// hex or binary EWKB, as returned by the drivers. It encodes in JSON as a GeoJSON point,
+// longitude first:
+//
+//	{"type":"Point","coordinates":[2.3522,48.8566]}
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// String returns the point as WKT, e.g. "POINT(2.3522 48.8566)".
+func (p Point) String() string {
+	return fmt.Sprintf("POINT(%v %v)", p.Lng, p.Lat)
+}
+
+// Value implements the driver.Valuer interface, encoding the point as hex EWKB.
+func (p Point) Value() (driver.Value, error) {
+	b := make([]byte, 0, ewkbLength)
+	b = append(b, 1) // little endian
+	b = binary.LittleEndian.AppendUint32(b, wkbPoint|ewkbSRID)
+	b = binary.LittleEndian.AppendUint32(b, sridWGS84)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.Lng))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.Lat))
+
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// Scan implements the sql.Scanner interface, decoding hex or binary EWKB (or WKB).
+// Points in another SRID than 4326 and points with Z or M coordinates are rejected.
+func (p *Point) Scan(v any) error {
+	var b []byte
+
+	switch v := v.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("%w : cannot scan %T", ErrInvalidPoint, v)
+	}
+
+	// Binary EWKB starts with its byte order, 0 or 1; hex EWKB with its text.
+	if len(b) > 0 && b[0] > 1 {
+		decoded, err := hex.DecodeString(string(b))
+		if err != nil {
+			return fmt.Errorf("%w : %w", ErrInvalidPoint, err)
+		}
+
+		b = decoded
+	}
+
+	point, err := parseEWKB(b)
+	if err != nil {
+		return err
+	}
+
+	*p = point
+
+	return nil
+}
+
+// parseEWKB decodes a 2D point, in SRID 4326 when it has one.
+func parseEWKB(b []byte) (Point, error) {
+	if len(b) < 1+4 {
+		return Point{}, fmt.Errorf("%w : truncated EWKB", ErrInvalidPoint)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if b[0] == 0 {
+		order = binary.BigEndian
+	}
+
+	typ := order.Uint32(b[1:])
+	b = b[5:]
+
+	if typ&ewkbSRID != 0 {
+		if len(b) < 4 {
+			return Point{}, fmt.Errorf("%w : truncated EWKB", ErrInvalidPoint)
+		}
+
+		if srid := order.Uint32(b); srid != sridWGS84 {
+			return Point{}, fmt.Errorf("%w : SRID %d instead of %d", ErrInvalidPoint, srid, sridWGS84)
+		}
+
+		b = b[4:]
+	}
+
+	if typ&^ewkbSRID != wkbPoint {
+		return Point{}, fmt.Errorf("%w : geometry type %#x is not a 2D point", ErrInvalidPoint, typ&^ewkbFlags)
+	}
+
+	if len(b) != 8+8 {
+		return Point{}, fmt.Errorf("%w : %d coordinate bytes instead of 16", ErrInvalidPoint, len(b))
+	}
+
+	p := Point{
+		Lng: math.Float64frombits(order.Uint64(b)),
+		Lat: math.Float64frombits(order.Uint64(b[8:])),
+	}
+
+	// PostGIS encodes POINT EMPTY with NaN coordinates.
+	if math.IsNaN(p.Lng) || math.IsNaN(p.Lat) {
+		return Point{}, fmt.Errorf("%w : empty point", ErrInvalidPoint)
+	}
+
+	return p, nil
+}
+
+// geoJSONPoint is the GeoJSON encoding of Point.
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// MarshalJSON implements the encoding json interface, encoding the point as GeoJSON.
+func (p Point) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(geoJSONPoint{Type: "Point", Coordinates: []float64{p.Lng, p.Lat}})
+	if err != nil {
+		return nil, fmt.Errorf("presence point marshaling : %w", err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalJSON implements the decoding json interface, decoding a GeoJSON point.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var v geoJSONPoint
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return fmt.Errorf("presence point unmarshaling : %w", err)
+	}
+
+	if v.Type != "Point" || len(v.Coordinates) != 2 {
+		return fmt.Errorf("%w : %s is not a 2D GeoJSON point", ErrInvalidPoint, data)
+	}
+
+	*p = Point{Lng: v.Coordinates[0], Lat: v.Coordinates[1]}
+
+	return nil
+}
@@ -6,12 +6,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type PresenceI[T any] interface {
+// Getter is the read-only subset of PresenceI, for APIs which only read presence
+// values and for the mocks of such APIs.
+type Getter[T any] interface {
 	// IsNull returns true if itself is nil or the value is nil/null
 	IsNull() bool
 	// IsUnset returns true if the value has not been set
@@ -20,6 +25,8 @@ type PresenceI[T any] interface {
 	IsSet() bool
 	// IsValue returns true if the value is set and not null
 	IsValue() bool
+	// State returns the presence state (unset, null or value).
+	State() State
 	// GetValue implements the getter (returns pointer).
 	GetValue() *T
 	// Get returns the value and a boolean indicating presence.
@@ -30,6 +37,10 @@ type PresenceI[T any] interface {
 	MustGet() T
 	// Ptr returns a pointer to the value, or nil if null/unset.
 	Ptr() *T
+}
+
+// Setter is the write-only subset of PresenceI.
+type Setter[T any] interface {
 	// SetValue implements the setter.
 	SetValue(T)
 	// SetValueP implements the setter by pointer.
@@ -38,6 +49,12 @@ type PresenceI[T any] interface {
 	SetNull()
 	// Unset resets to unset state
 	Unset()
+}
+
+// PresenceI is the union of Getter, Setter and the encoding interfaces.
+type PresenceI[T any] interface {
+	Getter[T]
+	Setter[T]
 	// MarshalJSON implements the encoding json interface.
 	MarshalJSON() ([]byte, error)
 	// UnmarshalJSON implements the decoding json interface.
@@ -76,7 +93,7 @@ func (n *Of[T]) scanJSON(v any) error {
 	}
 
 	if null.Valid {
-		value := new(T)
+		value := n.scanStorage()
 
 		if scanner, ok := any(value).(sql.Scanner); ok {
 			err := scanner.Scan(v)
@@ -84,13 +101,18 @@ func (n *Of[T]) scanJSON(v any) error {
 				return fmt.Errorf("custom scanner error on presence : %w", err)
 			}
 		} else {
-			err := json.Unmarshal([]byte(null.String), value)
+			unmarshal := json.Unmarshal
+			if GetDefaultNumberDecoding() == NumbersAsJSONNumber {
+				unmarshal = unmarshalUseNumber
+			}
+
+			err := unmarshal([]byte(null.String), value)
 			if err != nil {
 				return fmt.Errorf("presence database unmarshaling json : %w", err)
 			}
 		}
 
-		n.SetValue(*value)
+		n.setScanned(*value)
 	} else {
 		n.handleScanNull()
 	}
@@ -110,7 +132,7 @@ func (n *Of[T]) scanString(v any) error {
 	}
 
 	if null.Valid {
-		n.SetValue(any(null.String).(T))
+		n.setScanned(any(null.String).(T))
 	} else {
 		n.handleScanNull()
 	}
@@ -118,28 +140,85 @@ func (n *Of[T]) scanString(v any) error {
 	return nil
 }
 
+func (n *Of[T]) scanBigInt(v any) error {
+	if n == nil {
+		return errors.New("calling scanBigInt on nil receiver")
+	}
+
+	var value big.Int
+
+	switch src := v.(type) {
+	case nil:
+		n.handleScanNull()
+
+		return nil
+	case int64:
+		value.SetInt64(src)
+	case float64:
+		if math.IsInf(src, 0) || src != math.Trunc(src) {
+			return fmt.Errorf("presence database scanning big.Int : %v is not an integer", src)
+		}
+
+		big.NewFloat(src).Int(&value)
+	default:
+		null := sql.NullString{}
+		err := null.Scan(v)
+		if err != nil {
+			return fmt.Errorf("presence database scanning big.Int : %w", err)
+		}
+
+		// NUMERIC columns come as decimal text.
+		if _, ok := value.SetString(null.String, 10); !ok {
+			return fmt.Errorf("presence database scanning big.Int : invalid integer %q", null.String)
+		}
+	}
+
+	n.setScanned(any(value).(T))
+
+	return nil
+}
+
 func (n *Of[T]) scanUUID(v any) error {
 	if n == nil {
 		return errors.New("calling scanUUID on nil receiver")
 	}
 
-	null := sql.NullString{}
-	err := null.Scan(v)
-	if err != nil {
-		return fmt.Errorf("presence database scanning string : %w", err)
-	}
+	var (
+		uid uuid.UUID
+		err error
+	)
 
-	if null.Valid {
-		uid, err := uuid.Parse(null.String)
+	switch value := v.(type) {
+	case nil:
+		n.handleScanNull()
+
+		return nil
+	case []byte:
+		// BINARY(16) columns hold the raw bytes, other drivers send the text form as bytes.
+		if len(value) == len(uid) {
+			uid, err = uuid.FromBytes(value)
+		} else {
+			uid, err = uuid.ParseBytes(value)
+		}
+	case [16]byte:
+		uid = value
+	default:
+		null := sql.NullString{}
+		err = null.Scan(v)
 		if err != nil {
-			return fmt.Errorf("UUID parsing failed : %w", err)
+			return fmt.Errorf("presence database scanning string : %w", err)
 		}
 
-		n.SetValue(any(uid).(T))
-	} else {
-		n.handleScanNull()
+		// Parse accepts the braced {xxxxxxxx-...} and urn:uuid: forms as well.
+		uid, err = uuid.Parse(null.String)
+	}
+
+	if err != nil {
+		return fmt.Errorf("UUID parsing failed : %w", err)
 	}
 
+	n.setScanned(any(uid).(T))
+
 	return nil
 }
 
@@ -153,7 +232,7 @@ func (n *Of[T]) scanInt(v any) error {
 		}
 
 		if null.Valid {
-			n.SetValue(any(null.Int16).(T))
+			n.setScanned(any(null.Int16).(T))
 		} else {
 			n.handleScanNull()
 		}
@@ -167,7 +246,7 @@ func (n *Of[T]) scanInt(v any) error {
 		}
 
 		if null.Valid {
-			n.SetValue(any(null.Int32).(T))
+			n.setScanned(any(null.Int32).(T))
 		} else {
 			n.handleScanNull()
 		}
@@ -181,7 +260,7 @@ func (n *Of[T]) scanInt(v any) error {
 		}
 
 		if null.Valid {
-			n.SetValue(any(int(null.Int64)).(T))
+			n.setScanned(any(int(null.Int64)).(T))
 		} else {
 			n.handleScanNull()
 		}
@@ -195,7 +274,7 @@ func (n *Of[T]) scanInt(v any) error {
 		}
 
 		if null.Valid {
-			n.SetValue(any(null.Int64).(T))
+			n.setScanned(any(null.Int64).(T))
 		} else {
 			n.handleScanNull()
 		}
@@ -214,7 +293,7 @@ func (n *Of[T]) scanFloat(v any) error {
 	}
 
 	if null.Valid {
-		n.SetValue(any(null.Float64).(T))
+		n.setScanned(any(null.Float64).(T))
 	} else {
 		n.handleScanNull()
 	}
@@ -230,7 +309,7 @@ func (n *Of[T]) scanBool(v any) error {
 	}
 
 	if null.Valid {
-		n.SetValue(any(null.Bool).(T))
+		n.setScanned(any(null.Bool).(T))
 	} else {
 		n.handleScanNull()
 	}
@@ -238,6 +317,31 @@ func (n *Of[T]) scanBool(v any) error {
 	return nil
 }
 
+// timeLayouts are the string layouts accepted when scanning a time, in the order they are tried.
+// They cover RFC 3339 and the "YYYY-MM-DD HH:MM:SS[.ffffff][zone]" forms emitted by MySQL and SQLite.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+}
+
+// parseTime parses s against timeLayouts.
+// Strings without a zone are read as UTC.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("presence cannot parse %q as time", s)
+}
+
 func (n *Of[T]) scanTime(v any) error {
 	if v == nil {
 		n.handleScanNull()
@@ -245,30 +349,28 @@ func (n *Of[T]) scanTime(v any) error {
 		return nil
 	}
 
-	null := new(sql.NullTime)
+	var (
+		t   time.Time
+		err error
+	)
 
-	switch t := v.(type) {
+	switch value := v.(type) {
 	case string:
-		var err error
-		null.Time, err = time.Parse(t, t)
-		if err != nil {
-			return fmt.Errorf("%w", err)
-		}
+		t, err = parseTime(value)
+	case []byte:
+		t, err = parseTime(string(value))
 	case time.Time:
-		err := null.Scan(v)
-		if err != nil {
-			return fmt.Errorf("presence database scanning Time : %w", err)
-		}
+		t = value
 	default:
-		return fmt.Errorf("canot parse type \"%T\" with value \"%v\" to time", t, t)
+		return fmt.Errorf("canot parse type \"%T\" with value \"%v\" to time", value, value)
 	}
 
-	if null.Valid {
-		n.SetValue(any(null.Time).(T))
-	} else {
-		n.handleScanNull()
+	if err != nil {
+		return err
 	}
 
+	n.setScanned(any(t).(T))
+
 	return nil
 }
 
@@ -368,3 +470,158 @@ func FromBool[T any](value T, ok bool) Of[T] {
 
 	return Null[T]()
 }
+
+// FromSQL creates an Of[T] from the database value v like Scan, for driver rows and cache
+// entries in functional pipelines:
+//
+//	email, err := presence.FromSQL[string](row["email"])
+//
+// A nil v gives null, or unset according to the ScanNullBehavior. The error is the one
+// of Scan, the value returned being unset then.
+func FromSQL[T any](v any) (Of[T], error) {
+	var n Of[T]
+
+	err := n.Scan(v)
+	if err != nil {
+		return Of[T]{}, err
+	}
+
+	return n, nil
+}
+
+// MustFromJSON creates an Of[T] from the JSON literal s, panicking on error, for
+// fixtures and table-driven tests: "null" gives null, an empty s gives unset.
+//
+//	presence.MustFromJSON[int]("42")
+//	presence.MustFromJSON[[]string](`["a","b"]`)
+func MustFromJSON[T any](s string) Of[T] {
+	var n Of[T]
+	if s == "" {
+		return n
+	}
+
+	err := n.UnmarshalJSON([]byte(s))
+	if err != nil {
+		panic(fmt.Sprintf("presence: MustFromJSON[%s](%q) : %v", reflect.TypeFor[T](), s, err))
+	}
+
+	return n
+}
+
+// Ptrs converts a column of presence values into a slice of pointers where
+// null and unset values are nil.
+// Column-oriented drivers bind nullable columns from []*T and bypass driver.Valuer;
+// contrib/clickhouse uses it for the Nullable(T) columns of clickhouse-go.
+func Ptrs[T any](values []Of[T]) []*T {
+	out := make([]*T, len(values))
+	for i := range values {
+		out[i] = values[i].Ptr()
+	}
+
+	return out
+}
+
+// FromPtrs is the inverse of Ptrs: nil pointers become null values.
+func FromPtrs[T any](ptrs []*T) []Of[T] {
+	out := make([]Of[T], len(ptrs))
+	for i, ptr := range ptrs {
+		out[i] = FromPtr(ptr)
+	}
+
+	return out
+}
+
+// MapSlice applies fn to the presence field selected by field of each item, keeping the
+// null and unset states like Map:
+//
+//	emails := presence.MapSlice(users, func(u User) presence.Of[string] { return u.Email }, strings.ToLower)
+func MapSlice[S, T, U any](items []S, field func(S) Of[T], fn func(T) U) []Of[U] {
+	out := make([]Of[U], len(items))
+	for i, item := range items {
+		out[i] = Map(field(item), fn)
+	}
+
+	return out
+}
+
+// FilterSlice returns the items whose presence field selected by field holds a value
+// satisfying predicate, dropping those where it is null or unset.
+func FilterSlice[S, T any](items []S, field func(S) Of[T], predicate func(T) bool) []S {
+	var out []S
+	for _, item := range items {
+		n := field(item)
+		if v, ok := n.Get(); ok && predicate(v) {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+// GroupByPresence groups the items by the state of their presence field selected by
+// field, keeping their order, e.g. to tell the rows that never had a value from those
+// where it was cleared:
+//
+//	groups := presence.GroupByPresence(users, func(u User) presence.Of[time.Time] { return u.VerifiedAt })
+//	pending, revoked := groups[presence.StateUnset], groups[presence.StateNull]
+func GroupByPresence[S, T any](items []S, field func(S) Of[T]) map[State][]S {
+	groups := map[State][]S{}
+	for _, item := range items {
+		n := field(item)
+		groups[n.State()] = append(groups[n.State()], item)
+	}
+
+	return groups
+}
+
+// As converts a presence value of unknown type, as found in heterogeneous collections
+// such as []any or map[string]any, to an Of[T] with the same state.
+// v may be an Of[U] or a type embedding it (String…), by value or pointer, U being
+// assignable or convertible to T; nil pointers are unset. It returns false for any other v.
+func As[T any](v any) (Of[T], bool) {
+	state, value, ok := presenceOf(v)
+	if !ok {
+		return Of[T]{}, false
+	}
+
+	var out Of[T]
+	switch state {
+	case StateUnset:
+	case StateNull:
+		out.SetNull()
+	case StateValue:
+		if out.setAny(value) != nil {
+			return Of[T]{}, false
+		}
+	}
+
+	return out, true
+}
+
+// presenceOf returns the state and the value of the presence value v, and whether v is one.
+func presenceOf(v any) (State, any, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return StateUnset, nil, false
+	}
+
+	if rv.Kind() != reflect.Pointer {
+		if !reflect.PointerTo(rv.Type()).Implements(presenceFieldType) {
+			return StateUnset, nil, false
+		}
+
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	}
+
+	pf, ok := rv.Interface().(presenceField)
+	switch {
+	case !ok:
+		return StateUnset, nil, false
+	case rv.IsNil():
+		return StateUnset, nil, true
+	}
+
+	return pf.State(), pf.anyValue(), true
+}
@@ -0,0 +1,221 @@
+/*
+Package presencetest provides golden-file helpers for tests of presence structs.
+SaveGolden writes a struct in JSON keeping the three states apart, unset fields being
+left out and null ones written as null whatever their tags, and LoadFixture reads it
+back, so that tests can assert exact unset/null/value states:
+
+	var update = flag.Bool("update", false, "update the golden files")
+
+	func TestDecodePatch(t *testing.T) {
+		got := decodePatch(t, "testdata/patch.json")
+		if *update {
+			require.NoError(t, presencetest.SaveGolden("testdata/patch.golden.json", got))
+		}
+
+		var want Patch
+		require.NoError(t, presencetest.LoadFixture("testdata/patch.golden.json", &want))
+		assert.Equal(t, want, got)
+	}
+*/
+package presencetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pivaldi/presence"
+)
+
+// stater is implemented by the pointers to presence values.
+type stater interface {
+	State() presence.State
+}
+
+var staterType = reflect.TypeFor[stater]()
+
+// LoadFixture decodes the JSON file at path into dst, a pointer: absent presence fields
+// are unset and null ones null. Fields of the file missing from dst are rejected, to
+// catch typos in fixtures.
+func LoadFixture(path string, dst any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("presencetest loading fixture : %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(dst)
+	if err != nil {
+		return fmt.Errorf("presencetest decoding fixture %s : %w", path, err)
+	}
+
+	return nil
+}
+
+// SaveGolden writes v to the file at path as indented JSON with sorted keys, creating
+// its directory: unset presence fields are left out and null ones written as null,
+// whatever their tags.
+func SaveGolden(path string, v any) error {
+	data, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err == nil {
+		err = os.WriteFile(path, data, 0o600)
+	}
+
+	if err != nil {
+		return fmt.Errorf("presencetest saving golden : %w", err)
+	}
+
+	return nil
+}
+
+// Marshal returns the encoding of v written by SaveGolden.
+func Marshal(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("presencetest marshaling : %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var doc any
+
+	err = dec.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("presencetest marshaling : %w", err)
+	}
+
+	pruneUnset(reflect.ValueOf(v), doc)
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("presencetest marshaling : %w", err)
+	}
+
+	return append(out, '\n'), nil
+}
+
+// pruneUnset removes from doc, the decoded JSON encoding of rv, the unset presence
+// fields of the structs it holds.
+func pruneUnset(rv reflect.Value, doc any) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Struct {
+		if m, ok := doc.(map[string]any); ok {
+			pruneStruct(addressable(rv), m)
+		}
+
+		return
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if items, ok := doc.([]any); ok {
+			for i := range min(rv.Len(), len(items)) {
+				pruneUnset(rv.Index(i), items[i])
+			}
+		}
+
+		return
+	}
+
+	if m, ok := doc.(map[string]any); ok && rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		for _, key := range rv.MapKeys() {
+			pruneUnset(rv.MapIndex(key), m[key.String()])
+		}
+	}
+}
+
+// pruneStruct removes from m the unset presence fields of the addressable struct rv,
+// flattening its embedded structs like encoding/json does.
+func pruneStruct(rv reflect.Value, m map[string]any) {
+	for i := range rv.NumField() {
+		f := rv.Type().Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		field := rv.Field(i)
+		isPresence := reflect.PointerTo(f.Type).Implements(staterType)
+
+		if f.Anonymous && name == "" && !isPresence {
+			if embedded, ok := embeddedStruct(field); ok {
+				pruneStruct(embedded, m)
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if !field.CanInterface() {
+			continue
+		}
+
+		if isPresence {
+			pruneField(field, name, m)
+
+			continue
+		}
+
+		pruneUnset(field, m[name])
+	}
+}
+
+// embeddedStruct returns the struct embedded as field, through a pointer.
+func embeddedStruct(field reflect.Value) (reflect.Value, bool) {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		field = field.Elem()
+	}
+
+	return field, field.Kind() == reflect.Struct
+}
+
+// pruneField removes the presence field named name from m when unset, and prunes the
+// value it holds.
+func pruneField(field reflect.Value, name string, m map[string]any) {
+	if field.Addr().Interface().(stater).State() == presence.StateUnset {
+		delete(m, name)
+
+		return
+	}
+
+	if getValue := field.Addr().MethodByName("GetValue"); getValue.IsValid() {
+		pruneUnset(getValue.Call(nil)[0], m[name])
+	}
+}
+
+// addressable returns rv, copied when it is not addressable.
+func addressable(rv reflect.Value) reflect.Value {
+	if rv.CanAddr() {
+		return rv
+	}
+
+	out := reflect.New(rv.Type()).Elem()
+	out.Set(rv)
+
+	return out
+}
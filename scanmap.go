@@ -0,0 +1,111 @@
+package presence
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScanMap fills the struct pointed to by dst from row, a row of a dynamic source such
+// as GORM's Raw().Scan into maps, a document store or a CSV record keyed by its
+// headers. The keys are matched against the db tags of the fields, else their names
+// from the tag set with WithTag, json by default:
+//
+//	var user User
+//	err := presence.ScanMap(map[string]any{"id": int64(1), "email": nil}, &user)
+//	// user.Email is null, user.Name is unset
+//
+// Presence fields missing from row are unset and those whose value is nil are null,
+// their values being converted like Scan does, e.g. from strings or int64. Plain fields
+// missing from row are left intact, the others are scanned when they implement
+// sql.Scanner, else converted like Go conversions do, between numeric types for
+// example.
+func ScanMap(row map[string]any, dst any, opts ...Option) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence scan map target must be a non-nil struct pointer, got %T", dst)
+	}
+
+	rv = rv.Elem()
+
+	var err error
+
+	walkFields(rv.Type(), nil, newOptions(opts), func(name string, index []int, isPresence bool) {
+		if err != nil {
+			return
+		}
+
+		if dbName, _, _ := strings.Cut(rv.Type().FieldByIndex(index).Tag.Get("db"), ","); dbName != "" && dbName != "-" {
+			name = dbName
+		}
+
+		v, ok := row[name]
+		field := rv.FieldByIndex(index)
+
+		if isPresence {
+			err = scanMapField(field, name, v, ok)
+		} else if ok {
+			err = scanMapPlain(field, name, v)
+		}
+	})
+
+	return err
+}
+
+// scanMapField sets the presence field f named name from v, unset when missing.
+func scanMapField(f reflect.Value, name string, v any, ok bool) error {
+	pf := f.Addr().Interface().(presenceField)
+	if !ok {
+		pf.Unset()
+
+		return nil
+	}
+
+	if v == nil {
+		pf.SetNull()
+
+		return nil
+	}
+
+	// Values of the type of the field, e.g. []string from a document store, cannot
+	// always be scanned.
+	if setValue := f.Addr().MethodByName("SetValue"); setValue.IsValid() &&
+		reflect.TypeOf(v).AssignableTo(setValue.Type().In(0)) {
+		return pf.setAny(v)
+	}
+
+	err := f.Addr().Interface().(sql.Scanner).Scan(v)
+	if err != nil {
+		return fmt.Errorf("presence scanning %s : %w", name, err)
+	}
+
+	return nil
+}
+
+// scanMapPlain sets the plain field f named name from v, nil zeroing it.
+func scanMapPlain(f reflect.Value, name string, v any) error {
+	if v == nil {
+		f.SetZero()
+
+		return nil
+	}
+
+	if f.Kind() == reflect.Pointer {
+		f.Set(reflect.New(f.Type().Elem()))
+		f = f.Elem()
+	}
+
+	var err error
+	if reflect.PointerTo(f.Type()).Implements(scannerType) {
+		err = f.Addr().Interface().(sql.Scanner).Scan(v)
+	} else {
+		err = assign(f, v)
+	}
+
+	if err != nil {
+		return fmt.Errorf("presence scanning %s : %w", name, err)
+	}
+
+	return nil
+}
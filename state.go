@@ -0,0 +1,43 @@
+package presence
+
+// State is the presence state of an Of[T].
+// Switch statements over State are checked for exhaustiveness by the
+// exhaustive linter enabled in .golangci.yml.
+type State int
+
+const (
+	// StateUnset means the value has never been set.
+	StateUnset State = iota
+	// StateNull means the value has explicitly been set to null.
+	StateNull
+	// StateValue means the value holds a concrete value.
+	StateValue
+)
+
+// String implements fmt.Stringer.
+func (s State) String() string {
+	switch s {
+	case StateUnset:
+		return "unset"
+	case StateNull:
+		return "null"
+	case StateValue:
+		return "value"
+	}
+
+	return "unknown"
+}
+
+// Match calls the function matching the state of n and returns its result.
+// It is the expression form of an exhaustive switch over n.State().
+func Match[T, R any](n Of[T], onUnset func() R, onNull func() R, onValue func(T) R) R {
+	switch n.State() {
+	case StateUnset:
+		return onUnset()
+	case StateNull:
+		return onNull()
+	case StateValue:
+	}
+
+	return onValue(*n.val)
+}
@@ -0,0 +1,68 @@
+package presence
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldStats counts the states of a presence field over a set of structs.
+type FieldStats struct {
+	Field string
+	Unset int
+	Null  int
+	Value int
+}
+
+// Total returns the number of structs counted.
+func (s FieldStats) Total() int {
+	return s.Unset + s.Null + s.Value
+}
+
+// Stats returns the state counts of the presence fields of rows, in the order of the
+// fields, for data-quality dashboards or before a NOT NULL migration:
+//
+//	stats, err := presence.Stats(users, presence.WithTag("db"))
+//	for _, s := range stats {
+//		if s.Null == 0 && s.Unset == 0 {
+//			fmt.Printf("%s can be NOT NULL\n", s.Field)
+//		}
+//	}
+//
+// T must be a struct or a pointer to a struct.
+func Stats[T any](rows []T, opts ...Option) ([]FieldStats, error) {
+	o := newOptions(opts)
+	typ := reflect.TypeFor[T]()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("presence expected a struct, got %s", reflect.TypeFor[T]())
+	}
+
+	fields := presenceFields(typ, o)
+	stats := make([]FieldStats, len(fields))
+	for i, f := range fields {
+		stats[i].Field = f.name
+	}
+
+	for _, row := range rows {
+		rv, err := structValue(row)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, f := range fields {
+			switch fieldOf(rv, f).State() {
+			case StateUnset:
+				stats[i].Unset++
+			case StateNull:
+				stats[i].Null++
+			case StateValue:
+				stats[i].Value++
+			}
+		}
+	}
+
+	return stats, nil
+}
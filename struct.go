@@ -0,0 +1,949 @@
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivaldi/presence/naming"
+)
+
+// ErrNullNotAllowed is returned when a null value targets a field which cannot hold null.
+var ErrNullNotAllowed = errors.New("presence: null not allowed")
+
+// presenceField is implemented by *Of[T] and the types embedding it.
+type presenceField interface {
+	State() State
+	SetNull()
+	Unset()
+	anyValue() any
+	setAny(v any) error
+}
+
+var presenceFieldType = reflect.TypeFor[presenceField]()
+
+// anyValue returns the value boxed in an any, nil when null or unset.
+func (n *Of[T]) anyValue() any {
+	if !n.IsValue() {
+		return nil
+	}
+
+	return *n.val
+}
+
+// setAny sets the value from v, which must be assignable or convertible to T.
+// A nil v sets null.
+func (n *Of[T]) setAny(v any) error {
+	if v == nil {
+		n.SetNull()
+
+		return nil
+	}
+
+	if value, ok := v.(T); ok {
+		n.SetValue(value)
+
+		return nil
+	}
+
+	var value T
+	err := assign(reflect.ValueOf(&value).Elem(), v)
+	if err != nil {
+		return err
+	}
+
+	n.SetValue(value)
+
+	return nil
+}
+
+// assign sets dst to v, converting v when needed.
+func assign(dst reflect.Value, v any) error {
+	src := reflect.ValueOf(v)
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case src.Type().ConvertibleTo(dst.Type()):
+		dst.Set(src.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("presence cannot assign %T to %s", v, dst.Type())
+	}
+
+	return nil
+}
+
+// Option configures the struct-walking functions ToMap, Diff, PatchStruct and
+// InsertColumnsValues, as well as UnmarshalMerge and ApplyJSONPatch. Comparison options only affect Diff.
+// The options of a single function have their own type, such as InsertOption, so that
+// passing them elsewhere does not compile.
+type Option func(*options)
+
+type options struct {
+	tag             string
+	naming          naming.Strategy
+	equal           map[reflect.Type]func(a, b any) bool
+	floatEpsilon    float64
+	timeGranularity time.Duration
+	pad             bool
+	padding         any
+	nested          NestedMode
+	removeNull      bool
+
+	// ctx is the context of the Context variants, checked every contextCheckInterval
+	// fields; ctxErr holds its error once done.
+	ctx    context.Context
+	steps  int
+	ctxErr error
+}
+
+// NestedMode controls how ToMap and PatchStruct handle nested presence structs: the
+// struct fields, and the values of presence fields, whose struct type has presence fields.
+type NestedMode int
+
+const (
+	// NestedValue handles nested presence structs as any other value: ToMap returns them
+	// as is and PatchStruct replaces the destination field with them.
+	NestedValue NestedMode = iota
+	// NestedMaps makes ToMap return nested presence structs as maps of their set fields,
+	// {"address": {"city": "Paris"}}, and PatchStruct merge them into the destination.
+	NestedMaps
+	// NestedFlatten makes ToMap flatten the set fields of nested presence structs into
+	// dotted keys, {"address.city": "Paris"}, as MongoDB and Elasticsearch partial updates
+	// expect, and PatchStruct merge them into the destination.
+	NestedFlatten
+)
+
+// WithTag selects the struct tag naming the fields, "json" by default.
+// With WithTag("db") one presence struct can feed both the API and the persistence layers.
+// Fields without the tag keep their Go name, fields tagged "-" are skipped.
+func WithTag(tag string) Option {
+	return func(o *options) {
+		o.tag = tag
+	}
+}
+
+// WithNaming names the fields without tag by converting their Go name with s,
+// e.g. WithNaming(naming.Snake) maps CreatedAt to created_at.
+func WithNaming(s naming.Strategy) Option {
+	return func(o *options) {
+		o.naming = s
+	}
+}
+
+// EqualFunc makes Diff compare the values of type T with fn instead of reflect.DeepEqual.
+func EqualFunc[T any](fn func(a, b T) bool) Option {
+	return func(o *options) {
+		if o.equal == nil {
+			o.equal = map[reflect.Type]func(a, b any) bool{}
+		}
+
+		o.equal[reflect.TypeFor[T]()] = func(a, b any) bool {
+			return fn(a.(T), b.(T))
+		}
+	}
+}
+
+// WithFloatEpsilon makes Diff consider float32 and float64 values closer than epsilon equal,
+// suppressing the changes caused by serialization round trips.
+func WithFloatEpsilon(epsilon float64) Option {
+	return func(o *options) {
+		o.floatEpsilon = epsilon
+	}
+}
+
+// WithTimeGranularity makes Diff consider times less than d apart equal, whatever their
+// location, e.g. time.Microsecond for values read back from PostgreSQL.
+func WithTimeGranularity(d time.Duration) Option {
+	return func(o *options) {
+		o.timeGranularity = d
+	}
+}
+
+// WithNested sets how ToMap and PatchStruct handle nested presence structs,
+// NestedValue by default.
+func WithNested(mode NestedMode) Option {
+	return func(o *options) {
+		o.nested = mode
+	}
+}
+
+// contextCheckInterval is the number of fields walked between two checks of the context
+// of the Context variants.
+const contextCheckInterval = 64
+
+// newContextOptions returns the options of the Context variants, checking ctx.
+func newContextOptions(ctx context.Context, opts []Option) *options {
+	o := newOptions(opts)
+	o.ctx, o.ctxErr = ctx, ctx.Err()
+
+	return o
+}
+
+// done reports whether the context of the Context variants is done, checking it every
+// contextCheckInterval calls. It is always false without context.
+func (o *options) done() bool {
+	if o.ctx == nil {
+		return false
+	}
+
+	if o.ctxErr == nil {
+		o.steps++
+		if o.steps%contextCheckInterval == 0 {
+			o.ctxErr = o.ctx.Err()
+		}
+	}
+
+	return o.ctxErr != nil
+}
+
+// contextErr returns the error of the context of the Context variants once done.
+func (o *options) contextErr() error {
+	if o.ctxErr == nil {
+		return nil
+	}
+
+	return fmt.Errorf("presence walking struct : %w", o.ctxErr)
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{tag: "json"}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// structField is a presence field of a struct type.
+type structField struct {
+	name  string
+	index []int
+}
+
+type fieldsKey struct {
+	typ    reflect.Type
+	tag    string
+	naming naming.Strategy
+}
+
+// fieldsCache holds the []structField of the walked struct types.
+var fieldsCache sync.Map
+
+// presenceFields returns the presence fields of the struct type typ, including the
+// fields of its embedded structs.
+func presenceFields(typ reflect.Type, o *options) []structField {
+	// Strategies such as naming.Func cannot be map keys.
+	cacheable := o.naming == nil || reflect.TypeOf(o.naming).Comparable()
+	key := fieldsKey{typ: typ, tag: o.tag, naming: o.naming}
+	if cacheable {
+		if fields, ok := fieldsCache.Load(key); ok {
+			return fields.([]structField)
+		}
+	}
+
+	var fields []structField
+	walkFields(typ, nil, o, func(name string, index []int, isPresence bool) {
+		if isPresence {
+			fields = append(fields, structField{name: name, index: index})
+		}
+	})
+
+	if cacheable {
+		fieldsCache.Store(key, fields)
+	}
+
+	return fields
+}
+
+// walkFields calls fn for the exported fields of the struct type typ, flattening
+// its embedded structs like encoding/json does.
+func walkFields(typ reflect.Type, index []int, o *options, fn func(name string, index []int, isPresence bool)) {
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		name, tagged := fieldName(f, o)
+		if name == "-" || !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		isPresence := reflect.PointerTo(f.Type).Implements(presenceFieldType)
+
+		if f.Anonymous && !tagged && !isPresence && f.Type.Kind() == reflect.Struct {
+			walkFields(f.Type, fieldIndex, o, fn)
+
+			continue
+		}
+
+		if f.IsExported() {
+			fn(name, fieldIndex, isPresence)
+		}
+	}
+}
+
+// fieldName returns the name of f and whether the tag provides it.
+func fieldName(f reflect.StructField, o *options) (string, bool) {
+	name, _, _ := strings.Cut(f.Tag.Get(o.tag), ",")
+	if name == "" {
+		if o.naming != nil {
+			return o.naming.Convert(f.Name), false
+		}
+
+		return f.Name, false
+	}
+
+	return name, true
+}
+
+// structValue returns the addressable struct held by v, copying it when needed.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("presence expected a struct, got nil %T", v)
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("presence expected a struct, got %T", v)
+	}
+
+	if !rv.CanAddr() {
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+
+	return rv, nil
+}
+
+// fieldOf returns the presence field f of the struct value rv.
+func fieldOf(rv reflect.Value, f structField) presenceField {
+	return rv.FieldByIndex(f.index).Addr().Interface().(presenceField)
+}
+
+// ToMap returns the set presence fields of the struct v keyed by their names:
+// values map to themselves, nulls to nil and unset fields are left out.
+// The result suits update builders such as gorm's Updates(map[string]any).
+// Fields tagged presence:"apionly", such as computed API fields, are left out too,
+// those tagged presence:"dbonly" being left out of the JSON of Marshal instead.
+func ToMap(v any, opts ...Option) (map[string]any, error) {
+	return newOptions(opts).toMap(v)
+}
+
+// ToMapContext is ToMap aborting with the error of ctx once it is done, for very
+// large structs walked under a request deadline.
+func ToMapContext(ctx context.Context, v any, opts ...Option) (map[string]any, error) {
+	return newContextOptions(ctx, opts).toMap(v)
+}
+
+func (o *options) toMap(v any) (map[string]any, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]any{}
+	if o.nested == NestedValue {
+		for _, f := range presenceFields(rv.Type(), o) {
+			if o.done() {
+				return nil, o.contextErr()
+			}
+
+			pf := fieldOf(rv, f)
+			if pf.State() != StateUnset && !hasPresenceOption(rv.Type().FieldByIndex(f.index), "apionly") {
+				out[f.name] = pf.anyValue()
+			}
+		}
+
+		return out, nil
+	}
+
+	o.nestedToMap(rv, "", out)
+	if err := o.contextErr(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// nestedToMap adds the set fields of the struct rv to out, their names prefixed with
+// prefix, handling the nested presence structs according to the NestedMode.
+func (o *options) nestedToMap(rv reflect.Value, prefix string, out map[string]any) {
+	walkFields(rv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		if o.done() || hasPresenceOption(rv.Type().FieldByIndex(index), "apionly") {
+			return
+		}
+
+		field := rv.FieldByIndex(index)
+		if !isPresence {
+			if nested, ok := o.nestedStruct(field); ok {
+				o.addNested(nested, prefix+name, out)
+			}
+
+			return
+		}
+
+		pf := field.Addr().Interface().(presenceField)
+		if pf.State() == StateUnset {
+			return
+		}
+
+		if nested, ok := o.nestedStruct(reflect.ValueOf(pf.anyValue())); ok {
+			o.addNested(nested, prefix+name, out)
+
+			return
+		}
+
+		out[prefix+name] = pf.anyValue()
+	})
+}
+
+// addNested adds the set fields of the nested presence struct rv named name to out.
+// Nested structs without set fields are left out.
+func (o *options) addNested(rv reflect.Value, name string, out map[string]any) {
+	if o.nested == NestedFlatten {
+		o.nestedToMap(rv, name+".", out)
+
+		return
+	}
+
+	m := map[string]any{}
+	o.nestedToMap(rv, "", m)
+	if len(m) > 0 {
+		out[name] = m
+	}
+}
+
+// nestedStruct returns the struct held by v, through pointers, when its type has
+// presence fields.
+func (o *options) nestedStruct(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || len(presenceFields(v.Type(), o)) == 0 {
+		return reflect.Value{}, false
+	}
+
+	if !v.CanAddr() {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+
+	return v, true
+}
+
+// InsertOption configures InsertColumnsValues: an Option or WithPadding.
+type InsertOption interface {
+	applyInsert(o *options)
+}
+
+func (f Option) applyInsert(o *options) { f(o) }
+
+type insertOption func(*options)
+
+func (f insertOption) applyInsert(o *options) { f(o) }
+
+// WithPadding makes InsertColumnsValues include the presence fields set in any row,
+// the unset ones taking the value v, e.g. Default, which InsertSQL writes as DEFAULT,
+// or nil.
+func WithPadding(v any) InsertOption {
+	return insertOption(func(o *options) {
+		o.pad = true
+		o.padding = v
+	})
+}
+
+// InsertColumnsValues returns the columns and the values of rows for a bulk insert or a
+// COPY. Plain fields are always included, presence fields only when set in every row so
+// that the database fills the others with their column default, unless WithPadding says
+// otherwise. Set presence values are returned as is, encoded by their driver.Valuer.
+// T must be a struct or a pointer to a struct.
+func InsertColumnsValues[T any](rows []T, opts ...InsertOption) ([]string, [][]any, error) {
+	o := newOptions(nil)
+	for _, opt := range opts {
+		opt.applyInsert(o)
+	}
+
+	typ := reflect.TypeFor[T]()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("presence expected a struct, got %s", reflect.TypeFor[T]())
+	}
+
+	var fields []columnField
+	walkFields(typ, nil, o, func(name string, index []int, isPresence bool) {
+		fields = append(fields, columnField{structField: structField{name: name, index: index}, presence: isPresence})
+	})
+
+	values := make([]reflect.Value, len(rows))
+	for i, row := range rows {
+		rv, err := structValue(row)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		values[i] = rv
+	}
+
+	var columns []string
+	var included []columnField
+	for _, f := range fields {
+		if !f.presence || o.includeColumn(values, f) {
+			columns = append(columns, f.name)
+			included = append(included, f)
+		}
+	}
+
+	out := make([][]any, len(values))
+	for i, rv := range values {
+		out[i] = make([]any, len(included))
+		for j, f := range included {
+			if f.presence && fieldOf(rv, f.structField).State() == StateUnset {
+				out[i][j] = o.padding
+			} else {
+				out[i][j] = rv.FieldByIndex(f.index).Interface()
+			}
+		}
+	}
+
+	return columns, out, nil
+}
+
+// columnField is a field of a struct type, presence or not.
+type columnField struct {
+	structField
+
+	presence bool
+}
+
+// includeColumn reports whether InsertColumnsValues includes the presence field f of rows.
+func (o *options) includeColumn(rows []reflect.Value, f columnField) bool {
+	for _, rv := range rows {
+		set := fieldOf(rv, f.structField).State() != StateUnset
+		if o.pad && set {
+			return true
+		}
+
+		if !o.pad && !set {
+			return false
+		}
+	}
+
+	return !o.pad
+}
+
+// Change describes a presence field which differs between two structs.
+// From and To are nil when the field is null or unset.
+type Change struct {
+	Field     string
+	From      any
+	To        any
+	FromState State
+	ToState   State
+}
+
+// Diff returns the presence fields whose state or value differ between the structs
+// before and after, which must be of the same type.
+// Values are compared with reflect.DeepEqual unless EqualFunc, WithFloatEpsilon or
+// WithTimeGranularity say otherwise.
+func Diff(before, after any, opts ...Option) ([]Change, error) {
+	return newOptions(opts).diff(before, after)
+}
+
+// DiffContext is Diff aborting with the error of ctx once it is done, for very large
+// structs walked under a request deadline.
+func DiffContext(ctx context.Context, before, after any, opts ...Option) ([]Change, error) {
+	return newContextOptions(ctx, opts).diff(before, after)
+}
+
+func (o *options) diff(before, after any) ([]Change, error) {
+	bv, err := structValue(before)
+	if err != nil {
+		return nil, err
+	}
+
+	av, err := structValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	if bv.Type() != av.Type() {
+		return nil, fmt.Errorf("presence cannot diff %s and %s", bv.Type(), av.Type())
+	}
+
+	var changes []Change
+	for _, f := range presenceFields(bv.Type(), o) {
+		if o.done() {
+			return nil, o.contextErr()
+		}
+
+		from, to := fieldOf(bv, f), fieldOf(av, f)
+		change := Change{
+			Field:     f.name,
+			From:      from.anyValue(),
+			To:        to.anyValue(),
+			FromState: from.State(),
+			ToState:   to.State(),
+		}
+
+		if change.FromState != change.ToState || !o.equalValues(change.From, change.To) {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// equalValues compares two values of the same type according to the options.
+func (o *options) equalValues(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if fn, ok := o.equal[reflect.TypeOf(a)]; ok {
+		return fn(a, b)
+	}
+
+	switch a := a.(type) {
+	case float64:
+		if o.floatEpsilon > 0 {
+			return math.Abs(a-b.(float64)) < o.floatEpsilon
+		}
+	case float32:
+		if o.floatEpsilon > 0 {
+			return math.Abs(float64(a)-float64(b.(float32))) < o.floatEpsilon
+		}
+	case time.Time:
+		if o.timeGranularity > 0 {
+			return a.Sub(b.(time.Time)).Abs() < o.timeGranularity
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// PatchStruct applies the set presence fields of patch to the same-named fields of
+// the struct pointed to by dst, leaving the other fields untouched.
+// Destination fields may be presence fields, pointers (nil on null) or plain fields,
+// which return ErrNullNotAllowed on null. Patch fields missing from dst are ignored.
+// The errors of the fields are returned together as FieldErrors, the other fields
+// being patched. With WithNested(NestedMaps) or WithNested(NestedFlatten), nested presence structs are
+// merged into the destination fields rather than replacing them.
+func PatchStruct(dst, patch any, opts ...Option) error {
+	return newOptions(opts).patchStruct(dst, patch)
+}
+
+// PatchStructContext is PatchStruct aborting with the error of ctx once it is done, for
+// very large structs walked under a request deadline. The fields patched before are
+// left patched.
+func PatchStructContext(ctx context.Context, dst, patch any, opts ...Option) error {
+	return newContextOptions(ctx, opts).patchStruct(dst, patch)
+}
+
+func (o *options) patchStruct(dst, patch any) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence patch destination must be a non-nil struct pointer, got %T", dst)
+	}
+
+	pv, err := structValue(patch)
+	if err != nil {
+		return err
+	}
+
+	return o.patch(dv.Elem(), pv)
+}
+
+// ApplyToPointers is PatchStruct for the models with pointer fields of the GraphQL and
+// API layers, e.g. gqlgen models: the fields are matched by their Go names, as the tags
+// of the inputs and of the models often differ.
+//
+//	err := presence.ApplyToPointers(user, input) // input.Email null: user.Email is nil
+func ApplyToPointers(dst, patch any) error {
+	return PatchStruct(dst, patch, WithTag(""))
+}
+
+// patch applies the set presence fields of the struct pv to the struct dv.
+func (o *options) patch(dv, pv reflect.Value) error {
+	targets := fieldIndexes(dv.Type(), o)
+	errs := FieldErrors{}
+
+	walkFields(pv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		target, ok := targets[name]
+		if !ok || o.done() {
+			return
+		}
+
+		err := o.patchField(dv.FieldByIndex(target), pv.FieldByIndex(index), isPresence)
+
+		// The errors of nested presence structs are flattened into dotted names, the names
+		// alone locating them so that the messages reach API consumers as is.
+		var nested FieldErrors
+		if errors.As(err, &nested) {
+			for nestedName, nestedErr := range nested {
+				errs[name+"."+nestedName] = nestedErr
+			}
+		} else if err != nil {
+			errs[name] = err
+		}
+	})
+
+	if err := o.contextErr(); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// fieldIndexes returns the indexes of the fields of the struct type typ by name, the
+// first field winning when names collide.
+func fieldIndexes(typ reflect.Type, o *options) map[string][]int {
+	indexes := map[string][]int{}
+	walkFields(typ, nil, o, func(name string, index []int, _ bool) {
+		if _, ok := indexes[name]; !ok {
+			indexes[name] = index
+		}
+	})
+
+	return indexes
+}
+
+// patchField applies the patch field src to the field dst: set presence fields and, when
+// merging them, nested presence structs.
+func (o *options) patchField(dst, src reflect.Value, isPresence bool) error {
+	merge := o.nested != NestedValue
+	if !isPresence {
+		if !merge {
+			return nil
+		}
+
+		if nested, ok := o.nestedStruct(src); ok {
+			return o.patchNested(dst, nested)
+		}
+
+		return nil
+	}
+
+	pf := src.Addr().Interface().(presenceField)
+	if pf.State() == StateUnset {
+		return nil
+	}
+
+	if merge {
+		if nested, ok := o.nestedStruct(reflect.ValueOf(pf.anyValue())); ok {
+			return o.patchNested(dst, nested)
+		}
+	}
+
+	return patchField(dst, pf)
+}
+
+// patchNested merges the nested presence struct src into the field dst.
+func (o *options) patchNested(dst, src reflect.Value) error {
+	if target, ok := dst.Addr().Interface().(presenceField); ok {
+		current := reflect.ValueOf(target.anyValue())
+		if current.Kind() != reflect.Struct {
+			return target.setAny(src.Interface())
+		}
+
+		merged := reflect.New(current.Type()).Elem()
+		merged.Set(current)
+
+		err := o.patch(merged, src)
+		if err != nil {
+			return err
+		}
+
+		return target.setAny(merged.Interface())
+	}
+
+	if dst.Kind() == reflect.Pointer && dst.Type().Elem().Kind() == reflect.Struct {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+
+		return o.patch(dst.Elem(), src)
+	}
+
+	if dst.Kind() == reflect.Struct {
+		return o.patch(dst, src)
+	}
+
+	return assign(dst, src.Interface())
+}
+
+// patchField applies the set presence value src to the field dst.
+func patchField(dst reflect.Value, src presenceField) error {
+	if target, ok := dst.Addr().Interface().(presenceField); ok {
+		return target.setAny(src.anyValue())
+	}
+
+	if src.State() == StateNull {
+		return nullField(dst)
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		ptr := reflect.New(dst.Type().Elem())
+		err := assign(ptr.Elem(), src.anyValue())
+		if err != nil {
+			return err
+		}
+
+		dst.Set(ptr)
+
+		return nil
+	}
+
+	return assign(dst, src.anyValue())
+}
+
+// Mask unsets the presence fields of the struct pointed to by v whose name is not in
+// allowed, e.g. to hide the fields a client may not see. Other fields are left intact,
+// and names matching no field are ignored.
+func Mask(v any, allowed []string, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence mask target must be a non-nil struct pointer, got %T", v)
+	}
+
+	rv = rv.Elem()
+	keep := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		keep[name] = true
+	}
+
+	for _, f := range presenceFields(rv.Type(), newOptions(opts)) {
+		if !keep[f.name] {
+			fieldOf(rv, f).Unset()
+		}
+	}
+
+	return nil
+}
+
+// Project returns a copy of the struct v keeping only the fields named in fields:
+// the other presence fields are unset and the other plain fields zeroed, so that one
+// struct can be shaped per client, e.g. from a ?fields=id,name query parameter:
+//
+//	resp, err := presence.Project(user, strings.Split(r.URL.Query().Get("fields"), ","))
+//
+// Names matching no field are ignored.
+func Project[T any](v T, fields []string, opts ...Option) (T, error) {
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Struct {
+		return v, fmt.Errorf("presence cannot project %s, which is not a struct", rv.Type())
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		keep[name] = true
+	}
+
+	walkFields(rv.Type(), nil, newOptions(opts), func(name string, index []int, isPresence bool) {
+		if keep[name] {
+			return
+		}
+
+		field := rv.FieldByIndex(index)
+		if isPresence {
+			field.Addr().Interface().(presenceField).Unset()
+		} else {
+			field.SetZero()
+		}
+	})
+
+	return v, nil
+}
+
+// Pick returns a copy of the struct v keeping only the fields named in fields, named by
+// their json tags, like Project does, to build minimal downstream requests from rich
+// inputs:
+//
+//	patch, err := presence.Pick(input, "email", "name") // the other fields are unset
+//
+// Unlike Project, which serves client-supplied lists, names matching no field return
+// an error, as they are typos.
+func Pick[T any](v T, fields ...string) (T, error) {
+	picked, err := Project(v, fields)
+	if err != nil {
+		return v, err
+	}
+
+	indexes := fieldIndexes(reflect.TypeFor[T](), newOptions(nil))
+	for _, name := range fields {
+		if _, ok := indexes[name]; !ok {
+			return v, fmt.Errorf("presence: no field %s in %s", name, reflect.TypeFor[T]())
+		}
+	}
+
+	return picked, nil
+}
+
+// Sanitize unsets the presence fields of the struct pointed to by v that role may not
+// see, so that one model serves several authorization levels. The roles allowed to see
+// a field are listed in its presence tag; fields without the tag are visible to all:
+//
+//	type User struct {
+//		Name  presence.Of[string] `json:"name"`
+//		Email presence.Of[string] `json:"email" presence:"roles=admin|support"`
+//	}
+//
+//	err := presence.Sanitize(&user, "guest") // user.Email becomes unset
+//
+// Plain fields the role may not see are zeroed.
+func Sanitize(v any, role string, opts ...Option) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("presence sanitize target must be a non-nil struct pointer, got %T", v)
+	}
+
+	rv = rv.Elem()
+	walkFields(rv.Type(), nil, newOptions(opts), func(_ string, index []int, isPresence bool) {
+		roles, ok := fieldRoles(rv.Type().FieldByIndex(index))
+		if !ok || slices.Contains(roles, role) {
+			return
+		}
+
+		field := rv.FieldByIndex(index)
+		if isPresence {
+			field.Addr().Interface().(presenceField).Unset()
+		} else {
+			field.SetZero()
+		}
+	})
+
+	return nil
+}
+
+// fieldRoles returns the roles listed in the roles option of the presence tag of f,
+// reporting false when the field has none.
+func fieldRoles(f reflect.StructField) ([]string, bool) {
+	for option := range strings.SplitSeq(f.Tag.Get("presence"), ",") {
+		if roles, ok := strings.CutPrefix(strings.TrimSpace(option), "roles="); ok {
+			return strings.Split(roles, "|"), true
+		}
+	}
+
+	return nil, false
+}
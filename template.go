@@ -0,0 +1,86 @@
+package presence
+
+import "fmt"
+
+// Templates can only call the pointer receiver methods of addressable values, which the
+// fields of a struct passed by value are not. IsPresent and Any have value receivers,
+// and the TemplateFuncs functions accept any presence value, nil pointers included.
+
+// IsPresent reports whether n holds a value, neither null nor unset.
+// It is IsValue with a value receiver, callable from templates: {{if .Name.IsPresent}}.
+func (n Of[T]) IsPresent() bool {
+	return n.isSet() && n.val != nil
+}
+
+// Any returns the value boxed in an any, nil when null or unset.
+// Unlike Value, it does not return an error nor encode the value for a database.
+func (n Of[T]) Any() any {
+	if !n.IsPresent() {
+		return nil
+	}
+
+	return *n.val
+}
+
+// TemplateFuncs returns the presence functions for text/template and html/template,
+// whose FuncMap types it converts to:
+//   - isValue, isNull, isUnset and isSet report the state of a presence value;
+//   - state returns it as a string ("unset", "null" or "value");
+//   - value returns the value, nil when null or unset;
+//   - valueOr returns the value, or its second argument when null or unset.
+//
+// They accept any presence value (Of[T], *Of[T] or a type embedding it), nil pointers
+// being unset, and report an error for other values:
+//
+//	{{if isNull .Email}}cleared{{else}}{{valueOr .Email "n/a"}}{{end}}
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"isValue": templateStateIs(StateValue),
+		"isNull":  templateStateIs(StateNull),
+		"isUnset": templateStateIs(StateUnset),
+		"isSet": func(v any) (bool, error) {
+			state, err := templateState(v)
+
+			return state != StateUnset, err
+		},
+		"state": func(v any) (string, error) {
+			state, err := templateState(v)
+
+			return state.String(), err
+		},
+		"value": func(v any) (any, error) {
+			return templateValueOr(v, nil)
+		},
+		"valueOr": templateValueOr,
+	}
+}
+
+func templateStateIs(want State) func(v any) (bool, error) {
+	return func(v any) (bool, error) {
+		state, err := templateState(v)
+
+		return state == want, err
+	}
+}
+
+func templateState(v any) (State, error) {
+	state, _, ok := presenceOf(v)
+	if !ok {
+		return StateUnset, fmt.Errorf("presence expected a presence value, got %T", v)
+	}
+
+	return state, nil
+}
+
+func templateValueOr(v, defaultValue any) (any, error) {
+	state, value, ok := presenceOf(v)
+	if !ok {
+		return nil, fmt.Errorf("presence expected a presence value, got %T", v)
+	}
+
+	if state != StateValue {
+		return defaultValue, nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type anonymizedUser struct {
+	ID       int                 `json:"id"`
+	Email    presence.Of[string] `json:"email"`
+	Name     presence.Of[string] `json:"name"`
+	Phone    presence.Of[string] `json:"phone"`
+	Login    string              `json:"login"`
+	Nickname *string             `json:"nickname"`
+}
+
+// Tests for Anonymize
+
+func TestAnonymize(t *testing.T) {
+	rules := map[string]func(any) any{
+		"email":    func(v any) any { return "user" + strings.Repeat("x", len(v.(string))) + "@example.com" },
+		"name":     func(any) any { return "Jane Doe" },
+		"phone":    func(any) any { return "000" },
+		"login":    func(v any) any { return strings.ToUpper(v.(string)) },
+		"nickname": func(any) any { return "nick" },
+	}
+
+	t.Run("rewrites values and keeps states", func(t *testing.T) {
+		u := anonymizedUser{
+			ID:    1,
+			Email: presence.FromValue("ada@b.c"),
+			Name:  presence.Null[string](),
+			Login: "ada",
+		}
+		require.NoError(t, presence.Anonymize(&u, rules))
+
+		assert.Equal(t, anonymizedUser{
+			ID:    1,
+			Email: presence.FromValue("userxxxxxxx@example.com"),
+			Name:  presence.Null[string](),
+			Login: "ADA",
+		}, u)
+	})
+
+	t.Run("plain pointers", func(t *testing.T) {
+		nickname := "ada"
+		u := anonymizedUser{Nickname: &nickname}
+		require.NoError(t, presence.Anonymize(&u, rules))
+		assert.Equal(t, "nick", *u.Nickname)
+	})
+
+	t.Run("nil rule result", func(t *testing.T) {
+		u := anonymizedUser{Email: presence.FromValue("ada@b.c"), Login: "ada"}
+		require.NoError(t, presence.Anonymize(&u, map[string]func(any) any{
+			"email": func(any) any { return nil },
+			"login": func(any) any { return nil },
+		}))
+		assert.Equal(t, anonymizedUser{Email: presence.Null[string]()}, u)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		u := anonymizedUser{Email: presence.FromValue("ada@b.c")}
+		require.ErrorContains(t, presence.Anonymize(&u, map[string]func(any) any{"mail": rules["email"]}), "mail")
+		require.Error(t, presence.Anonymize(&u, map[string]func(any) any{"email": func(any) any { return []int{42} }}))
+		require.Error(t, presence.Anonymize(u, rules))
+	})
+}
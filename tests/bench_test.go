@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wideColumns = 200
+
+// wideRow stands for a 200-column table row made of presence columns.
+type wideRow struct {
+	Ints    [wideColumns / 2]presence.Of[int64]
+	Strings [wideColumns / 2]presence.Of[string]
+}
+
+// dest returns the scan destinations of the row, as database/sql's Rows.Scan takes them.
+func (r *wideRow) dest() []any {
+	dest := make([]any, 0, wideColumns)
+	for i := range r.Ints {
+		dest = append(dest, &r.Ints[i])
+	}
+
+	for i := range r.Strings {
+		dest = append(dest, &r.Strings[i])
+	}
+
+	return dest
+}
+
+// wideSource returns driver values for a wide row, one column out of four being NULL.
+func wideSource() []any {
+	src := make([]any, wideColumns)
+	for i := range src {
+		switch {
+		case i%4 == 0:
+			src[i] = nil
+		case i < wideColumns/2:
+			src[i] = int64(i)
+		default:
+			src[i] = "value"
+		}
+	}
+
+	return src
+}
+
+func TestOfMemoryFootprint(t *testing.T) {
+	// A pointer and the packed state: per-value overrides do not grow the struct.
+	assert.Equal(t, 2*unsafe.Sizeof(uintptr(0)), unsafe.Sizeof(presence.Of[int64]{}))
+	assert.Equal(t, wideColumns*unsafe.Sizeof(presence.Of[int64]{}), unsafe.Sizeof(wideRow{}))
+
+	t.Run("overrides are independent", func(t *testing.T) {
+		var n presence.Of[int]
+		n.SetMarshalUnset(presence.UnsetNull)
+		n.SetScanNull(presence.ScanNullAsUnset)
+		n.SetValueUnset(presence.ValueUnsetError)
+		n.SetValue(1)
+		n.SetMarshalUnset(presence.UnsetSkip)
+
+		assert.Equal(t, presence.UnsetSkip, n.GetMarshalUnset())
+		assert.Equal(t, presence.ScanNullAsUnset, n.GetScanNull())
+		assert.Equal(t, presence.ValueUnsetError, n.GetValueUnset())
+		assert.True(t, n.IsValue())
+
+		n.Unset()
+		assert.True(t, n.IsUnset())
+		assert.Equal(t, presence.ScanNullAsUnset, n.GetScanNull())
+	})
+}
+
+// scanRow scans src into dest like database/sql's Rows.Scan does.
+func scanRow(dest, src []any) error {
+	for i, v := range src {
+		err := dest[i].(sql.Scanner).Scan(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestWideRowScan(t *testing.T) {
+	var row wideRow
+	require.NoError(t, scanRow(row.dest(), wideSource()))
+
+	assert.True(t, row.Ints[0].IsNull())
+	assert.Equal(t, int64(1), row.Ints[1].MustGet())
+	assert.Equal(t, "value", row.Strings[1].MustGet())
+}
+
+func BenchmarkWideRowScan(b *testing.B) {
+	src := wideSource()
+	rows := make([]wideRow, 1024)
+	dests := make([][]any, len(rows))
+	for i := range rows {
+		dests[i] = rows[i].dest()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range b.N {
+		err := scanRow(dests[i%len(dests)], src)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalUnsetParallel(b *testing.B) {
+	type payload struct {
+		A presence.Of[string] `json:"a,omitzero"`
+		B presence.Of[int]    `json:"b,omitzero"`
+	}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, err := json.Marshal(payload{})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// largeDocument returns a JSONB-like payload of about 100 KB with characters drivers escape.
+func largeDocument() map[string]any {
+	doc := map[string]any{}
+	for i := range 1000 {
+		doc[fmt.Sprintf("key_%d", i)] = map[string]any{
+			"text":   "quoted \"value\" with <html> & unicode é",
+			"values": []int{i, i * 2, i * 3},
+		}
+	}
+
+	return doc
+}
+
+func BenchmarkValueLargeJSON(b *testing.B) {
+	n := presence.FromValue(largeDocument())
+
+	for _, bc := range []struct {
+		name     string
+		behavior presence.JSONValueBehavior
+	}{
+		{"string", presence.JSONValueString},
+		{"bytes", presence.JSONValueBytes},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			presence.SetDefaultJSONValue(bc.behavior)
+			defer presence.SetDefaultJSONValue(presence.JSONValueString)
+
+			b.ReportAllocs()
+			for range b.N {
+				_, err := n.Value()
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecoderScan(b *testing.B) {
+	values := make([]presence.Of[string], 1024)
+
+	b.Run("plain", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := range b.N {
+			err := values[i%len(values)].Scan("value")
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("decoder", func(b *testing.B) {
+		dec := presence.NewDecoder[string](len(values))
+
+		b.ReportAllocs()
+		for i := range b.N {
+			if i%len(values) == 0 {
+				dec.Reset()
+			}
+
+			err := dec.Scan(&values[i%len(values)], "value")
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeLines(b *testing.B) {
+	input := []byte(strings.Repeat("{\"id\":1,\"name\":\"ada\",\"score\":null}\n", 1024))
+
+	b.ReportAllocs()
+	for range b.N {
+		err := presence.DecodeLines(bytes.NewReader(input), func(*lineEvent) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"math/big"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// huge is above the int64 and float64 exact ranges.
+const huge = "123456789012345678901234567890"
+
+func hugeInt(t *testing.T) big.Int {
+	t.Helper()
+
+	var i big.Int
+	_, ok := i.SetString(huge, 10)
+	require.True(t, ok)
+
+	return i
+}
+
+// Tests for Of[big.Int]
+
+func TestBigIntScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want string
+	}{
+		{"numeric text", huge, huge},
+		{"numeric bytes", []byte("-" + huge), "-" + huge},
+		{"int64", int64(42), "42"},
+		{"integral float64", float64(1e20), "100000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n presence.Of[big.Int]
+			require.NoError(t, n.Scan(tt.src))
+
+			got := n.MustGet()
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		var n presence.Of[big.Int]
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, src := range []any{"1.5", "abc", 1.5, true} {
+			var n presence.Of[big.Int]
+			require.Error(t, n.Scan(src), src)
+			assert.True(t, n.IsUnset())
+		}
+	})
+
+	t.Run("through a Decoder", func(t *testing.T) {
+		dec := presence.NewDecoder[big.Int](0)
+		var n presence.Of[big.Int]
+		require.NoError(t, dec.Scan(&n, huge))
+
+		got := n.MustGet()
+		assert.Equal(t, huge, got.String())
+	})
+}
+
+func TestBigIntValue(t *testing.T) {
+	v, err := presence.FromValue(hugeInt(t)).Value()
+	require.NoError(t, err)
+	assert.Equal(t, huge, v)
+
+	v, err = presence.Null[big.Int]().Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestBigIntJSON(t *testing.T) {
+	type account struct {
+		Balance presence.Of[big.Int] `json:"balance,omitzero"`
+	}
+
+	t.Run("encodes as a string", func(t *testing.T) {
+		b, err := json.Marshal(account{Balance: presence.FromValue(hugeInt(t))})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"balance":"`+huge+`"}`, string(b))
+
+		b, err = json.Marshal(account{})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(b))
+	})
+
+	t.Run("decodes strings and numbers", func(t *testing.T) {
+		for _, in := range []string{`{"balance":"` + huge + `"}`, `{"balance":` + huge + `}`} {
+			var a account
+			require.NoError(t, json.Unmarshal([]byte(in), &a))
+
+			got := a.Balance.MustGet()
+			assert.Equal(t, huge, got.String())
+		}
+
+		var a account
+		require.NoError(t, json.Unmarshal([]byte(`{"balance":null}`), &a))
+		assert.True(t, a.Balance.IsNull())
+
+		require.Error(t, json.Unmarshal([]byte(`{"balance":"1.5"}`), &a))
+	})
+
+	t.Run("hash and debug rendering", func(t *testing.T) {
+		h1, h2 := fnv.New64a(), fnv.New64a()
+		require.NoError(t, presence.Hash(presence.FromValue(hugeInt(t)), h1))
+		require.NoError(t, presence.Hash(presence.FromValue(*big.NewInt(1)), h2))
+		assert.NotEqual(t, h1.Sum64(), h2.Sum64())
+
+		assert.Equal(t, huge, presence.DebugString(presence.FromValue(hugeInt(t))))
+	})
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Build
+
+func TestBuild(t *testing.T) {
+	t.Run("presence fields by Go name", func(t *testing.T) {
+		patch, err := presence.Build[userPatch]().Set("Name", "Ada").Null("Age").Set("Email", "a@b.c").Struct()
+		require.NoError(t, err)
+		assert.Equal(t, "Ada", patch.Name.MustGet())
+		assert.True(t, patch.Age.IsNull())
+		assert.Equal(t, "a@b.c", patch.Email.MustGet())
+		assert.True(t, patch.UpdatedBy.IsUnset())
+	})
+
+	t.Run("tag names and embedded fields", func(t *testing.T) {
+		patch := presence.Build[userPatch](presence.WithTag("db")).
+			Set("full_name", "Ada").
+			Set("updated_by", "admin").
+			MustStruct()
+		assert.Equal(t, "Ada", patch.Name.MustGet())
+		assert.Equal(t, "admin", patch.UpdatedBy.MustGet())
+	})
+
+	t.Run("conversions, nil and unset", func(t *testing.T) {
+		patch := presence.Build[userPatch]().Set("Age", int64(36)).Set("Name", nil).Set("Plain", "p").MustStruct()
+		assert.Equal(t, 36, patch.Age.MustGet())
+		assert.True(t, patch.Name.IsNull())
+		assert.Equal(t, "p", patch.Plain)
+
+		patch = presence.Build[userPatch]().Set("Age", 1).Unset("Age").MustStruct()
+		assert.True(t, patch.Age.IsUnset())
+	})
+
+	t.Run("pointer fields", func(t *testing.T) {
+		entity := presence.Build[userEntity]().Set("Email", "a@b.c").MustStruct()
+		require.NotNil(t, entity.Email)
+		assert.Equal(t, "a@b.c", *entity.Email)
+
+		entity = presence.Build[userEntity]().Set("Email", "a@b.c").Null("Email").MustStruct()
+		assert.Nil(t, entity.Email)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := presence.Build[userPatch]().Set("Missing", 1).Struct()
+		require.ErrorContains(t, err, "unknown field Missing")
+
+		_, err = presence.Build[userPatch]().Set("Age", "old").Set("Name", "Ada").Struct()
+		require.ErrorContains(t, err, "Age")
+
+		_, err = presence.Build[userPatch]().Null("Plain").Struct()
+		require.ErrorIs(t, err, presence.ErrNullNotAllowed)
+
+		_, err = presence.Build[userPatch]().Unset("Plain").Struct()
+		require.Error(t, err)
+
+		_, err = presence.Build[int]().Struct()
+		require.Error(t, err)
+
+		assert.Panics(t, func() { presence.Build[userPatch]().Null("Plain").MustStruct() })
+	})
+}
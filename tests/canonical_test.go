@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for MarshalCanonical and CanonicalizeJSON
+
+func TestMarshalCanonical(t *testing.T) {
+	type payload struct {
+		Zeta  presence.Of[string]          `json:"zeta"`
+		Alpha presence.Of[any]             `json:"alpha"`
+		Meta  presence.Of[json.RawMessage] `json:"meta,omitzero"`
+		Note  presence.Of[string]          `json:"note,omitzero"`
+	}
+
+	t.Run("sorted keys and no HTML escaping", func(t *testing.T) {
+		p := payload{
+			Zeta:  presence.FromValue("<a & b>"),
+			Alpha: presence.FromValue[any](map[string]any{"b": 1, "a": []any{2.50, "x"}}),
+			Meta:  presence.FromValue(json.RawMessage(`{ "y": 1, "x": {"d": null, "c": true} }`)),
+		}
+
+		got, err := presence.MarshalCanonical(p)
+		require.NoError(t, err)
+		assert.Equal(t, `{"alpha":{"a":[2.5,"x"],"b":1},"meta":{"x":{"c":true,"d":null},"y":1},"zeta":"<a & b>"}`,
+			string(got))
+	})
+
+	t.Run("equal payloads encode alike", func(t *testing.T) {
+		a, err := presence.MarshalCanonical(presence.FromValue[any](map[string]any{"x": 1.50, "y": "é"}))
+		require.NoError(t, err)
+
+		b, err := presence.CanonicalizeJSON([]byte("{\"y\":\"\\u00e9\",\n \"x\": 15e-1}"))
+		require.NoError(t, err)
+		assert.Equal(t, string(a), string(b))
+	})
+
+	t.Run("null and unset", func(t *testing.T) {
+		got, err := presence.MarshalCanonical(payload{Zeta: presence.Null[string]()})
+		require.NoError(t, err)
+		assert.Equal(t, `{"alpha":null,"zeta":null}`, string(got))
+	})
+}
+
+func TestCanonicalizeJSON(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"integers are kept", `[1, -0, 9007199254740993, 100]`, `[1,0,9007199254740993,100]`},
+		{"floats", `[1.0, 0.1, 1e2, 1E21, 1e-7, -0.0, 123456789.125, 0.000001]`,
+			`[1,0.1,100,1e+21,1e-7,0,123456789.125,0.000001]`},
+		{"string escapes", `"\u0041\/\u2028\u001f\t\"\\"`, "\"A/\u2028\\u001f\\t\\\"\\\\\""},
+		{"keys in UTF-16 order", "{\"\U0001F600\":1,\"\uFFFD\":2,\"a\":3}", "{\"a\":3,\"\U0001F600\":1,\"\uFFFD\":2}"},
+		{"scalars", ` true `, `true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := presence.CanonicalizeJSON([]byte(tt.in))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("invalid documents", func(t *testing.T) {
+		for _, in := range []string{`{`, `1 2`, `1e400`, ``} {
+			_, err := presence.CanonicalizeJSON([]byte(in))
+			require.Error(t, err, in)
+		}
+	})
+}
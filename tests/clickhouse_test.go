@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/pivaldi/presence"
+	presenceclickhouse "github.com/pivaldi/presence/contrib/clickhouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchColumn is the driver.BatchColumn of a batch, over a bare column.
+type batchColumn struct {
+	column.Interface
+}
+
+func (c batchColumn) Append(v any) error {
+	_, err := c.Interface.Append(v)
+
+	return err
+}
+
+func newClickHouseColumn(t *testing.T, typ string) column.Interface {
+	t.Helper()
+
+	col, err := column.Type(typ).Column("c", &column.ServerContext{})
+	require.NoError(t, err)
+
+	return col
+}
+
+// scanClickHouseColumn scans the rows of col into presence values.
+func scanClickHouseColumn[T any](t *testing.T, col column.Interface) []presence.Of[T] {
+	t.Helper()
+
+	out := make([]presence.Of[T], col.Rows())
+	for i := range out {
+		require.NoError(t, col.ScanRow(&out[i], i))
+	}
+
+	return out
+}
+
+// roundTripClickHouse appends the value, null and unset states of v to a column of
+// type typ in row mode and scans them back.
+func roundTripClickHouse[T any](t *testing.T, typ string, v T) {
+	t.Helper()
+
+	col := newClickHouseColumn(t, typ)
+	for _, n := range []presence.Of[T]{presence.FromValue(v), presence.Null[T](), {}} {
+		require.NoError(t, col.AppendRow(presenceclickhouse.Bind(n)), typ)
+	}
+
+	got := scanClickHouseColumn[T](t, col)
+	assert.Equal(t, v, got[0].MustGet(), typ)
+	assert.True(t, got[1].IsNull(), typ)
+	assert.True(t, got[2].IsNull(), "unset values are written as NULL")
+}
+
+// Tests for the clickhouse-go binding
+
+func TestClickHouseBind(t *testing.T) {
+	t.Run("row mode round-trips the Nullable types", func(t *testing.T) {
+		roundTripClickHouse(t, "Nullable(String)", "ada")
+		roundTripClickHouse(t, "Nullable(Int32)", int32(-3))
+		roundTripClickHouse(t, "Nullable(Int64)", int64(1)<<60)
+		roundTripClickHouse(t, "Nullable(UInt8)", uint8(200))
+		roundTripClickHouse(t, "Nullable(UInt64)", uint64(1)<<63)
+		roundTripClickHouse(t, "Nullable(Float32)", float32(1.5))
+		roundTripClickHouse(t, "Nullable(Float64)", 2.25)
+		roundTripClickHouse(t, "Nullable(Bool)", true)
+		roundTripClickHouse(t, "Nullable(DateTime('UTC'))", time.Unix(1700000000, 0).UTC())
+	})
+
+	t.Run("the driver.Valuer path rejects unsigned values", func(t *testing.T) {
+		col := newClickHouseColumn(t, "Nullable(UInt64)")
+		require.Error(t, col.AppendRow(presence.FromValue(uint64(7))))
+		require.NoError(t, col.AppendRow(presenceclickhouse.Bind(presence.FromValue(uint64(7)))))
+	})
+}
+
+func TestClickHouseAppendColumn(t *testing.T) {
+	t.Run("column mode", func(t *testing.T) {
+		col := newClickHouseColumn(t, "Nullable(UInt64)")
+		hits := []presence.Of[uint64]{presence.FromValue(uint64(3)), presence.Null[uint64](), {}}
+		require.NoError(t, presenceclickhouse.AppendColumn(batchColumn{col}, hits))
+
+		got := scanClickHouseColumn[uint64](t, col)
+		require.Len(t, got, 3)
+		assert.Equal(t, uint64(3), got[0].MustGet())
+		assert.True(t, got[1].IsNull())
+		assert.True(t, got[2].IsNull())
+	})
+
+	t.Run("the Go type must be the column one", func(t *testing.T) {
+		col := newClickHouseColumn(t, "Nullable(Int32)")
+		err := presenceclickhouse.AppendColumn(batchColumn{col}, []presence.Of[int]{presence.FromValue(1)})
+		require.ErrorContains(t, err, "presence clickhouse appending")
+	})
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type compatRow struct {
+	Name presence.CompatNull[string] `json:"name,omitzero"`
+	Age  presence.CompatNull[int64]  `json:"age,omitzero"`
+}
+
+// nullConvention reads a field the way the libraries relying on sql.Null do.
+func nullConvention(v reflect.Value) (any, bool) {
+	if !v.FieldByName("Valid").Bool() {
+		return nil, false
+	}
+
+	return v.FieldByName("V").Interface(), true
+}
+
+// Tests for CompatNull
+
+func TestCompatNull(t *testing.T) {
+	t.Run("exposes the sql.Null fields", func(t *testing.T) {
+		row := compatRow{Name: presence.CompatNullOf(presence.FromValue("Ada"))}
+
+		v, valid := nullConvention(reflect.ValueOf(row.Name))
+		assert.True(t, valid)
+		assert.Equal(t, "Ada", v)
+
+		_, valid = nullConvention(reflect.ValueOf(row.Age))
+		assert.False(t, valid)
+
+		// A library setting the fields sets a value.
+		reflect.ValueOf(&row.Age).Elem().FieldByName("V").SetInt(36)
+		reflect.ValueOf(&row.Age).Elem().FieldByName("Valid").SetBool(true)
+		assert.Equal(t, presence.FromValue(int64(36)), row.Age.Of())
+	})
+
+	t.Run("keeps the three states", func(t *testing.T) {
+		var c presence.CompatNull[int64]
+		assert.Equal(t, presence.StateUnset, c.State())
+
+		c.SetNull()
+		assert.Equal(t, presence.StateNull, c.State())
+		assert.Equal(t, presence.Null[int64](), c.Of())
+
+		c.SetValue(1)
+		assert.Equal(t, presence.StateValue, c.State())
+
+		c.Unset()
+		assert.True(t, c.IsZero())
+	})
+
+	t.Run("scans and stores like Of", func(t *testing.T) {
+		var c presence.CompatNull[int64]
+		require.NoError(t, c.Scan(int64(42)))
+		assert.Equal(t, presence.CompatNull[int64]{V: 42, Valid: true}, c)
+
+		require.NoError(t, c.Scan(nil))
+		assert.Equal(t, presence.StateNull, c.State())
+
+		v, err := presence.CompatNullOf(presence.FromValue(int64(7))).Value()
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), v)
+
+		v, err = c.Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("encodes in JSON like Of", func(t *testing.T) {
+		b, err := json.Marshal(compatRow{Name: presence.CompatNullOf(presence.Null[string]())})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":null}`, string(b))
+
+		var row compatRow
+		require.NoError(t, json.Unmarshal([]byte(`{"name":"Ada","age":null}`), &row))
+		assert.Equal(t, "Ada", row.Name.V)
+		assert.Equal(t, presence.StateNull, row.Age.State())
+	})
+
+	t.Run("works with the struct helpers", func(t *testing.T) {
+		row := compatRow{Name: presence.CompatNullOf(presence.FromValue("Ada"))}
+		row.Age.SetNull()
+
+		m, err := presence.ToMap(row)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Ada", "age": nil}, m)
+
+		var dst compatRow
+		require.NoError(t, presence.PatchStruct(&dst, row))
+		assert.Equal(t, row, dst)
+	})
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type compositeAddress struct {
+	Number int
+	Street string
+	Zip    presence.Of[string]
+	Owner  presence.Of[uuid.UUID]
+	Since  time.Time
+	Rural  bool
+	note   string
+}
+
+// Tests for RegisterComposite
+
+func TestComposite(t *testing.T) {
+	presence.RegisterComposite[compositeAddress]()
+	t.Cleanup(presence.UnregisterComposite[compositeAddress])
+
+	owner := uuid.MustParse("5b1e4a4c-0a43-4c8f-9b5e-3f1f6d1c2a10")
+	since := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	addr := compositeAddress{
+		Number: 12,
+		Street: `Main St, "old" \ town`,
+		Zip:    presence.Null[string](),
+		Owner:  presence.FromValue(owner),
+		Since:  since,
+	}
+
+	t.Run("value", func(t *testing.T) {
+		v, err := presence.FromValue(addr).Value()
+		require.NoError(t, err)
+		assert.Equal(t,
+			`(12,"Main St, \"old\" \\ town",,`+owner.String()+`,"2024-03-01 12:30:00Z",f)`, v)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		v, err := presence.FromValue(addr).Value()
+		require.NoError(t, err)
+
+		var n presence.Of[compositeAddress]
+		require.NoError(t, n.Scan(v))
+		assert.Equal(t, addr, n.MustGet())
+	})
+
+	t.Run("postgres output", func(t *testing.T) {
+		var n presence.Of[compositeAddress]
+		require.NoError(t, n.Scan([]byte(`(7,"Elm ""Side"" Rd",75001,,"2024-03-01 12:30:00+00",t)`)))
+
+		got := n.MustGet()
+		assert.True(t, since.Equal(got.Since))
+
+		got.Since = since
+		assert.Equal(t, compositeAddress{
+			Number: 7,
+			Street: `Elm "Side" Rd`,
+			Zip:    presence.FromValue("75001"),
+			Owner:  presence.Null[uuid.UUID](),
+			Since:  since,
+			Rural:  true,
+		}, got)
+	})
+
+	t.Run("empty strings are not null", func(t *testing.T) {
+		var n presence.Of[compositeAddress]
+		require.NoError(t, n.Scan(`(1,"","",,,f)`))
+		assert.Equal(t, presence.FromValue(""), n.MustGet().Zip)
+		assert.True(t, n.MustGet().Since.IsZero())
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var n presence.Of[compositeAddress]
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+
+		v, err := presence.Null[compositeAddress]().Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("invalid literals", func(t *testing.T) {
+		for _, src := range []any{`12,Main`, `(1,2)`, `(x,a,,,,f)`, `(1,"a,,,,f)`, 42} {
+			var n presence.Of[compositeAddress]
+			require.Error(t, n.Scan(src), src)
+			assert.True(t, n.IsUnset())
+		}
+	})
+
+	t.Run("unregistered types are JSON", func(t *testing.T) {
+		presence.UnregisterComposite[compositeAddress]()
+		t.Cleanup(presence.RegisterComposite[compositeAddress])
+
+		v, err := presence.FromValue(compositeAddress{Number: 1}).Value()
+		require.NoError(t, err)
+		assert.Contains(t, v, `"Number":1`)
+	})
+}
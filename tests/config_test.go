@@ -1,10 +1,18 @@
 package tests
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pivaldi/presence"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMarshalUnsetBehavior(t *testing.T) {
@@ -15,6 +23,38 @@ func TestMarshalUnsetBehavior(t *testing.T) {
 	t.Run("UnsetNull is alternative", func(t *testing.T) {
 		assert.Equal(t, presence.MarshalUnsetBehavior(1), presence.UnsetNull)
 	})
+
+	t.Run("UnsetError fails on unset", func(t *testing.T) {
+		type order struct {
+			ID   presence.Of[int]    `json:"id,omitzero"`
+			Note presence.Of[string] `json:"note,omitzero"`
+		}
+
+		o := order{Note: presence.FromValue("gift")}
+		o.ID.SetMarshalUnset(presence.UnsetError)
+		_, err := json.Marshal(o)
+		require.ErrorIs(t, err, presence.ErrUnsetMarshal)
+
+		o.ID.SetValue(1)
+		b, err := json.Marshal(o)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"note":"gift"}`, string(b))
+
+		o.ID.SetNull()
+		b, err = json.Marshal(o)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":null,"note":"gift"}`, string(b))
+	})
+
+	t.Run("UnsetError as package default", func(t *testing.T) {
+		presence.SetDefaultMarshalUnset(presence.UnsetError)
+		defer presence.SetDefaultMarshalUnset(presence.UnsetSkip)
+
+		var n presence.Of[string]
+		assert.False(t, n.IsZero())
+		_, err := n.MarshalJSON()
+		require.ErrorIs(t, err, presence.ErrUnsetMarshal)
+	})
 }
 
 func TestScanNullBehaviorConstants(t *testing.T) {
@@ -60,3 +100,689 @@ func TestPerValueConfiguration(t *testing.T) {
 		assert.Equal(t, presence.GetDefaultScanNull(), n.GetScanNull())
 	})
 }
+
+func TestValueUnsetBehavior(t *testing.T) {
+	t.Run("default stores unset as NULL", func(t *testing.T) {
+		assert.Equal(t, presence.ValueUnsetNull, presence.GetDefaultValueUnset())
+
+		var n presence.Of[string]
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("ValueUnsetDefault returns the Default sentinel", func(t *testing.T) {
+		var n presence.Of[string]
+		n.SetValueUnset(presence.ValueUnsetDefault)
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Equal(t, presence.Default, v)
+	})
+
+	t.Run("ValueUnsetError fails on unset", func(t *testing.T) {
+		var n presence.Of[int]
+		n.SetValueUnset(presence.ValueUnsetError)
+		_, err := n.Value()
+		assert.ErrorIs(t, err, presence.ErrUnsetValue)
+	})
+
+	t.Run("null is not affected", func(t *testing.T) {
+		n := presence.Null[int]()
+		n.SetValueUnset(presence.ValueUnsetError)
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("package default applies to all values", func(t *testing.T) {
+		presence.SetDefaultValueUnset(presence.ValueUnsetError)
+		defer presence.SetDefaultValueUnset(presence.ValueUnsetNull)
+
+		var n presence.Of[string]
+		_, err := n.Value()
+		assert.ErrorIs(t, err, presence.ErrUnsetValue)
+	})
+}
+
+func TestTimeNormalization(t *testing.T) {
+	paris := time.FixedZone("CET", 60*60)
+	precise := time.Date(2024, 3, 1, 10, 20, 30, 123456789, paris)
+
+	t.Run("no normalization by default", func(t *testing.T) {
+		n := presence.FromValue(precise)
+		assert.Equal(t, precise, n.MustGet())
+	})
+
+	t.Run("truncate and UTC on SetValue", func(t *testing.T) {
+		presence.SetDefaultTimeNormalization(presence.TimeNormalization{Truncate: time.Millisecond, UTC: true})
+		defer presence.SetDefaultTimeNormalization(presence.TimeNormalization{})
+
+		n := presence.FromValue(precise)
+		assert.Equal(t, time.Date(2024, 3, 1, 9, 20, 30, 123000000, time.UTC), n.MustGet())
+	})
+
+	t.Run("Scan is normalized", func(t *testing.T) {
+		presence.SetDefaultTimeNormalization(presence.TimeNormalization{Truncate: time.Second})
+		defer presence.SetDefaultTimeNormalization(presence.TimeNormalization{})
+
+		var n presence.Of[time.Time]
+		require.NoError(t, n.Scan(precise))
+		assert.Equal(t, time.Date(2024, 3, 1, 10, 20, 30, 0, paris), n.MustGet())
+	})
+
+	t.Run("UnmarshalJSON is normalized", func(t *testing.T) {
+		presence.SetDefaultTimeNormalization(presence.TimeNormalization{Truncate: time.Second, UTC: true})
+		defer presence.SetDefaultTimeNormalization(presence.TimeNormalization{})
+
+		var n presence.Of[time.Time]
+		require.NoError(t, n.UnmarshalJSON([]byte(`"2024-03-01T10:20:30.123+01:00"`)))
+		assert.Equal(t, time.Date(2024, 3, 1, 9, 20, 30, 0, time.UTC), n.MustGet())
+	})
+
+	t.Run("round trip equality with a microsecond database", func(t *testing.T) {
+		presence.SetDefaultTimeNormalization(presence.TimeNormalization{Truncate: time.Microsecond, UTC: true})
+		defer presence.SetDefaultTimeNormalization(presence.TimeNormalization{})
+
+		written := presence.FromValue(precise)
+		var read presence.Of[time.Time]
+		require.NoError(t, read.Scan(precise.Truncate(time.Microsecond)))
+		assert.Equal(t, written.MustGet(), read.MustGet())
+	})
+
+	t.Run("other types are untouched", func(t *testing.T) {
+		presence.SetDefaultTimeNormalization(presence.TimeNormalization{Truncate: time.Hour})
+		defer presence.SetDefaultTimeNormalization(presence.TimeNormalization{})
+
+		d := presence.FromValue(time.Duration(90))
+		assert.Equal(t, time.Duration(90), d.MustGet())
+	})
+}
+
+func TestDefaultsConcurrentAccess(t *testing.T) {
+	defer presence.SetDefaultMarshalUnset(presence.UnsetSkip)
+	defer presence.SetDefaultScanNull(presence.ScanNullAsNull)
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			presence.SetDefaultMarshalUnset(presence.UnsetNull)
+			presence.SetDefaultScanNull(presence.ScanNullAsUnset)
+		}()
+
+		go func() {
+			defer wg.Done()
+			var n presence.Of[int]
+			_ = n.IsZero()
+			_ = n.GetScanNull()
+		}()
+	}
+
+	wg.Wait()
+
+	// Concurrent setters of different defaults do not overwrite each other.
+	assert.Equal(t, presence.UnsetNull, presence.GetDefaultMarshalUnset())
+	assert.Equal(t, presence.ScanNullAsUnset, presence.GetDefaultScanNull())
+}
+
+func TestJSONValueBehavior(t *testing.T) {
+	type doc struct {
+		Name string `json:"name"`
+	}
+
+	n := presence.FromValue(doc{Name: "a"})
+
+	t.Run("string by default", func(t *testing.T) {
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"a"}`, v)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		presence.SetDefaultJSONValue(presence.JSONValueBytes)
+		defer presence.SetDefaultJSONValue(presence.JSONValueString)
+
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Equal(t, []byte(`{"name":"a"}`), v)
+
+		var back presence.Of[doc]
+		require.NoError(t, back.Scan(v))
+		assert.Equal(t, "a", back.MustGet().Name)
+	})
+
+	t.Run("primitives are not affected", func(t *testing.T) {
+		presence.SetDefaultJSONValue(presence.JSONValueBytes)
+		defer presence.SetDefaultJSONValue(presence.JSONValueString)
+
+		v, err := presence.FromValue("text").Value()
+		require.NoError(t, err)
+		assert.Equal(t, "text", v)
+	})
+
+	t.Run("driver detection", func(t *testing.T) {
+		db, err := sql.Open("pgx", "postgres://localhost/none")
+		require.NoError(t, err)
+		defer db.Close()
+
+		assert.Equal(t, presence.JSONValueBytes, presence.DetectJSONValue(db))
+		assert.Equal(t, presence.JSONValueString, presence.DetectJSONValue(nil))
+	})
+}
+
+func TestDefaultMarshalNull(t *testing.T) {
+	type payload struct {
+		Tags     presence.Of[[]string]         `json:"tags"`
+		Extra    presence.Of[map[string]any]   `json:"extra"`
+		Data     presence.Of[any]              `json:"data"`
+		Point    presence.Of[*struct{ X int }] `json:"point"`
+		Grid     presence.Of[[2]int]           `json:"grid"`
+		At       presence.Of[time.Time]        `json:"at"`
+		Raw      presence.Of[[]byte]           `json:"raw"`
+		Name     presence.Of[string]           `json:"name"`
+		Comments presence.Of[[]string]         `json:"comments"`
+	}
+
+	p := payload{
+		Tags:  presence.Null[[]string](),
+		Extra: presence.Null[map[string]any](),
+		Data:  presence.Null[any](),
+		Point: presence.Null[*struct{ X int }](),
+		Grid:  presence.Null[[2]int](),
+		At:    presence.Null[time.Time](),
+		Raw:   presence.Null[[]byte](),
+		Name:  presence.Null[string](),
+	}
+
+	t.Run("default", func(t *testing.T) {
+		b, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"tags":null,"extra":null,"data":null,"point":null,"grid":null,"at":null,
+			"raw":null,"name":null,"comments":null}`, string(b))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		presence.SetDefaultMarshalNull(presence.MarshalNullAsEmpty)
+		defer presence.SetDefaultMarshalNull(presence.MarshalNullAsNull)
+
+		b, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"tags":[],"extra":{},"data":{},"point":{},"grid":[],"at":null,
+			"raw":null,"name":null,"comments":null}`, string(b), "unset values are still null")
+	})
+
+	t.Run("values are not affected", func(t *testing.T) {
+		presence.SetDefaultMarshalNull(presence.MarshalNullAsEmpty)
+		defer presence.SetDefaultMarshalNull(presence.MarshalNullAsNull)
+
+		b, err := json.Marshal(presence.FromValue([]string{"go"}))
+		require.NoError(t, err)
+		assert.JSONEq(t, `["go"]`, string(b))
+	})
+}
+
+func TestDefaultJSONLimits(t *testing.T) {
+	presence.SetDefaultJSONLimits(presence.JSONLimits{MaxDepth: 3, MaxSize: 64})
+	defer presence.SetDefaultJSONLimits(presence.JSONLimits{})
+
+	t.Run("within limits", func(t *testing.T) {
+		var n presence.Of[any]
+		require.NoError(t, json.Unmarshal([]byte(`{"a":[{"b":"[[[{{{"}]}`), &n))
+		assert.True(t, n.IsSet())
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		var n presence.Of[any]
+		err := json.Unmarshal([]byte(`{"a":[{"b":[1]}]}`), &n)
+		require.ErrorIs(t, err, presence.ErrLimitsExceeded)
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		var n presence.Of[map[string]string]
+		err := n.UnmarshalJSON([]byte(`{"a":"` + strings.Repeat("x", 64) + `"}`))
+		require.ErrorIs(t, err, presence.ErrLimitsExceeded)
+	})
+
+	t.Run("escaped quotes", func(t *testing.T) {
+		var n presence.Of[[]string]
+		require.NoError(t, n.UnmarshalJSON([]byte(`["\\\"[[[[", "\\"]`)))
+		assert.Equal(t, []string{`\"[[[[`, `\`}, n.MustGet())
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var n presence.Of[any]
+		require.NoError(t, n.UnmarshalJSON([]byte("null")))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		presence.SetDefaultJSONLimits(presence.JSONLimits{})
+
+		var n presence.Of[any]
+		require.NoError(t, n.UnmarshalJSON([]byte(strings.Repeat("[", 100)+strings.Repeat("]", 100))))
+	})
+}
+
+func TestDefaultDuplicateKeys(t *testing.T) {
+	type account struct {
+		IBAN   presence.Of[string]         `json:"iban"`
+		Limits presence.Of[map[string]int] `json:"limits"`
+	}
+
+	t.Run("allowed by default", func(t *testing.T) {
+		var n presence.Of[any]
+		require.NoError(t, json.Unmarshal([]byte(`{"a":1,"a":2}`), &n))
+		assert.Equal(t, map[string]any{"a": 2.0}, n.MustGet())
+	})
+
+	presence.SetDefaultDuplicateKeys(presence.DuplicateKeysReject)
+	defer presence.SetDefaultDuplicateKeys(presence.DuplicateKeysAllow)
+
+	t.Run("Of[any]", func(t *testing.T) {
+		var n presence.Of[any]
+		err := json.Unmarshal([]byte(`[{"a":{"b":1}},{"a":{"b":1,"c":{},"b":2}}]`), &n)
+		require.ErrorIs(t, err, presence.ErrDuplicateKey)
+		assert.ErrorContains(t, err, `"b"`)
+
+		require.NoError(t, json.Unmarshal([]byte(`[{"a":{}},{"a":[{"a":1},{"a":1}]},"a"]`), &n))
+	})
+
+	t.Run("presence fields", func(t *testing.T) {
+		var a account
+		err := json.Unmarshal([]byte(`{"iban":"FR76","limits":{"daily":100,"daily":1000000}}`), &a)
+		require.ErrorIs(t, err, presence.ErrDuplicateKey)
+	})
+
+	t.Run("UnmarshalMerge", func(t *testing.T) {
+		var a account
+		err := presence.UnmarshalMerge([]byte(`{"iban":"FR76","iban":"GB29"}`), &a)
+		require.ErrorIs(t, err, presence.ErrDuplicateKey)
+		assert.True(t, a.IBAN.IsUnset())
+	})
+
+	t.Run("CheckDuplicateKeys", func(t *testing.T) {
+		require.ErrorIs(t, presence.CheckDuplicateKeys([]byte(`{"iban":"FR76","iban":"GB29"}`)), presence.ErrDuplicateKey)
+		require.NoError(t, presence.CheckDuplicateKeys([]byte(`{"iban":"FR76","limits":{"iban":1}}`)))
+		require.NoError(t, presence.CheckDuplicateKeys([]byte(`{"iban":`)), "left to the decoding")
+	})
+}
+
+func TestDefaultNumberDecoding(t *testing.T) {
+	const payload = `{"id":9007199254740993,"amount":0.10,"tags":[1,2]}`
+
+	t.Run("float64 by default", func(t *testing.T) {
+		var n presence.Of[any]
+		require.NoError(t, n.UnmarshalJSON([]byte(payload)))
+		assert.InDelta(t, 9007199254740992.0, n.MustGet().(map[string]any)["id"], 0)
+	})
+
+	presence.SetDefaultNumberDecoding(presence.NumbersAsJSONNumber)
+	defer presence.SetDefaultNumberDecoding(presence.NumbersAsFloat64)
+
+	t.Run("UnmarshalJSON", func(t *testing.T) {
+		var n presence.Of[any]
+		require.NoError(t, n.UnmarshalJSON([]byte(payload)))
+		assert.Equal(t, map[string]any{
+			"id":     json.Number("9007199254740993"),
+			"amount": json.Number("0.10"),
+			"tags":   []any{json.Number("1"), json.Number("2")},
+		}, n.MustGet())
+
+		b, err := json.Marshal(n)
+		require.NoError(t, err)
+		assert.JSONEq(t, payload, string(b))
+		assert.Contains(t, string(b), "9007199254740993")
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		var n presence.Of[map[string]any]
+		require.NoError(t, n.Scan([]byte(payload)))
+		assert.Equal(t, json.Number("9007199254740993"), n.MustGet()["id"])
+	})
+
+	t.Run("typed values are not affected", func(t *testing.T) {
+		var n presence.Of[map[string]int64]
+		require.NoError(t, n.UnmarshalJSON([]byte(`{"id":9007199254740993}`)))
+		assert.Equal(t, int64(9007199254740993), n.MustGet()["id"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var n presence.Of[any]
+		require.Error(t, n.UnmarshalJSON([]byte(`{"id":1} {}`)))
+		require.Error(t, n.UnmarshalJSON([]byte(`{"id":`)))
+	})
+}
+
+func TestDefaultMapValue(t *testing.T) {
+	attrs := presence.FromValue(map[string]string{"size": "XL", "note": `say "hi" \o/`, "empty": ""})
+
+	t.Run("JSON by default", func(t *testing.T) {
+		v, err := attrs.Value()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"size":"XL","note":"say \"hi\" \\o/","empty":""}`, v.(string))
+	})
+
+	presence.SetDefaultMapValue(presence.MapValueHstore)
+	defer presence.SetDefaultMapValue(presence.MapValueJSON)
+
+	t.Run("hstore", func(t *testing.T) {
+		v, err := attrs.Value()
+		require.NoError(t, err)
+		assert.Equal(t, `"empty"=>"", "note"=>"say \"hi\" \\o/", "size"=>"XL"`, v)
+
+		var n presence.Of[map[string]string]
+		require.NoError(t, n.Scan(v))
+		assert.Equal(t, attrs, n)
+
+		v, err = presence.FromValue(map[string]string{}).Value()
+		require.NoError(t, err)
+		assert.Empty(t, v)
+
+		v, err = presence.Null[map[string]string]().Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		tests := []struct {
+			name string
+			src  any
+			want map[string]string
+		}{
+			{"postgres output", []byte(`"a"=>"1", "b"=>NULL, "c d"=>"x,y"`), map[string]string{"a": "1", "c d": "x,y"}},
+			{"unquoted", `a=>1,b => 2`, map[string]string{"a": "1", "b": "2"}},
+			{"quoted NULL", `"a"=>"NULL"`, map[string]string{"a": "NULL"}},
+			{"empty", ``, map[string]string{}},
+			{"JSON", `{"a":"1"}`, map[string]string{"a": "1"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var n presence.Of[map[string]string]
+				require.NoError(t, n.Scan(tt.src))
+				assert.Equal(t, tt.want, n.MustGet())
+			})
+		}
+
+		var n presence.Of[map[string]string]
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, src := range []string{`"a"=>`, `"a" "b"`, `"a=>"b"`, `=>"b"`, `"a"=>"1" "b"=>"2"`} {
+			var n presence.Of[map[string]string]
+			require.ErrorIs(t, n.Scan(src), presence.ErrInvalidHstore, src)
+		}
+	})
+}
+
+func TestDefaultJSONFallback(t *testing.T) {
+	tags := presence.FromValue([]string{"go", "sql"})
+
+	t.Run("JSON by default", func(t *testing.T) {
+		v, err := tags.Value()
+		require.NoError(t, err)
+		assert.Equal(t, `["go","sql"]`, v)
+
+		var n presence.Of[map[string]int]
+		require.NoError(t, n.Scan(`{"a":1}`))
+		assert.Equal(t, map[string]int{"a": 1}, n.MustGet())
+	})
+
+	presence.SetDefaultJSONFallback(presence.JSONFallbackDisabled)
+	defer presence.SetDefaultJSONFallback(presence.JSONFallbackEnabled)
+
+	t.Run("disabled for slices and maps", func(t *testing.T) {
+		_, err := tags.Value()
+		require.ErrorIs(t, err, presence.ErrUnsupportedType)
+
+		_, err = presence.FromValue(map[string]int{"a": 1}).Value()
+		require.ErrorIs(t, err, presence.ErrUnsupportedType)
+
+		var n presence.Of[[]string]
+		require.ErrorIs(t, n.Scan(`["go"]`), presence.ErrUnsupportedType)
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("nulls and other encodings still work", func(t *testing.T) {
+		v, err := presence.Null[[]string]().Value()
+		require.NoError(t, err)
+		assert.Nil(t, v)
+
+		var n presence.Of[[]string]
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+
+		v, err = presence.FromValue(struct{ Name string }{Name: "x"}).Value()
+		require.NoError(t, err)
+		assert.Contains(t, v, `"x"`)
+
+		presence.SetDefaultMapValue(presence.MapValueHstore)
+		defer presence.SetDefaultMapValue(presence.MapValueJSON)
+
+		v, err = presence.FromValue(map[string]string{"a": "1"}).Value()
+		require.NoError(t, err)
+		assert.Equal(t, `"a"=>"1"`, v)
+	})
+}
+
+func TestRegisterTypeDefaults(t *testing.T) {
+	presence.RegisterTypeDefaults[time.Time](presence.UnsetNull, presence.ScanNullAsUnset)
+	defer presence.UnregisterTypeDefaults[time.Time]()
+
+	t.Run("applies to the registered type", func(t *testing.T) {
+		var n presence.Of[time.Time]
+		assert.Equal(t, presence.UnsetNull, n.GetMarshalUnset())
+		assert.Equal(t, presence.ScanNullAsUnset, n.GetScanNull())
+		assert.False(t, n.IsZero())
+
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("applies to named types", func(t *testing.T) {
+		var n presence.Time
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("other types keep the package defaults", func(t *testing.T) {
+		var n presence.Of[string]
+		assert.Equal(t, presence.UnsetSkip, n.GetMarshalUnset())
+		assert.Equal(t, presence.ScanNullAsNull, n.GetScanNull())
+	})
+
+	t.Run("per-value settings win", func(t *testing.T) {
+		var n presence.Of[time.Time]
+		n.SetScanNull(presence.ScanNullAsNull)
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("type defaults win over package defaults", func(t *testing.T) {
+		presence.SetDefaultScanNull(presence.ScanNullAsNull)
+		var n presence.Of[time.Time]
+		assert.Equal(t, presence.ScanNullAsUnset, n.GetScanNull())
+	})
+
+	t.Run("unregister", func(t *testing.T) {
+		presence.RegisterTypeDefaults[int](presence.UnsetNull, presence.ScanNullAsUnset)
+		presence.UnregisterTypeDefaults[int]()
+
+		var n presence.Of[int]
+		assert.Equal(t, presence.ScanNullAsNull, n.GetScanNull())
+	})
+}
+
+func TestRegisterNormalizer(t *testing.T) {
+	type email string
+
+	presence.RegisterNormalizer(func(e email) email { return email(strings.ToLower(strings.TrimSpace(string(e)))) })
+	defer presence.UnregisterNormalizer[email]()
+
+	want := email("ada@example.com")
+
+	t.Run("SetValue", func(t *testing.T) {
+		n := presence.FromValue(email(" Ada@Example.com "))
+		assert.Equal(t, want, n.MustGet())
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		var n presence.Of[email]
+		require.NoError(t, n.Scan("ADA@example.com"))
+		assert.Equal(t, want, n.MustGet())
+
+		dec := presence.NewDecoder[email](0)
+		require.NoError(t, dec.Scan(&n, "ADA@example.com "))
+		assert.Equal(t, want, n.MustGet())
+	})
+
+	t.Run("UnmarshalJSON", func(t *testing.T) {
+		var n presence.Of[email]
+		require.NoError(t, n.UnmarshalJSON([]byte(`"Ada@Example.com"`)))
+		assert.Equal(t, want, n.MustGet())
+
+		require.NoError(t, n.UnmarshalJSON([]byte(`null`)))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("clamping, after the time normalization", func(t *testing.T) {
+		presence.SetDefaultTimeNormalization(presence.TimeNormalization{UTC: true})
+		defer presence.SetDefaultTimeNormalization(presence.TimeNormalization{})
+
+		epoch := time.Unix(0, 0).UTC()
+		presence.RegisterNormalizer(func(t time.Time) time.Time {
+			if t.Before(epoch) {
+				return epoch
+			}
+
+			return t
+		})
+		defer presence.UnregisterNormalizer[time.Time]()
+
+		n := presence.NewTime(time.Date(1960, 1, 1, 0, 0, 0, 0, time.FixedZone("X", 3600)))
+		assert.Equal(t, epoch, n.MustGet())
+	})
+
+	t.Run("other types are left alone", func(t *testing.T) {
+		n := presence.FromValue(" A ")
+		assert.Equal(t, " A ", n.MustGet())
+	})
+
+	t.Run("unregister", func(t *testing.T) {
+		presence.RegisterNormalizer(strings.TrimSpace)
+		presence.UnregisterNormalizer[string]()
+
+		n := presence.FromValue(" A ")
+		assert.Equal(t, " A ", n.MustGet())
+	})
+}
+
+func TestRegisterValidator(t *testing.T) {
+	type age int
+
+	errNegative := errors.New("negative age")
+	presence.RegisterValidator(func(a age) error {
+		if a < 0 {
+			return errNegative
+		}
+
+		return nil
+	})
+	defer presence.UnregisterValidator[age]()
+
+	t.Run("Scan", func(t *testing.T) {
+		var n presence.Of[age]
+		require.NoError(t, n.Scan(int64(42)))
+		assert.Equal(t, age(42), n.MustGet())
+
+		require.ErrorIs(t, n.Scan(int64(-1)), errNegative)
+		assert.True(t, n.IsUnset())
+
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+
+		dec := presence.NewDecoder[age](0)
+		require.ErrorIs(t, dec.Scan(&n, int64(-1)), errNegative)
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("UnmarshalJSON", func(t *testing.T) {
+		type person struct {
+			Age presence.Of[age] `json:"age"`
+		}
+
+		var p person
+		require.NoError(t, json.Unmarshal([]byte(`{"age":42}`), &p))
+		assert.Equal(t, age(42), p.Age.MustGet())
+
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"age":-1}`), &p), errNegative)
+		assert.Equal(t, age(42), p.Age.MustGet(), "a rejected value leaves the field unchanged")
+
+		var fresh person
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"age":-1}`), &fresh), errNegative)
+		assert.True(t, fresh.Age.IsUnset())
+	})
+
+	t.Run("UnmarshalJSON does not write into copies", func(t *testing.T) {
+		a := presence.FromValue(age(5))
+		b := a
+		require.ErrorIs(t, b.UnmarshalJSON([]byte(`-3`)), errNegative)
+		assert.Equal(t, age(5), a.MustGet())
+		assert.Equal(t, age(5), b.MustGet())
+
+		require.NoError(t, b.UnmarshalJSON([]byte(`7`)))
+		assert.Equal(t, age(5), a.MustGet())
+		assert.Equal(t, age(7), b.MustGet())
+	})
+
+	t.Run("SetValueChecked", func(t *testing.T) {
+		n := presence.FromValue(age(1))
+		require.ErrorIs(t, n.SetValueChecked(-1), errNegative)
+		assert.Equal(t, age(1), n.MustGet())
+
+		require.NoError(t, n.SetValueChecked(2))
+		assert.Equal(t, age(2), n.MustGet())
+	})
+
+	t.Run("after the normalizer", func(t *testing.T) {
+		presence.RegisterNormalizer(func(a age) age { return max(a, 0) })
+		defer presence.UnregisterNormalizer[age]()
+
+		var n presence.Of[age]
+		require.NoError(t, n.SetValueChecked(-1))
+		assert.Equal(t, age(0), n.MustGet())
+	})
+
+	t.Run("typed UUIDs", func(t *testing.T) {
+		type orderID uuid.UUID
+
+		errNil := errors.New("nil order ID")
+		presence.RegisterValidator(func(id orderID) error {
+			if id == orderID(uuid.Nil) {
+				return errNil
+			}
+
+			return nil
+		})
+		defer presence.UnregisterValidator[orderID]()
+
+		var n presence.Of[orderID]
+		require.ErrorIs(t, n.UnmarshalJSON([]byte(`"`+uuid.Nil.String()+`"`)), errNil)
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("SetValue does not validate", func(t *testing.T) {
+		n := presence.FromValue(age(-1))
+		assert.Equal(t, age(-1), n.MustGet())
+	})
+
+	t.Run("GetValidator", func(t *testing.T) {
+		require.ErrorIs(t, presence.GetValidator[age]()(-1), errNegative)
+		assert.Nil(t, presence.GetValidator[int]())
+	})
+}
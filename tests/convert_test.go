@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type convertUser struct {
+	ID       string              `json:"id"`
+	Username presence.Of[string] `json:"username"`
+	Email    presence.Of[string] `json:"email"`
+	Bio      presence.Of[string] `json:"bio"`
+	Age      presence.Of[int]    `json:"age"`
+	Internal string              `json:"-"`
+}
+
+// convertModel is a GraphQL model with pointer fields.
+type convertModel struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Email    *string `json:"email"`
+	Bio      *string `json:"bio"`
+	Age      *int64  `json:"age"`
+	Website  *string `json:"website"`
+}
+
+// Tests for ConvertStruct
+
+func TestConvertStruct(t *testing.T) {
+	t.Run("to model", func(t *testing.T) {
+		user := convertUser{
+			ID:       "1",
+			Username: presence.FromValue("ada"),
+			Email:    presence.FromValue("ada@example.com"),
+			Bio:      presence.Null[string](),
+		}
+		out := convertModel{Bio: new(string), Age: new(int64), Website: new(string)}
+
+		require.NoError(t, presence.ConvertStruct(&out, user))
+		assert.Equal(t, "1", out.ID)
+		assert.Equal(t, "ada", out.Username)
+		assert.Equal(t, "ada@example.com", *out.Email)
+		assert.Nil(t, out.Bio, "null")
+		assert.Nil(t, out.Age, "unset")
+		assert.NotNil(t, out.Website, "missing from src")
+
+		*out.Email = "changed"
+		assert.Equal(t, "ada@example.com", user.Email.MustGet(), "values are copied")
+	})
+
+	t.Run("from model", func(t *testing.T) {
+		email, age := "ada@example.com", int64(36)
+		in := convertModel{ID: "1", Username: "ada", Email: &email, Age: &age}
+
+		var user convertUser
+		require.NoError(t, presence.ConvertStruct(&user, &in))
+		assert.Equal(t, convertUser{
+			ID:       "1",
+			Username: presence.FromValue("ada"),
+			Email:    presence.FromValue("ada@example.com"),
+			Bio:      presence.Null[string](),
+			Age:      presence.FromValue(36),
+		}, user)
+	})
+
+	t.Run("presence to presence", func(t *testing.T) {
+		src := convertUser{Email: presence.Null[string](), Age: presence.FromValue(1)}
+		dst := convertUser{Username: presence.FromValue("ada"), Internal: "kept"}
+
+		require.NoError(t, presence.ConvertStruct(&dst, src))
+		assert.Equal(t, convertUser{Email: presence.Null[string](), Age: presence.FromValue(1), Internal: "kept"}, dst)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var out convertModel
+		err := presence.ConvertStruct(&out, convertUser{Username: presence.Null[string]()})
+		require.ErrorIs(t, err, presence.ErrNullNotAllowed)
+		require.ErrorContains(t, err, "username")
+
+		type badModel struct {
+			Age *bool `json:"age"`
+		}
+
+		require.Error(t, presence.ConvertStruct(&badModel{}, convertUser{Age: presence.FromValue(1)}))
+		require.Error(t, presence.ConvertStruct(out, convertUser{}))
+		require.Error(t, presence.ConvertStruct(&out, 1))
+	})
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/pivaldi/presence"
+	presencecopier "github.com/pivaldi/presence/contrib/copier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type copierEntity struct {
+	ID        int64
+	Email     string
+	Age       *int
+	CreatedAt time.Time
+	Rating    *float32
+}
+
+type copierDTO struct {
+	ID        int64
+	Email     presence.Of[string]
+	Age       presence.Of[int]
+	CreatedAt presence.Of[time.Time]
+	Rating    presence.Of[float32]
+}
+
+// Tests for presencecopier
+
+func TestCopierEntityToDTO(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entity := copierEntity{ID: 1, Email: "ada@b.c", CreatedAt: createdAt}
+
+	var dto copierDTO
+	require.NoError(t, copier.CopyWithOption(&dto, &entity,
+		presencecopier.Option(presencecopier.Converters[float32]())))
+
+	assert.Equal(t, copierDTO{
+		ID:        1,
+		Email:     presence.FromValue("ada@b.c"),
+		Age:       presence.Null[int](),
+		CreatedAt: presence.FromValue(createdAt),
+		Rating:    presence.Null[float32](),
+	}, dto)
+
+	age := 36
+	entity.Age = &age
+	require.NoError(t, copier.CopyWithOption(&dto, &entity, presencecopier.Option()))
+	assert.Equal(t, presence.FromValue(36), dto.Age)
+}
+
+func TestCopierDTOToEntity(t *testing.T) {
+	dto := copierDTO{
+		ID:    1,
+		Email: presence.Null[string](),
+		Age:   presence.FromValue(36),
+	}
+
+	var entity copierEntity
+	require.NoError(t, copier.CopyWithOption(&entity, &dto, presencecopier.Option()))
+
+	age := 36
+	assert.Equal(t, copierEntity{ID: 1, Age: &age}, entity)
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests for DebugString
+
+func TestDebugString(t *testing.T) {
+	patch := userPatch{
+		Name:  presence.FromValue("x"),
+		Email: presence.NewString(""),
+		Age:   presence.Null[int](),
+		Plain: "p",
+	}
+
+	t.Run("state annotations", func(t *testing.T) {
+		assert.Equal(t, `{updated_by: <unset>, name: "x", email: "", age: <null>, plain: "p"}`,
+			presence.DebugString(patch))
+	})
+
+	t.Run("options name the fields", func(t *testing.T) {
+		assert.Equal(t, `{updated_by: <unset>, full_name: "x", email_address: "", age: <null>, internal: <unset>, Plain: "p"}`,
+			presence.DebugString(&patch, presence.WithTag("db")))
+	})
+
+	t.Run("presence values and other values", func(t *testing.T) {
+		assert.Equal(t, "42", presence.DebugString(presence.FromValue(42)))
+		assert.Equal(t, "<null>", presence.DebugString(presence.Null[int]()))
+		assert.Equal(t, "<unset>", presence.DebugString((*presence.Of[int])(nil)))
+		assert.Equal(t, "42", presence.DebugString(42))
+	})
+
+	t.Run("pointer fields", func(t *testing.T) {
+		email := "a@b.c"
+		assert.Equal(t, `{Name: "", email: "a@b.c", age: <unset>, updated_by: <unset>}`,
+			presence.DebugString(userEntity{Email: &email}))
+		assert.Equal(t, `{Name: "", email: <nil>, age: <unset>, updated_by: <unset>}`,
+			presence.DebugString(userEntity{}))
+	})
+}
+
+func TestDebugStringColor(t *testing.T) {
+	out := presence.DebugStringColor(userPatch{Name: presence.FromValue("x"), Age: presence.Null[int]()})
+	assert.Contains(t, out, "name: \x1b[32m\"x\"\x1b[0m")
+	assert.Contains(t, out, "age: \x1b[33m<null>\x1b[0m")
+	assert.Contains(t, out, "updated_by: \x1b[2m<unset>\x1b[0m")
+}
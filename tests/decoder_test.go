@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Decoder
+
+func TestDecoderScan(t *testing.T) {
+	t.Run("values and nulls", func(t *testing.T) {
+		dec := presence.NewDecoder[int64](2)
+		values := make([]presence.Of[int64], 5)
+		srcs := []any{int64(1), nil, int64(3), int64(4), int64(5)}
+
+		for i, src := range srcs {
+			require.NoError(t, dec.Scan(&values[i], src))
+		}
+
+		assert.Equal(t, int64(1), values[0].MustGet())
+		assert.True(t, values[1].IsNull())
+		assert.Equal(t, int64(3), values[2].MustGet())
+		assert.Equal(t, int64(4), values[3].MustGet())
+		assert.Equal(t, int64(5), values[4].MustGet())
+	})
+
+	t.Run("conversions and times", func(t *testing.T) {
+		dec := presence.NewDecoder[time.Time](0)
+		var n presence.Of[time.Time]
+
+		require.NoError(t, dec.Scan(&n, "2024-01-02T03:04:05Z"))
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), n.MustGet())
+	})
+
+	t.Run("scan null as unset", func(t *testing.T) {
+		dec := presence.NewDecoder[string](0)
+		var n presence.Of[string]
+		n.SetScanNull(presence.ScanNullAsUnset)
+
+		require.NoError(t, dec.Scan(&n, nil))
+		assert.True(t, n.IsUnset())
+		assert.Equal(t, presence.ScanNullAsUnset, n.GetScanNull())
+
+		require.NoError(t, dec.Scan(&n, "x"))
+		assert.Equal(t, "x", n.MustGet())
+	})
+
+	t.Run("errors keep the previous value", func(t *testing.T) {
+		dec := presence.NewDecoder[int](0)
+		n := presence.FromValue(7)
+
+		require.Error(t, dec.Scan(&n, "not a number"))
+		assert.Equal(t, 7, n.MustGet())
+	})
+
+	t.Run("typed IDs", func(t *testing.T) {
+		dec := presence.NewDecoder[userID](0)
+		var n presence.Of[userID]
+
+		require.NoError(t, dec.Scan(&n, int64(42)))
+		assert.Equal(t, userID(42), n.MustGet())
+	})
+
+	t.Run("reset reuses the storage", func(t *testing.T) {
+		dec := presence.NewDecoder[string](4)
+		var first, second presence.Of[string]
+
+		require.NoError(t, dec.Scan(&first, "a"))
+		kept := first.WithValue(first.MustGet())
+
+		dec.Reset()
+		require.NoError(t, dec.Scan(&second, "b"))
+
+		assert.Equal(t, "b", first.MustGet(), "values decoded before Reset share the reused storage")
+		assert.Equal(t, "a", kept.MustGet())
+	})
+}
+
+func TestDecoderDecodeJSON(t *testing.T) {
+	type event struct {
+		ID   int    `json:"id"`
+		Kind string `json:"kind"`
+	}
+
+	t.Run("values and nulls", func(t *testing.T) {
+		dec := presence.NewDecoder[event](0)
+		events := make([]presence.Of[event], 3)
+		lines := []string{`{"id":1,"kind":"a"}`, `null`, `{"id":3}`}
+
+		for i, line := range lines {
+			require.NoError(t, dec.DecodeJSON(&events[i], []byte(line)))
+		}
+
+		assert.Equal(t, event{ID: 1, Kind: "a"}, events[0].MustGet())
+		assert.True(t, events[1].IsNull())
+		assert.Equal(t, event{ID: 3}, events[2].MustGet(), "slots are zeroed before decoding")
+	})
+
+	t.Run("reused slots are zeroed", func(t *testing.T) {
+		dec := presence.NewDecoder[event](1)
+		var n presence.Of[event]
+
+		require.NoError(t, dec.DecodeJSON(&n, []byte(`{"id":1,"kind":"a"}`)))
+		dec.Reset()
+		require.NoError(t, dec.DecodeJSON(&n, []byte(`{"id":2}`)))
+		assert.Equal(t, event{ID: 2}, n.MustGet())
+	})
+
+	t.Run("errors keep the previous value", func(t *testing.T) {
+		dec := presence.NewDecoder[event](0)
+		n := presence.FromValue(event{ID: 1})
+
+		require.Error(t, dec.DecodeJSON(&n, []byte(`{"id":"x"}`)))
+		assert.Equal(t, event{ID: 1}, n.MustGet())
+	})
+
+	t.Run("typed IDs", func(t *testing.T) {
+		dec := presence.NewDecoder[orderID](0)
+		var n presence.Of[orderID]
+
+		require.NoError(t, dec.DecodeJSON(&n, []byte(`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`)))
+		assert.True(t, n.IsValue())
+	})
+
+	t.Run("within json.Unmarshal", func(t *testing.T) {
+		dec := presence.NewDecoder[string](0)
+		var raw map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(`{"name":"Ada"}`), &raw))
+
+		var name presence.Of[string]
+		require.NoError(t, dec.DecodeJSON(&name, raw["name"]))
+		assert.Equal(t, "Ada", name.MustGet())
+	})
+}
+
+type lineEvent struct {
+	ID    int                 `json:"id"`
+	Name  presence.Of[string] `json:"name"`
+	Score presence.Of[int]    `json:"score"`
+}
+
+func TestDecodeLines(t *testing.T) {
+	t.Run("lines", func(t *testing.T) {
+		input := "{\"id\":1,\"name\":\"ada\",\"score\":3}\n\n{\"id\":2,\"score\":null}\r\n{\"id\":3," +
+			"\"name\":\"" + strings.Repeat("x", 100_000) + "\"}"
+
+		var events []lineEvent
+
+		require.NoError(t, presence.DecodeLines(strings.NewReader(input), func(e *lineEvent) error {
+			events = append(events, *e)
+
+			return nil
+		}))
+
+		require.Len(t, events, 3)
+		assert.Equal(t, lineEvent{ID: 1, Name: presence.FromValue("ada"), Score: presence.FromValue(3)}, events[0])
+		assert.Equal(t, lineEvent{ID: 2, Score: presence.Null[int]()}, events[1], "fields of the previous line are reset")
+		assert.Equal(t, 100_000, len(events[2].Name.MustGet()))
+		assert.True(t, events[2].Score.IsUnset())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := presence.DecodeLines(strings.NewReader("{\"id\":1}\n{\"id\":"), func(*lineEvent) error { return nil })
+		require.ErrorContains(t, err, "line 2")
+
+		stop := errors.New("stop")
+		err = presence.DecodeLines(strings.NewReader("{\"id\":1}\n{\"id\":2}"), func(*lineEvent) error { return stop })
+		require.ErrorIs(t, err, stop)
+		require.ErrorContains(t, err, "line 1")
+	})
+}
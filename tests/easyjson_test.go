@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"github.com/pivaldi/presence"
+	presenceeasyjson "github.com/pivaldi/presence/contrib/easyjson"
+	"github.com/pivaldi/presence/tests/easyjsonmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainUserPatch has the fields of UserPatch without its generated methods, so that
+// encoding/json encodes it by reflection.
+type plainUserPatch easyjsonmodel.UserPatch
+
+func easyjsonPatches() map[string]easyjsonmodel.UserPatch {
+	email := presence.FromValue("a@b.c")
+	birth := time.Date(1815, 12, 10, 0, 0, 0, 0, time.UTC)
+
+	return map[string]easyjsonmodel.UserPatch{
+		"unset": {},
+		"values": {
+			Audit:   easyjsonmodel.Audit{UpdatedBy: presence.FromValue("admin"), Reason: "import"},
+			Name:    presence.FromValue(`Ada "<Lovelace>"`),
+			Age:     presence.FromValue(36),
+			Bio:     presence.NewString("mathematician"),
+			Email:   &email,
+			Birth:   presence.FromValue(birth),
+			Score:   presence.FromValue(9.5),
+			Profile: presence.FromValue(easyjsonmodel.Profile{Website: presence.FromValue("x.org"), Ratio: 0.5}),
+			Tags:    []string{"a", "b"},
+			Version: 3,
+			Active:  true,
+		},
+		"nulls": {
+			Name:    presence.Null[string](),
+			Age:     presence.Null[int](),
+			Bio:     presence.String{Of: presence.Null[string]()},
+			Email:   &presence.Of[string]{},
+			Score:   presence.Null[float64](),
+			Profile: presence.Null[easyjsonmodel.Profile](),
+		},
+	}
+}
+
+// Tests for the presence-easyjson generated code
+
+func TestEasyJSONMarshal(t *testing.T) {
+	for name, patch := range easyjsonPatches() {
+		t.Run(name, func(t *testing.T) {
+			want, err := json.Marshal(plainUserPatch(patch))
+			require.NoError(t, err)
+
+			got, err := easyjson.Marshal(patch)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+
+			got, err = json.Marshal(patch)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(got), "encoding/json uses the generated MarshalJSON")
+		})
+	}
+}
+
+func TestEasyJSONUnmarshal(t *testing.T) {
+	for name, patch := range easyjsonPatches() {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(plainUserPatch(patch))
+			require.NoError(t, err)
+
+			var want plainUserPatch
+			require.NoError(t, json.Unmarshal(data, &want))
+
+			var got easyjsonmodel.UserPatch
+			require.NoError(t, easyjson.Unmarshal(data, &got))
+			assert.Equal(t, easyjsonmodel.UserPatch(want), got)
+		})
+	}
+
+	t.Run("three states", func(t *testing.T) {
+		var got easyjsonmodel.UserPatch
+		require.NoError(t, easyjson.Unmarshal([]byte(`{"name":"Ada","age":null,"email":null,"unknown":{"a":[1]}}`), &got))
+		assert.Equal(t, "Ada", got.Name.MustGet())
+		assert.True(t, got.Age.IsNull())
+		assert.True(t, got.Bio.IsUnset())
+		assert.Nil(t, got.Email)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var got easyjsonmodel.UserPatch
+		require.Error(t, easyjson.Unmarshal([]byte(`{"age":"x"}`), &got))
+		require.Error(t, easyjson.Unmarshal([]byte(`{"birth":1}`), &got))
+		require.Error(t, json.Unmarshal([]byte(`{"name":`), &got))
+	})
+}
+
+func TestEasyJSONGenerate(t *testing.T) {
+	t.Run("generated code is up to date", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, presenceeasyjson.Generate(&out, "easyjsonmodel", "Profile"))
+
+		committed, err := os.ReadFile(filepath.Join("easyjsonmodel", "presence_easyjson.go"))
+		require.NoError(t, err)
+		assert.Equal(t, string(committed), out.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var out bytes.Buffer
+		require.ErrorContains(t, presenceeasyjson.Generate(&out, "easyjsonmodel", "Missing"), "unknown type Missing")
+	})
+}
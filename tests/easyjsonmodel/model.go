@@ -0,0 +1,41 @@
+// Package easyjsonmodel holds the structs whose easyjson methods presence-easyjson
+// generates for the tests.
+package easyjsonmodel
+
+import (
+	"time"
+
+	"github.com/pivaldi/presence"
+)
+
+//go:generate go run github.com/pivaldi/presence/contrib/easyjson/cmd/presence-easyjson -type Profile
+
+// Audit is embedded in UserPatch, its fields being flattened.
+type Audit struct {
+	UpdatedBy presence.Of[string] `json:"updated_by,omitzero"`
+	Reason    string              `json:"reason,omitempty"`
+}
+
+//presence:easyjson
+type UserPatch struct {
+	Audit
+
+	Name     presence.Of[string]    `json:"name,omitzero"`
+	Age      presence.Of[int]       `json:"age,omitzero"`
+	Bio      presence.String        `json:"bio,omitzero"`
+	Email    *presence.Of[string]   `json:"email,omitzero"`
+	Birth    presence.Of[time.Time] `json:"birth,omitzero"`
+	Score    presence.Of[float64]   `json:"score"`
+	Profile  presence.Of[Profile]   `json:"profile,omitzero"`
+	Tags     []string               `json:"tags,omitempty"`
+	Version  int64                  `json:"version"`
+	Active   bool                   `json:"active,omitempty"`
+	Ignored  string                 `json:"-"`
+	internal string
+}
+
+// Profile is generated through -type.
+type Profile struct {
+	Website presence.Of[string] `json:"website,omitzero"`
+	Ratio   float64             `json:"ratio"`
+}
@@ -0,0 +1,249 @@
+// Code generated by presence-easyjson. DO NOT EDIT.
+
+package easyjsonmodel
+
+import (
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+	presenceeasyjson "github.com/pivaldi/presence/contrib/easyjson"
+)
+
+// MarshalEasyJSON implements easyjson.Marshaler.
+func (v Profile) MarshalEasyJSON(w *jwriter.Writer) {
+	first := true
+	w.RawByte('{')
+	if !v.Website.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"website\":")
+		presenceeasyjson.WriteOf(w, v.Website)
+	}
+	if !first {
+		w.RawByte(',')
+	}
+	first = false
+	w.RawString("\"ratio\":")
+	presenceeasyjson.Write(w, v.Ratio)
+	w.RawByte('}')
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v Profile) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	v.MarshalEasyJSON(&w)
+
+	return w.BuildBytes()
+}
+
+// UnmarshalEasyJSON implements easyjson.Unmarshaler.
+func (v *Profile) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	isTopLevel := l.IsStart()
+	if l.IsNull() {
+		l.Skip()
+
+		return
+	}
+
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		key := l.UnsafeFieldName(false)
+		l.WantColon()
+
+		switch key {
+		case "website":
+			presenceeasyjson.ReadOf(l, &v.Website)
+		case "ratio":
+			presenceeasyjson.Read(l, &v.Ratio)
+		default:
+			l.SkipRecursive()
+		}
+
+		l.WantComma()
+	}
+	l.Delim('}')
+
+	if isTopLevel {
+		l.Consumed()
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Profile) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	v.UnmarshalEasyJSON(&l)
+
+	return l.Error()
+}
+
+// MarshalEasyJSON implements easyjson.Marshaler.
+func (v UserPatch) MarshalEasyJSON(w *jwriter.Writer) {
+	first := true
+	w.RawByte('{')
+	if !v.Audit.UpdatedBy.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"updated_by\":")
+		presenceeasyjson.WriteOf(w, v.Audit.UpdatedBy)
+	}
+	if v.Audit.Reason != "" {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"reason\":")
+		w.String(v.Audit.Reason)
+	}
+	if !v.Name.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"name\":")
+		presenceeasyjson.WriteOf(w, v.Name)
+	}
+	if !v.Age.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"age\":")
+		presenceeasyjson.WriteOf(w, v.Age)
+	}
+	if !v.Bio.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"bio\":")
+		presenceeasyjson.WriteOf(w, v.Bio.Of)
+	}
+	if v.Email != nil && !v.Email.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"email\":")
+		if v.Email == nil {
+			w.RawString("null")
+		} else {
+			presenceeasyjson.WriteOf(w, *v.Email)
+		}
+	}
+	if !v.Birth.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"birth\":")
+		presenceeasyjson.WriteOf(w, v.Birth)
+	}
+	if !first {
+		w.RawByte(',')
+	}
+	first = false
+	w.RawString("\"score\":")
+	presenceeasyjson.WriteOf(w, v.Score)
+	if !v.Profile.IsZero() {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"profile\":")
+		presenceeasyjson.WriteOf(w, v.Profile)
+	}
+	if !presenceeasyjson.IsEmpty(v.Tags) {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"tags\":")
+		presenceeasyjson.Write(w, v.Tags)
+	}
+	if !first {
+		w.RawByte(',')
+	}
+	first = false
+	w.RawString("\"version\":")
+	w.Int64(v.Version)
+	if v.Active {
+		if !first {
+			w.RawByte(',')
+		}
+		first = false
+		w.RawString("\"active\":")
+		w.Bool(v.Active)
+	}
+	w.RawByte('}')
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v UserPatch) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	v.MarshalEasyJSON(&w)
+
+	return w.BuildBytes()
+}
+
+// UnmarshalEasyJSON implements easyjson.Unmarshaler.
+func (v *UserPatch) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	isTopLevel := l.IsStart()
+	if l.IsNull() {
+		l.Skip()
+
+		return
+	}
+
+	l.Delim('{')
+	for !l.IsDelim('}') {
+		key := l.UnsafeFieldName(false)
+		l.WantColon()
+
+		switch key {
+		case "updated_by":
+			presenceeasyjson.ReadOf(l, &v.Audit.UpdatedBy)
+		case "reason":
+			v.Audit.Reason = l.String()
+		case "name":
+			presenceeasyjson.ReadOf(l, &v.Name)
+		case "age":
+			presenceeasyjson.ReadOf(l, &v.Age)
+		case "bio":
+			presenceeasyjson.ReadOf(l, &v.Bio.Of)
+		case "email":
+			presenceeasyjson.ReadOfPtr(l, &v.Email)
+		case "birth":
+			presenceeasyjson.ReadOf(l, &v.Birth)
+		case "score":
+			presenceeasyjson.ReadOf(l, &v.Score)
+		case "profile":
+			presenceeasyjson.ReadOf(l, &v.Profile)
+		case "tags":
+			presenceeasyjson.Read(l, &v.Tags)
+		case "version":
+			v.Version = l.Int64()
+		case "active":
+			v.Active = l.Bool()
+		default:
+			l.SkipRecursive()
+		}
+
+		l.WantComma()
+	}
+	l.Delim('}')
+
+	if isTopLevel {
+		l.Consumed()
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *UserPatch) UnmarshalJSON(data []byte) error {
+	l := jlexer.Lexer{Data: data}
+	v.UnmarshalEasyJSON(&l)
+
+	return l.Error()
+}
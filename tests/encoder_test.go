@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// Tests for Encoder
+
+func TestEncoder(t *testing.T) {
+	t.Run("fields follow the presence semantics", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := presence.NewEncoder(&buf)
+
+		unsetNull := presence.Of[int]{}
+		unsetNull.SetMarshalUnset(presence.UnsetNull)
+
+		require.NoError(t, enc.BeginObject())
+		require.NoError(t, enc.Field("id", 42))
+		require.NoError(t, presence.EncodeField(enc, "name", presence.FromValue("Ada <3")))
+		require.NoError(t, presence.EncodeField(enc, "bio", presence.Null[string]()))
+		require.NoError(t, presence.EncodeField(enc, "age", presence.Of[int]{}))
+		require.NoError(t, presence.EncodeField(enc, "score", unsetNull))
+		require.NoError(t, enc.BeginObjectField("address"))
+		require.NoError(t, presence.EncodeField(enc, "city", presence.FromValue("Paris")))
+		require.NoError(t, enc.EndObject())
+		require.NoError(t, enc.BeginObjectField("empty"))
+		require.NoError(t, enc.EndObject())
+		require.NoError(t, enc.EndObject())
+
+		assert.Equal(t, `{"id":42,"name":"Ada \u003c3","bio":null,"score":null,"address":{"city":"Paris"},"empty":{}}`,
+			buf.String())
+		assert.True(t, json.Valid(buf.Bytes()))
+	})
+
+	t.Run("without HTML escaping", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := presence.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+
+		require.NoError(t, enc.BeginObject())
+		require.NoError(t, enc.Field("query", "a < b && c"))
+		require.NoError(t, presence.EncodeField(enc, "name", presence.FromValue(`Ada <3 \u003c`)))
+		require.NoError(t, enc.EndObject())
+
+		assert.Equal(t, `{"query":"a < b && c","name":"Ada <3 \\u003c"}`, buf.String())
+	})
+
+	t.Run("matches encoding/json", func(t *testing.T) {
+		type row struct {
+			Name presence.Of[string] `json:"name,omitzero"`
+			Tags presence.Of[[]int]  `json:"tags,omitzero"`
+		}
+
+		r := row{Tags: presence.FromValue([]int{1, 2})}
+		want, err := json.Marshal(r)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		enc := presence.NewEncoder(&buf)
+		require.NoError(t, enc.BeginObject())
+		require.NoError(t, presence.EncodeField(enc, "name", r.Name))
+		require.NoError(t, presence.EncodeField(enc, "tags", r.Tags))
+		require.NoError(t, enc.EndObject())
+		assert.JSONEq(t, string(want), buf.String())
+	})
+
+	t.Run("misuse", func(t *testing.T) {
+		enc := presence.NewEncoder(&bytes.Buffer{})
+		require.Error(t, enc.Field("a", 1))
+
+		enc = presence.NewEncoder(&bytes.Buffer{})
+		require.Error(t, enc.EndObject())
+
+		enc = presence.NewEncoder(&bytes.Buffer{})
+		require.NoError(t, enc.BeginObject())
+		require.Error(t, enc.BeginObject())
+	})
+
+	t.Run("errors are sticky", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := presence.NewEncoder(&buf)
+		require.NoError(t, enc.BeginObject())
+		require.ErrorContains(t, presence.EncodeField(enc, "c", presence.FromValue(make(chan int))), "field c")
+		require.Error(t, enc.Field("a", 1))
+		require.Error(t, enc.EndObject())
+		assert.Equal(t, "{", buf.String())
+
+		enc = presence.NewEncoder(failingWriter{})
+		require.ErrorContains(t, enc.BeginObject(), "disk full")
+	})
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Range, Sort and Page
+
+func TestRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("open", func(t *testing.T) {
+		r := presence.Range[time.Time]{To: presence.Null[time.Time]()}
+		assert.True(t, r.IsOpen())
+
+		cond, args := r.SQL("created_at")
+		assert.Empty(t, cond)
+		assert.Empty(t, args)
+		assert.Nil(t, r.Mongo("created_at"))
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		r := presence.Range[time.Time]{From: presence.FromValue(from), To: presence.FromValue(to)}
+		assert.False(t, r.IsOpen())
+
+		cond, args := r.SQL("created_at")
+		assert.Equal(t, "created_at >= ? AND created_at <= ?", cond)
+		assert.Equal(t, []any{from, to}, args)
+		assert.Equal(t, map[string]any{"created_at": map[string]any{"$gte": from, "$lte": to}}, r.Mongo("created_at"))
+	})
+
+	t.Run("half open", func(t *testing.T) {
+		var r presence.Range[int]
+		require.NoError(t, json.Unmarshal([]byte(`{"to": 10}`), &r))
+
+		cond, args := r.SQL("total")
+		assert.Equal(t, "total <= ?", cond)
+		assert.Equal(t, []any{10}, args)
+		assert.Equal(t, map[string]any{"total": map[string]any{"$lte": 10}}, r.Mongo("total"))
+	})
+}
+
+func TestSort(t *testing.T) {
+	columns := map[string]string{"created_at": "o.created_at", "total": "o.total"}
+
+	t.Run("parse and encode", func(t *testing.T) {
+		s := presence.ParseSort("-created_at")
+		assert.Equal(t, presence.FromValue("created_at"), s.Field)
+		assert.True(t, s.Desc)
+		assert.Equal(t, "-created_at", s.String())
+		assert.Equal(t, "total", presence.ParseSort("+total").String())
+		assert.Empty(t, presence.ParseSort("").String())
+
+		var req struct {
+			Sort presence.Sort `json:"sort"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"sort":"-total"}`), &req))
+		assert.Equal(t, presence.ParseSort("-total"), req.Sort)
+
+		b, err := json.Marshal(req)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"sort":"-total"}`, string(b))
+	})
+
+	t.Run("SQL and Mongo", func(t *testing.T) {
+		orderBy, err := presence.ParseSort("-created_at").SQL(columns)
+		require.NoError(t, err)
+		assert.Equal(t, "ORDER BY o.created_at DESC", orderBy)
+
+		orderBy, err = presence.ParseSort("total").SQL(columns)
+		require.NoError(t, err)
+		assert.Equal(t, "ORDER BY o.total ASC", orderBy)
+
+		doc, err := presence.ParseSort("-created_at").Mongo(map[string]string{"created_at": "createdAt"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"createdAt": -1}, doc)
+
+		orderBy, err = presence.Sort{}.SQL(columns)
+		require.NoError(t, err)
+		assert.Empty(t, orderBy)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		_, err := presence.ParseSort("password; DROP TABLE users").SQL(columns)
+		require.ErrorIs(t, err, presence.ErrInvalidSort)
+		assert.Equal(t, "invalid sort field", presence.ErrorMessage(err, "en"))
+
+		_, err = presence.ParseSort("password").Mongo(columns)
+		require.ErrorIs(t, err, presence.ErrInvalidSort)
+	})
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		page          presence.Page
+		limit, offset int
+	}{
+		{"unset", presence.Page{}, 20, 0},
+		{"third page", presence.Page{Number: presence.FromValue(3), Size: presence.FromValue(10)}, 10, 20},
+		{"size above max", presence.Page{Size: presence.FromValue(1000)}, 100, 0},
+		{"invalid values", presence.Page{Number: presence.FromValue(-2), Size: presence.FromValue(0)}, 20, 0},
+		{"null size", presence.Page{Number: presence.FromValue(2), Size: presence.Null[int]()}, 20, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset := tt.page.Bounds(20, 100)
+			assert.Equal(t, tt.limit, limit)
+			assert.Equal(t, tt.offset, offset)
+		})
+	}
+
+	page := presence.Page{Number: presence.FromValue(3), Size: presence.FromValue(10)}
+	assert.Equal(t, "LIMIT 10 OFFSET 20", page.SQL(20, 100))
+}
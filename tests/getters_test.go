@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	presencegetters "github.com/pivaldi/presence/contrib/getters"
+	"github.com/pivaldi/presence/tests/gettersmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for presencegetters
+
+func TestGettersGeneratedMethods(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("set path", func(t *testing.T) {
+		user := &gettersmodel.User{
+			Name: presence.NewString("Ada"),
+			Profile: presence.FromValue(gettersmodel.Profile{
+				Bio:       presence.FromValue("Countess"),
+				Home:      &gettersmodel.Address{City: presence.FromValue("London"), Zip: "W1"},
+				UpdatedAt: updatedAt,
+			}),
+			Office: presence.FromValue(&gettersmodel.Address{City: presence.FromValue("Paris")}),
+		}
+
+		name, ok := user.GetName()
+		assert.True(t, ok)
+		assert.Equal(t, "Ada", name)
+
+		bio, ok := user.GetProfileBio()
+		assert.True(t, ok)
+		assert.Equal(t, "Countess", bio)
+
+		city, ok := user.GetProfileHomeCity()
+		assert.True(t, ok)
+		assert.Equal(t, "London", city)
+
+		zip, ok := user.GetProfileHomeZip()
+		assert.True(t, ok)
+		assert.Equal(t, "W1", zip)
+
+		at, ok := user.GetProfileUpdatedAt()
+		assert.True(t, ok)
+		assert.Equal(t, updatedAt, at)
+
+		city, ok = user.GetOfficeCity()
+		assert.True(t, ok)
+		assert.Equal(t, "Paris", city)
+
+		team := &gettersmodel.Team{Lead: user}
+		bio, ok = team.GetLeadProfileBio()
+		assert.True(t, ok)
+		assert.Equal(t, "Countess", bio)
+	})
+
+	t.Run("broken path", func(t *testing.T) {
+		for name, user := range map[string]*gettersmodel.User{
+			"nil receiver":  nil,
+			"unset profile": {},
+			"null profile":  {Profile: presence.Null[gettersmodel.Profile]()},
+			"nil home":      {Profile: presence.FromValue(gettersmodel.Profile{Bio: presence.FromValue("Countess")})},
+		} {
+			city, ok := user.GetProfileHomeCity()
+			assert.False(t, ok, name)
+			assert.Empty(t, city, name)
+
+			zip, ok := user.GetProfileHomeZip()
+			assert.False(t, ok, name)
+			assert.Empty(t, zip, name)
+		}
+
+		user := &gettersmodel.User{Office: presence.FromValue[*gettersmodel.Address](nil)}
+		_, ok := user.GetOfficeCity()
+		assert.False(t, ok, "nil pointer value")
+
+		_, ok = (&gettersmodel.Team{}).GetLeadProfileBio()
+		assert.False(t, ok)
+	})
+}
+
+func TestGettersGenerate(t *testing.T) {
+	t.Run("generated code is up to date", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, presencegetters.Generate(&out, "gettersmodel", "Team"))
+
+		committed, err := os.ReadFile(filepath.Join("gettersmodel", "presence_getters.go"))
+		require.NoError(t, err)
+		assert.Equal(t, string(committed), out.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var out bytes.Buffer
+		require.ErrorContains(t, presencegetters.Generate(&out, "gettersmodel", "Missing"), "unknown type Missing")
+		require.ErrorContains(t, presencegetters.Generate(&out, "mappermodel", "Level"), "not a struct")
+		require.ErrorIs(t, presencegetters.Generate(&out, "mappermodel"), presencegetters.ErrNoTypes)
+	})
+}
@@ -0,0 +1,39 @@
+// Package gettersmodel holds the structs whose getters presence-getters generates for
+// the tests.
+package gettersmodel
+
+import (
+	"time"
+
+	"github.com/pivaldi/presence"
+)
+
+//go:generate go run github.com/pivaldi/presence/contrib/getters/cmd/presence-getters -type Team
+
+//presence:getters
+type User struct {
+	ID      int64
+	Name    presence.String
+	Profile presence.Of[Profile]
+	Manager presence.Of[*User]
+	Office  presence.Of[*Address]
+	Work    Address
+}
+
+// Profile is reached through a presence field.
+type Profile struct {
+	Bio       presence.Of[string]
+	Home      *Address
+	UpdatedAt time.Time
+}
+
+// Address is reached through a pointer and a plain struct field.
+type Address struct {
+	City presence.Of[string]
+	Zip  string
+}
+
+// Team is named through -type.
+type Team struct {
+	Lead *User
+}
@@ -0,0 +1,375 @@
+// Code generated by presence-getters. DO NOT EDIT.
+
+package gettersmodel
+
+import (
+	"time"
+)
+
+// GetLeadID returns Lead.ID, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadID() (int64, bool) {
+	var zero int64
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.ID, true
+}
+
+// GetLeadName returns Lead.Name, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadName() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.Name.Get()
+}
+
+// GetLeadProfile returns Lead.Profile, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadProfile() (Profile, bool) {
+	var zero Profile
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.Profile.Get()
+}
+
+// GetLeadProfileBio returns Lead.Profile.Bio, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadProfileBio() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	x1, ok := v.Lead.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	return x1.Bio.Get()
+}
+
+// GetLeadProfileHomeCity returns Lead.Profile.Home.City, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadProfileHomeCity() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	x1, ok := v.Lead.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	if x1.Home == nil {
+		return zero, false
+	}
+
+	return x1.Home.City.Get()
+}
+
+// GetLeadProfileHomeZip returns Lead.Profile.Home.Zip, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadProfileHomeZip() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	x1, ok := v.Lead.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	if x1.Home == nil {
+		return zero, false
+	}
+
+	return x1.Home.Zip, true
+}
+
+// GetLeadProfileUpdatedAt returns Lead.Profile.UpdatedAt, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadProfileUpdatedAt() (time.Time, bool) {
+	var zero time.Time
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	x1, ok := v.Lead.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	return x1.UpdatedAt, true
+}
+
+// GetLeadManager returns Lead.Manager, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadManager() (*User, bool) {
+	var zero *User
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.Manager.Get()
+}
+
+// GetLeadOffice returns Lead.Office, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadOffice() (*Address, bool) {
+	var zero *Address
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.Office.Get()
+}
+
+// GetLeadOfficeCity returns Lead.Office.City, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadOfficeCity() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	x1, ok := v.Lead.Office.Get()
+	if !ok || x1 == nil {
+		return zero, false
+	}
+
+	return x1.City.Get()
+}
+
+// GetLeadOfficeZip returns Lead.Office.Zip, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadOfficeZip() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	x1, ok := v.Lead.Office.Get()
+	if !ok || x1 == nil {
+		return zero, false
+	}
+
+	return x1.Zip, true
+}
+
+// GetLeadWorkCity returns Lead.Work.City, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadWorkCity() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.Work.City.Get()
+}
+
+// GetLeadWorkZip returns Lead.Work.Zip, false when it or a field on its path is nil, null or unset.
+func (v *Team) GetLeadWorkZip() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	if v.Lead == nil {
+		return zero, false
+	}
+
+	return v.Lead.Work.Zip, true
+}
+
+// GetName returns Name, false when it or a field on its path is nil, null or unset.
+func (v *User) GetName() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	return v.Name.Get()
+}
+
+// GetProfile returns Profile, false when it or a field on its path is nil, null or unset.
+func (v *User) GetProfile() (Profile, bool) {
+	var zero Profile
+	if v == nil {
+		return zero, false
+	}
+
+	return v.Profile.Get()
+}
+
+// GetProfileBio returns Profile.Bio, false when it or a field on its path is nil, null or unset.
+func (v *User) GetProfileBio() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	x0, ok := v.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	return x0.Bio.Get()
+}
+
+// GetProfileHomeCity returns Profile.Home.City, false when it or a field on its path is nil, null or unset.
+func (v *User) GetProfileHomeCity() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	x0, ok := v.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	if x0.Home == nil {
+		return zero, false
+	}
+
+	return x0.Home.City.Get()
+}
+
+// GetProfileHomeZip returns Profile.Home.Zip, false when it or a field on its path is nil, null or unset.
+func (v *User) GetProfileHomeZip() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	x0, ok := v.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	if x0.Home == nil {
+		return zero, false
+	}
+
+	return x0.Home.Zip, true
+}
+
+// GetProfileUpdatedAt returns Profile.UpdatedAt, false when it or a field on its path is nil, null or unset.
+func (v *User) GetProfileUpdatedAt() (time.Time, bool) {
+	var zero time.Time
+	if v == nil {
+		return zero, false
+	}
+
+	x0, ok := v.Profile.Get()
+	if !ok {
+		return zero, false
+	}
+
+	return x0.UpdatedAt, true
+}
+
+// GetManager returns Manager, false when it or a field on its path is nil, null or unset.
+func (v *User) GetManager() (*User, bool) {
+	var zero *User
+	if v == nil {
+		return zero, false
+	}
+
+	return v.Manager.Get()
+}
+
+// GetOffice returns Office, false when it or a field on its path is nil, null or unset.
+func (v *User) GetOffice() (*Address, bool) {
+	var zero *Address
+	if v == nil {
+		return zero, false
+	}
+
+	return v.Office.Get()
+}
+
+// GetOfficeCity returns Office.City, false when it or a field on its path is nil, null or unset.
+func (v *User) GetOfficeCity() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	x0, ok := v.Office.Get()
+	if !ok || x0 == nil {
+		return zero, false
+	}
+
+	return x0.City.Get()
+}
+
+// GetOfficeZip returns Office.Zip, false when it or a field on its path is nil, null or unset.
+func (v *User) GetOfficeZip() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	x0, ok := v.Office.Get()
+	if !ok || x0 == nil {
+		return zero, false
+	}
+
+	return x0.Zip, true
+}
+
+// GetWorkCity returns Work.City, false when it or a field on its path is nil, null or unset.
+func (v *User) GetWorkCity() (string, bool) {
+	var zero string
+	if v == nil {
+		return zero, false
+	}
+
+	return v.Work.City.Get()
+}
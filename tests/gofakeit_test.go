@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/pivaldi/presence"
+	presencegofakeit "github.com/pivaldi/presence/contrib/gofakeit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAddress struct {
+	City presence.Of[string] `fake:"{city}"`
+}
+
+type fakeUser struct {
+	ID        int
+	Email     presence.Of[string] `fake:"{email}"`
+	Age       presence.Of[int]    `fake:"{number:18,99}"`
+	Address   presence.Of[fakeAddress]
+	Previous  []fakeAddress
+	Reference *fakeAddress
+	Note      presence.Of[string] `fake:"skip"`
+}
+
+// Tests for presencegofakeit
+
+func TestGofakeitStructStates(t *testing.T) {
+	faker := presencegofakeit.New(gofakeit.New(42))
+	counts := map[presence.State]int{}
+
+	for range 300 {
+		var u fakeUser
+		require.NoError(t, faker.Struct(&u))
+		counts[u.Email.State()]++
+
+		assert.Equal(t, presence.StateUnset, u.Note.State(), "fake:\"skip\" fields are left untouched")
+		assert.NotNil(t, u.Reference)
+		assert.NotEmpty(t, u.Previous)
+
+		if email, ok := u.Email.Get(); ok {
+			assert.Contains(t, email, "@")
+		}
+
+		if age, ok := u.Age.Get(); ok {
+			assert.GreaterOrEqual(t, age, 18)
+			assert.LessOrEqual(t, age, 99)
+		}
+
+		if address, ok := u.Address.Get(); ok && address.City.IsValue() {
+			assert.NotEmpty(t, address.City.MustGet())
+		}
+	}
+
+	for _, state := range []presence.State{presence.StateUnset, presence.StateNull, presence.StateValue} {
+		assert.Greater(t, counts[state], 50, "state %s", state)
+	}
+}
+
+func TestGofakeitRatios(t *testing.T) {
+	faker := presencegofakeit.New(gofakeit.New(7), presencegofakeit.WithRatios(0, 1, 0))
+
+	for range 20 {
+		var u fakeUser
+		require.NoError(t, faker.Struct(&u))
+		assert.True(t, u.Email.IsNull())
+	}
+
+	faker = presencegofakeit.New(gofakeit.New(7), presencegofakeit.WithRatios(0, 0, 1))
+	n, err := presencegofakeit.Of[string](faker, "{email}")
+	require.NoError(t, err)
+	assert.Contains(t, n.MustGet(), "@")
+}
+
+func TestGofakeitErrors(t *testing.T) {
+	faker := presencegofakeit.New(nil)
+	require.Error(t, faker.Struct(fakeUser{}))
+
+	var bad struct {
+		Email presence.Of[int] `fake:"{nosuchfunction}"`
+	}
+	require.Error(t, presencegofakeit.New(nil, presencegofakeit.WithRatios(0, 0, 1)).Struct(&bad))
+}
@@ -0,0 +1,509 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pivaldi/presence"
+	presencegorm "github.com/pivaldi/presence/contrib/gorm"
+	"github.com/pivaldi/presence/naming"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	gormtests "gorm.io/gorm/utils/tests"
+)
+
+// buildExpr renders a clause expression with a dummy dialect.
+func buildExpr(t *testing.T, expr clause.Expression) (string, []any) {
+	t.Helper()
+
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	stmt := &gorm.Statement{DB: db}
+	expr.Build(stmt)
+
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestGormGenExpressions(t *testing.T) {
+	email := field.NewString("users", "email")
+
+	t.Run("Eq on value", func(t *testing.T) {
+		sql, vars := buildExpr(t, presencegorm.Eq(email, presence.FromValue("a@b.c")))
+		assert.Equal(t, "`users`.`email` = ?", sql)
+		assert.Equal(t, []any{"a@b.c"}, vars)
+	})
+
+	t.Run("Eq on null is IS NULL", func(t *testing.T) {
+		sql, vars := buildExpr(t, presencegorm.Eq(email, presence.Null[string]()))
+		assert.Equal(t, "`users`.`email` IS NULL", sql)
+		assert.Empty(t, vars)
+	})
+
+	t.Run("Eq on unset is skipped", func(t *testing.T) {
+		assert.Nil(t, presencegorm.Eq(email, presence.Of[string]{}))
+	})
+
+	t.Run("Neq on null is IS NOT NULL", func(t *testing.T) {
+		sql, _ := buildExpr(t, presencegorm.Neq(field.NewInt64("users", "age"), presence.Null[int64]()))
+		assert.Equal(t, "`users`.`age` IS NOT NULL", sql)
+	})
+
+	t.Run("Is on bool field", func(t *testing.T) {
+		sql, vars := buildExpr(t, presencegorm.Is(field.NewBool("users", "active"), presence.FromValue(true)))
+		assert.Equal(t, "`users`.`active` = ?", sql)
+		assert.Equal(t, []any{true}, vars)
+	})
+
+	t.Run("EqField on generic field", func(t *testing.T) {
+		sql, _ := buildExpr(t, presencegorm.EqField(field.NewField("users", "bio"), presence.Null[string]()))
+		assert.Equal(t, "`users`.`bio` IS NULL", sql)
+	})
+
+	t.Run("Conds drops unset values", func(t *testing.T) {
+		conds := presencegorm.Conds(
+			presencegorm.Eq(email, presence.Of[string]{}),
+			presencegorm.Eq(email, presence.FromValue("x")),
+		)
+		assert.Len(t, conds, 1)
+	})
+}
+
+// Tests for the OmitUnset plugin
+
+type gormAccount struct {
+	ID    int64 `gorm:"primaryKey"`
+	Name  presence.Of[string]
+	Email presence.String
+	Age   presence.Of[int64]
+}
+
+func TestGormOmitUnsetPlugin(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(presencegorm.OmitUnset{}))
+
+	t.Run("Save skips unset fields", func(t *testing.T) {
+		account := gormAccount{ID: 1, Name: presence.FromValue("Ada"), Age: presence.Null[int64]()}
+		stmt := db.Save(&account).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t, "UPDATE `gorm_accounts` SET `name`=?,`age`=? WHERE `id` = ?", stmt.SQL.String())
+		assert.Equal(t, []any{account.Name, account.Age, int64(1)}, stmt.Vars)
+	})
+
+	t.Run("Updates from another struct type", func(t *testing.T) {
+		type patch struct {
+			Name  presence.Of[string]
+			Email presence.String
+		}
+
+		stmt := db.Model(&gormAccount{ID: 1}).Updates(&patch{Email: presence.NewString("a@b.c")}).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t, "UPDATE `gorm_accounts` SET `email`=? WHERE `id` = ?", stmt.SQL.String())
+	})
+
+	t.Run("Create skips unset fields", func(t *testing.T) {
+		stmt := db.Create(&gormAccount{ID: 2, Name: presence.FromValue("Bob")}).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t, "INSERT INTO `gorm_accounts` (`name`,`id`) VALUES (?,?) RETURNING `id`", stmt.SQL.String())
+	})
+
+	t.Run("Without plugin Save writes every column", func(t *testing.T) {
+		plain, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+		require.NoError(t, err)
+
+		stmt := plain.Save(&gormAccount{ID: 1, Name: presence.FromValue("Ada")}).Statement
+		assert.Equal(t, "UPDATE `gorm_accounts` SET `name`=?,`email`=?,`age`=? WHERE `id` = ?", stmt.SQL.String())
+	})
+}
+
+func TestGormCreateFromInsert(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	ins, err := presence.NewInsert(gormAccount{Name: presence.FromValue("Ada"), Age: presence.Null[int64]()},
+		presence.WithTag(""), presence.WithNaming(naming.Snake))
+	require.NoError(t, err)
+
+	stmt := db.Table("gorm_accounts").Create(ins.Map()).Statement
+	require.NoError(t, stmt.Error)
+	assert.Equal(t, "INSERT INTO `gorm_accounts` (`age`,`name`) VALUES (?,?)", stmt.SQL.String(),
+		"the zero primary key is left to the database")
+
+	ins, err = presence.NewInsert(gormAccount{ID: 7, Name: presence.FromValue("Ada")},
+		presence.WithTag(""), presence.WithNaming(naming.Snake))
+	require.NoError(t, err)
+
+	stmt = db.Table("gorm_accounts").Create(ins.Map()).Statement
+	require.NoError(t, stmt.Error)
+	assert.Equal(t, "INSERT INTO `gorm_accounts` (`id`,`name`) VALUES (?,?)", stmt.SQL.String())
+}
+
+// Tests for DeletedAt
+
+type gormPost struct {
+	ID        int64                  `gorm:"primaryKey" json:"id"`
+	DeletedAt presencegorm.DeletedAt `json:"deleted_at,omitzero"`
+}
+
+func TestGormDeletedAt(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	t.Run("Queries are scoped to non deleted rows", func(t *testing.T) {
+		stmt := db.Find(&[]gormPost{}).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t, "SELECT * FROM `gorm_posts` WHERE `gorm_posts`.`deleted_at` IS NULL", stmt.SQL.String())
+	})
+
+	t.Run("Unscoped queries every row", func(t *testing.T) {
+		stmt := db.Unscoped().Find(&[]gormPost{}).Statement
+		assert.Equal(t, "SELECT * FROM `gorm_posts`", stmt.SQL.String())
+	})
+
+	t.Run("Delete sets the deletion time", func(t *testing.T) {
+		post := gormPost{ID: 1}
+		stmt := db.Delete(&post).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t,
+			"UPDATE `gorm_posts` SET `deleted_at`=? WHERE `gorm_posts`.`id` = ? AND `gorm_posts`.`deleted_at` IS NULL",
+			stmt.SQL.String())
+		assert.True(t, post.DeletedAt.IsValue())
+	})
+
+	t.Run("JSON keeps the three states", func(t *testing.T) {
+		data, err := json.Marshal(gormPost{ID: 1})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1}`, string(data))
+
+		post := gormPost{ID: 1}
+		post.DeletedAt.SetNull()
+		data, err = json.Marshal(post)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"deleted_at":null}`, string(data))
+
+		deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		post.DeletedAt.SetValue(deletedAt)
+		data, err = json.Marshal(post)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"deleted_at":"2024-01-02T03:04:05Z"}`, string(data))
+
+		var decoded gormPost
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, decoded.DeletedAt.MustGet().Equal(deletedAt))
+	})
+}
+
+// Tests for UpdateVersioned
+
+type gormDocument struct {
+	ID      int64 `gorm:"primaryKey"`
+	Title   presence.Of[string]
+	Version int64
+}
+
+func TestGormUpdateVersioned(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	type patch struct {
+		Title   presence.Of[string]
+		Version int64 `presence:"version"`
+	}
+
+	t.Run("guards the update with the version", func(t *testing.T) {
+		tx := presencegorm.UpdateVersioned(db.Model(&gormDocument{ID: 1}),
+			patch{Title: presence.FromValue("v2"), Version: 3})
+		require.NoError(t, tx.Error)
+		assert.Equal(t,
+			"UPDATE `gorm_documents` SET `title`=?,`version`=`version` + 1 WHERE `version` = ? AND `id` = ?",
+			tx.Statement.SQL.String())
+		assert.Equal(t, []any{"v2", int64(3), int64(1)}, tx.Statement.Vars)
+	})
+
+	t.Run("presence version fields", func(t *testing.T) {
+		type presencePatch struct {
+			Title   presence.Of[string]
+			Version presence.Of[int64] `presence:"version"`
+		}
+
+		tx := presencegorm.UpdateVersioned(db.Model(&gormDocument{ID: 1}),
+			presencePatch{Title: presence.FromValue("v2"), Version: presence.FromValue(int64(3))})
+		require.NoError(t, tx.Error)
+		assert.Equal(t, []any{"v2", int64(3), int64(1)}, tx.Statement.Vars)
+
+		tx = presencegorm.UpdateVersioned(db.Model(&gormDocument{ID: 1}), presencePatch{Title: presence.FromValue("v2")})
+		require.Error(t, tx.Error)
+	})
+
+	t.Run("reports invalid patches", func(t *testing.T) {
+		tx := presencegorm.UpdateVersioned(db.Model(&gormDocument{ID: 1}), gormAccount{})
+		require.Error(t, tx.Error)
+	})
+}
+
+func TestGormToAssignmentColumns(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	type upsert struct {
+		ID      int64
+		Title   presence.Of[string]
+		Version presence.Of[int64]
+	}
+
+	set, err := presencegorm.ToAssignmentColumns(db, upsert{Title: presence.FromValue("v2")})
+	require.NoError(t, err)
+
+	tx := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoUpdates: set}).
+		Create(&gormDocument{ID: 1, Title: presence.FromValue("v2")})
+	require.NoError(t, tx.Error)
+	assert.Contains(t, tx.Statement.SQL.String(), "ON CONFLICT (`id`) DO UPDATE SET `title`=`excluded`.`title`")
+	assert.NotContains(t, tx.Statement.SQL.String(), "`version`=")
+
+	set, err = presencegorm.ToAssignmentColumns(db,
+		upsert{Title: presence.Null[string](), Version: presence.FromValue(int64(2))})
+	require.NoError(t, err)
+	assert.Equal(t, clause.AssignmentColumns([]string{"title", "version"}), set)
+
+	_, err = presencegorm.ToAssignmentColumns(db, 42)
+	require.Error(t, err)
+}
+
+type gormAuthor struct {
+	ID   uuid.UUID `gorm:"primaryKey"`
+	Name string
+}
+
+type gormArticle struct {
+	ID       int64 `gorm:"primaryKey"`
+	Title    presence.Of[string]
+	AuthorID presence.Of[uuid.UUID] `gorm:"type:uuid"`
+	Author   *gormAuthor
+}
+
+func TestGormUpdateBelongsTo(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	type patch struct {
+		Title    presence.Of[string]
+		AuthorID presence.Of[uuid.UUID]
+	}
+
+	ada, grace := uuid.New(), uuid.New()
+	loaded := func() *gormArticle {
+		return &gormArticle{ID: 1, AuthorID: presence.FromValue(ada), Author: &gormAuthor{ID: ada, Name: "Ada"}}
+	}
+
+	t.Run("null clears the association", func(t *testing.T) {
+		post := loaded()
+		tx := presencegorm.UpdateBelongsTo(db.Model(post), patch{AuthorID: presence.Null[uuid.UUID]()})
+		require.NoError(t, tx.Error)
+		assert.Equal(t, "UPDATE `gorm_articles` SET `author_id`=? WHERE `id` = ?", tx.Statement.SQL.String())
+		assert.Equal(t, []any{nil, int64(1)}, tx.Statement.Vars)
+		assert.Nil(t, post.Author)
+	})
+
+	t.Run("unset leaves the association", func(t *testing.T) {
+		post := loaded()
+		tx := presencegorm.UpdateBelongsTo(db.Model(post), patch{Title: presence.FromValue("v2")})
+		require.NoError(t, tx.Error)
+		assert.Equal(t, "UPDATE `gorm_articles` SET `title`=? WHERE `id` = ?", tx.Statement.SQL.String())
+		assert.Equal(t, &gormAuthor{ID: ada, Name: "Ada"}, post.Author)
+	})
+
+	t.Run("value replaces another association", func(t *testing.T) {
+		post := loaded()
+		tx := presencegorm.UpdateBelongsTo(db.Model(post), patch{AuthorID: presence.FromValue(grace)})
+		require.NoError(t, tx.Error)
+		assert.Equal(t, []any{grace, int64(1)}, tx.Statement.Vars)
+		assert.Nil(t, post.Author)
+
+		post = loaded()
+		tx = presencegorm.UpdateBelongsTo(db.Model(post), patch{AuthorID: presence.FromValue(ada)})
+		require.NoError(t, tx.Error)
+		assert.NotNil(t, post.Author, "the loaded association still matches")
+	})
+
+	t.Run("empty patch", func(t *testing.T) {
+		tx := presencegorm.UpdateBelongsTo(db.Model(loaded()), patch{})
+		require.NoError(t, tx.Error)
+		assert.Empty(t, tx.Statement.SQL.String())
+	})
+
+	t.Run("reports invalid patches", func(t *testing.T) {
+		tx := presencegorm.UpdateBelongsTo(db.Model(loaded()), 42)
+		require.Error(t, tx.Error)
+	})
+}
+
+type gormAddress struct {
+	City presence.Of[string]
+	Zip  presence.String
+}
+
+type gormCustomer struct {
+	ID       int64 `gorm:"primaryKey"`
+	Name     presence.Of[string]
+	Address  gormAddress         `gorm:"embedded;embeddedPrefix:addr_"`
+	Billing  *gormAddress        `gorm:"embedded;embeddedPrefix:bill_"`
+	Internal presence.Of[string] `presence:"apionly"`
+}
+
+func TestGormEmbedded(t *testing.T) {
+	db, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	customer := gormCustomer{
+		ID:       1,
+		Name:     presence.FromValue("Ada"),
+		Address:  gormAddress{City: presence.Null[string](), Zip: presence.NewString("75001")},
+		Internal: presence.FromValue("x"),
+	}
+
+	t.Run("ToMap prefixes embedded columns", func(t *testing.T) {
+		m, err := presencegorm.ToMap(db, &customer)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Ada", "addr_city": nil, "addr_zip": "75001"}, m)
+
+		withBilling := customer
+		withBilling.Billing = &gormAddress{City: presence.FromValue("Lyon")}
+		m, err = presencegorm.ToMap(db, withBilling)
+		require.NoError(t, err)
+		assert.Equal(t, "Lyon", m["bill_city"])
+		assert.NotContains(t, m, "bill_zip")
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		tx := presencegorm.Updates(db.Model(&gormCustomer{ID: 1}), gormCustomer{Address: customer.Address})
+		require.NoError(t, tx.Error)
+		assert.Equal(t, "UPDATE `gorm_customers` SET `addr_city`=?,`addr_zip`=? WHERE `id` = ?",
+			tx.Statement.SQL.String())
+		assert.Equal(t, []any{nil, "75001", int64(1)}, tx.Statement.Vars)
+
+		tx = presencegorm.Updates(db.Model(&gormCustomer{ID: 1}), gormCustomer{})
+		require.NoError(t, tx.Error)
+		assert.Empty(t, tx.Statement.SQL.String())
+
+		tx = presencegorm.Updates(db.Model(&gormCustomer{ID: 1}), 42)
+		require.Error(t, tx.Error)
+	})
+
+	t.Run("update builders", func(t *testing.T) {
+		patch := gormCustomer{Address: customer.Address}
+
+		set, err := presencegorm.ToAssignmentColumns(db, patch)
+		require.NoError(t, err)
+		assert.Equal(t, clause.AssignmentColumns([]string{"addr_city", "addr_zip"}), set)
+
+		type versioned struct {
+			Address gormAddress `gorm:"embedded;embeddedPrefix:addr_"`
+			Version int64       `presence:"version"`
+		}
+
+		tx := presencegorm.UpdateVersioned(db.Model(&gormCustomer{ID: 1}), versioned{Address: customer.Address, Version: 2})
+		require.NoError(t, tx.Error)
+		assert.Equal(t,
+			"UPDATE `gorm_customers` SET `addr_city`=?,`addr_zip`=?,`version`=`version` + 1 WHERE `version` = ? AND `id` = ?",
+			tx.Statement.SQL.String())
+
+		tx = presencegorm.UpdateBelongsTo(db.Model(&gormCustomer{ID: 1}), patch)
+		require.NoError(t, tx.Error)
+		assert.Equal(t, "UPDATE `gorm_customers` SET `addr_city`=?,`addr_zip`=? WHERE `id` = ?",
+			tx.Statement.SQL.String())
+	})
+
+	t.Run("criteria", func(t *testing.T) {
+		m, err := presencegorm.ToMap(db, gormCustomer{Address: gormAddress{City: presence.Null[string]()}})
+		require.NoError(t, err)
+
+		var found []gormCustomer
+		tx := db.Where(m).Find(&found)
+		require.NoError(t, tx.Error)
+		assert.Equal(t, "SELECT * FROM `gorm_customers` WHERE `addr_city` IS NULL", tx.Statement.SQL.String())
+	})
+
+	t.Run("OmitUnset plugin", func(t *testing.T) {
+		plugged, err := gorm.Open(gormtests.DummyDialector{}, &gorm.Config{DryRun: true})
+		require.NoError(t, err)
+		require.NoError(t, plugged.Use(presencegorm.OmitUnset{}))
+
+		stmt := plugged.Model(&gormCustomer{ID: 1}).Updates(&gormCustomer{Address: customer.Address}).Statement
+		require.NoError(t, stmt.Error)
+		assert.Equal(t, "UPDATE `gorm_customers` SET `addr_city`=?,`addr_zip`=? WHERE `id` = ?", stmt.SQL.String())
+	})
+
+	t.Run("FieldGenType", func(t *testing.T) {
+		sch, err := schema.Parse(&gormCustomer{}, &sync.Map{}, db.NamingStrategy)
+		require.NoError(t, err)
+
+		types := map[string]string{}
+		for _, f := range sch.Fields {
+			types[f.DBName] = presencegorm.FieldGenType(f)
+		}
+
+		assert.Equal(t, map[string]string{
+			"id": "", "name": "String", "addr_city": "String", "addr_zip": "String",
+			"bill_city": "String", "bill_zip": "String", "internal": "String",
+		}, types)
+	})
+}
+
+// genField builds a generated model field; gen.Field points to an internal type.
+func genField(typ string, gormTag field.GormTag) gen.Field {
+	f := reflect.New(reflect.TypeFor[gen.Field]().Elem())
+	f.Elem().FieldByName("Type").SetString(typ)
+	f.Elem().FieldByName("Tag").Set(reflect.ValueOf(field.Tag{"json": "x"}))
+	f.Elem().FieldByName("GORMTag").Set(reflect.ValueOf(gormTag))
+
+	return f.Interface().(gen.Field)
+}
+
+// applyModelOpt runs a gen field modifier option on f.
+func applyModelOpt(t *testing.T, opt gen.ModelOpt, f gen.Field) gen.Field {
+	t.Helper()
+
+	out := reflect.ValueOf(opt).Call([]reflect.Value{reflect.ValueOf(f)})
+	require.Len(t, out, 1)
+
+	return out[0].Interface().(gen.Field)
+}
+
+// Tests for JSONSchemaTags
+
+func TestGormGenJSONSchemaTags(t *testing.T) {
+	cases := []struct {
+		name  string
+		field gen.Field
+		want  string
+	}{
+		{"nullable presence string", genField("presence.Of[string]", field.GormTag{}), "type=string,nullable"},
+		{"nullable presence time", genField("presence.Of[time.Time]", field.GormTag{}),
+			"type=string,format=date-time,nullable"},
+		{"nullable presence uuid", genField("presence.Of[uuid.UUID]", field.GormTag{}),
+			"type=string,format=uuid,nullable"},
+		{"not null without default", genField("int64", field.GormTag{"not null": nil}), "required"},
+		{"not null with default", genField("bool", field.GormTag{"not null": nil, "default": {"true"}}), ""},
+		{"primary key", genField("int64", field.GormTag{"primaryKey": nil}), ""},
+		{"nullable plain pointer", genField("*float64", field.GormTag{}), "nullable"},
+		{"presence JSON type", genField("presence.Of[Metadata]", field.GormTag{}), "nullable"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := applyModelOpt(t, presencegorm.JSONSchemaTags(), tc.field)
+			assert.Equal(t, tc.want, f.Tag["jsonschema"])
+			assert.Equal(t, "x", f.Tag["json"])
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"hash/fnv"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hashOf[T any](t *testing.T, n presence.Of[T]) uint64 {
+	t.Helper()
+
+	h := fnv.New64a()
+	require.NoError(t, presence.Hash(n, h))
+
+	return h.Sum64()
+}
+
+func hashStructOf(t *testing.T, v any, opts ...presence.Option) uint64 {
+	t.Helper()
+
+	h := fnv.New64a()
+	require.NoError(t, presence.HashStruct(v, h, opts...))
+
+	return h.Sum64()
+}
+
+// Tests for Hash
+
+func TestHash(t *testing.T) {
+	t.Run("states differ", func(t *testing.T) {
+		unset, null, zero := hashOf(t, presence.Of[string]{}), hashOf(t, presence.Null[string]()),
+			hashOf(t, presence.FromValue(""))
+		assert.NotEqual(t, unset, null)
+		assert.NotEqual(t, null, zero)
+		assert.NotEqual(t, unset, zero)
+	})
+
+	t.Run("equal values hash alike", func(t *testing.T) {
+		assert.Equal(t, hashOf(t, presence.FromValue("a")), hashOf(t, presence.FromValue("a")))
+		assert.NotEqual(t, hashOf(t, presence.FromValue("a")), hashOf(t, presence.FromValue("b")))
+		assert.NotEqual(t, hashOf(t, presence.FromValue(1)), hashOf(t, presence.FromValue(2)))
+		assert.NotEqual(t, hashOf(t, presence.FromValue(1.5)), hashOf(t, presence.FromValue(math.Inf(1))))
+		assert.NotEqual(t, hashOf(t, presence.FromValue(true)), hashOf(t, presence.FromValue(false)))
+		assert.NotEqual(t, hashOf(t, presence.FromValue(uuid.New())), hashOf(t, presence.FromValue(uuid.New())))
+		assert.NotEqual(t, hashOf(t, presence.FromValue([]byte("a"))), hashOf(t, presence.FromValue([]byte("b"))))
+	})
+
+	t.Run("times are instants", func(t *testing.T) {
+		at := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+		assert.Equal(t, hashOf(t, presence.FromValue(at)), hashOf(t, presence.FromValue(at.In(time.FixedZone("X", 3600)))))
+		assert.NotEqual(t, hashOf(t, presence.FromValue(at)), hashOf(t, presence.FromValue(at.Add(time.Microsecond))))
+	})
+
+	t.Run("types held by Of[any] do not collide", func(t *testing.T) {
+		assert.NotEqual(t, hashOf(t, presence.FromValue[any]("1")), hashOf(t, presence.FromValue[any](1)))
+	})
+
+	t.Run("other values as canonical JSON", func(t *testing.T) {
+		a := hashOf(t, presence.FromValue(map[string]any{"x": 1, "y": []int{1, 2}}))
+		b := hashOf(t, presence.FromValue(map[string]any{"y": []int{1, 2}, "x": 1}))
+		assert.Equal(t, a, b)
+		assert.NotEqual(t, a, hashOf(t, presence.FromValue(map[string]any{"x": 1})))
+		assert.NotEqual(t, hashOf(t, presence.FromValue(userID(1))), hashOf(t, presence.FromValue(userID(2))))
+	})
+
+	t.Run("unencodable values", func(t *testing.T) {
+		require.Error(t, presence.Hash(presence.FromValue(make(chan int)), fnv.New64a()))
+	})
+}
+
+// Tests for HashStruct
+
+func TestHashStruct(t *testing.T) {
+	type base struct {
+		ID int64 `json:"id"`
+	}
+
+	type patch struct {
+		base
+		Name presence.Of[string] `json:"name"`
+		Bio  presence.String     `json:"bio"`
+	}
+
+	t.Run("fingerprints state and values", func(t *testing.T) {
+		a := patch{base: base{ID: 1}, Name: presence.FromValue("Ada")}
+		assert.Equal(t, hashStructOf(t, a), hashStructOf(t, &a))
+
+		b := a
+		b.Name = presence.Null[string]()
+		assert.NotEqual(t, hashStructOf(t, a), hashStructOf(t, b))
+
+		c := a
+		c.ID = 2
+		assert.NotEqual(t, hashStructOf(t, a), hashStructOf(t, c))
+
+		d := a
+		d.Bio = presence.NewString("")
+		assert.NotEqual(t, hashStructOf(t, a), hashStructOf(t, d))
+	})
+
+	t.Run("moving a value between fields changes the hash", func(t *testing.T) {
+		type pair struct {
+			A presence.Of[string]
+			B presence.Of[string]
+		}
+
+		assert.NotEqual(t,
+			hashStructOf(t, pair{A: presence.FromValue("x")}),
+			hashStructOf(t, pair{B: presence.FromValue("x")}))
+	})
+
+	t.Run("field names follow the options", func(t *testing.T) {
+		a := patch{Name: presence.FromValue("Ada")}
+		assert.NotEqual(t, hashStructOf(t, a), hashStructOf(t, a, presence.WithTag("db")))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.Error(t, presence.HashStruct("plain", fnv.New64a()))
+
+		type withChan struct {
+			C chan int
+		}
+
+		require.ErrorContains(t, presence.HashStruct(withChan{C: make(chan int)}, fnv.New64a()), "field C")
+	})
+}
+
+// Tests for CacheKey
+
+func TestCacheKey(t *testing.T) {
+	type listUsers struct {
+		Name   presence.Of[string] `json:"name"`
+		MinAge presence.Of[int]    `json:"min_age"`
+		Limit  int                 `json:"limit"`
+	}
+
+	type listUsersV2 struct {
+		Limit  int                   `json:"limit"`
+		Active presence.Of[bool]     `json:"active"`
+		MinAge presence.Of[int]      `json:"min_age"`
+		Name   presence.Of[string]   `json:"name"`
+		Tags   presence.Of[[]string] `json:"tags"`
+	}
+
+	key := func(v any) string {
+		t.Helper()
+
+		k, err := presence.CacheKey(v)
+		require.NoError(t, err)
+
+		return k
+	}
+
+	base := key(listUsers{Name: presence.FromValue("ada"), Limit: 10})
+	assert.Len(t, base, 64)
+	assert.Equal(t, base, key(&listUsers{Name: presence.FromValue("ada"), Limit: 10}), "stable")
+	assert.Equal(t, base, key(listUsersV2{Name: presence.FromValue("ada"), Limit: 10}),
+		"independent of the field order and of the unset fields")
+
+	assert.NotEqual(t, base, key(listUsers{Name: presence.FromValue("ada"), Limit: 20}))
+	assert.NotEqual(t, base, key(listUsers{Name: presence.FromValue("grace"), Limit: 10}))
+	assert.NotEqual(t, base, key(listUsers{Name: presence.FromValue("ada"), MinAge: presence.Null[int](), Limit: 10}),
+		"null differs from unset")
+	assert.NotEqual(t, key(listUsersV2{Tags: presence.FromValue([]string{"a", "b"})}),
+		key(listUsersV2{Tags: presence.FromValue([]string{"b", "a"})}))
+
+	_, err := presence.CacheKey(42)
+	require.Error(t, err)
+}
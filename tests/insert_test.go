@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type insertUser struct {
+	Email   string              `db:"email"`
+	Name    presence.Of[string] `db:"name"`
+	Country presence.Of[string] `db:"country"`
+	Bio     presence.String     `db:"bio"`
+	Secret  string              `db:"-"`
+}
+
+// Tests for NewInsert
+
+func TestNewInsert(t *testing.T) {
+	t.Run("unset fields are left out, null ones inserted", func(t *testing.T) {
+		row := insertUser{Email: "ada@example.com", Name: presence.FromValue("Ada"), Bio: presence.String{Of: presence.Null[string]()}}
+
+		ins, err := presence.NewInsert(&row, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"email", "name", "bio"}, ins.Columns)
+		assert.Equal(t, []any{"ada@example.com", row.Name, row.Bio}, ins.Values)
+
+		query, args := ins.SQL("users")
+		assert.Equal(t, "INSERT INTO users (email, name, bio) VALUES (?, ?, ?)", query)
+		assert.Equal(t, ins.Values, args)
+
+		v, err := args[2].(presence.String).Value()
+		require.NoError(t, err)
+		assert.Nil(t, v, "null is inserted as NULL")
+
+		assert.Equal(t, map[string]any{"email": "ada@example.com", "name": row.Name, "bio": row.Bio}, ins.Map())
+	})
+
+	t.Run("default values", func(t *testing.T) {
+		type counter struct {
+			Hits presence.Of[int] `db:"hits"`
+		}
+
+		ins, err := presence.NewInsert(counter{}, presence.WithTag("db"))
+		require.NoError(t, err)
+
+		query, args := ins.SQL("counters")
+		assert.Equal(t, "INSERT INTO counters DEFAULT VALUES", query)
+		assert.Empty(t, args)
+		assert.Empty(t, ins.Map())
+	})
+
+	t.Run("zero generated columns are left out", func(t *testing.T) {
+		type order struct {
+			ID     int64               `db:"id" presence:"auto"`
+			Number int64               `db:"number" gorm:"autoIncrement"`
+			Total  int64               `db:"total" gorm:"autoIncrement:false"`
+			Note   presence.Of[string] `db:"note"`
+		}
+
+		ins, err := presence.NewInsert(order{}, presence.WithTag("db"))
+		require.NoError(t, err)
+
+		query, args := ins.SQL("orders")
+		assert.Equal(t, "INSERT INTO orders (total) VALUES (?)", query)
+		assert.Equal(t, []any{int64(0)}, args, "plain fields override the column default")
+
+		ins, err = presence.NewInsert(order{ID: 7, Number: 12}, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "number", "total"}, ins.Columns)
+	})
+
+	t.Run("the Default sentinel is written as DEFAULT", func(t *testing.T) {
+		type account struct {
+			Email string `db:"email"`
+			Plan  any    `db:"plan"`
+		}
+
+		ins, err := presence.NewInsert(account{Email: "ada@example.com", Plan: presence.Default}, presence.WithTag("db"))
+		require.NoError(t, err)
+
+		query, args := ins.SQL("accounts")
+		assert.Equal(t, "INSERT INTO accounts (email, plan) VALUES (?, DEFAULT)", query)
+		assert.Equal(t, []any{"ada@example.com"}, args)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := presence.NewInsert(42)
+		require.Error(t, err)
+	})
+}
+
+// Tests for BuildUpsert
+
+func TestBuildUpsert(t *testing.T) {
+	type upsertUser struct {
+		Tenant int64               `db:"tenant" presence:"conflict"`
+		Email  presence.Of[string] `db:"email" presence:"conflict"`
+		Name   presence.Of[string] `db:"name"`
+		Bio    presence.Of[string] `db:"bio"`
+	}
+
+	t.Run("set fields are updated", func(t *testing.T) {
+		row := upsertUser{Tenant: 1, Email: presence.FromValue("ada@example.com"), Name: presence.FromValue("Ada"),
+			Bio: presence.Null[string]()}
+
+		query, args, err := presence.BuildUpsert("users", row, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO users (tenant, email, name, bio) VALUES (?, ?, ?, ?)"+
+			" ON CONFLICT (tenant, email) DO UPDATE SET name = EXCLUDED.name, bio = EXCLUDED.bio", query)
+		assert.Equal(t, []any{int64(1), row.Email, row.Name, row.Bio}, args)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		row := upsertUser{Tenant: 1, Email: presence.FromValue("ada@example.com")}
+
+		query, _, err := presence.BuildUpsert("users", &row, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO users (tenant, email) VALUES (?, ?) ON CONFLICT (tenant, email) DO NOTHING", query)
+	})
+
+	t.Run("DEFAULT columns are not updated", func(t *testing.T) {
+		type upsertAccount struct {
+			Email string              `db:"email" presence:"conflict"`
+			Name  presence.Of[string] `db:"name"`
+			Plan  any                 `db:"plan"`
+		}
+
+		row := upsertAccount{Email: "ada@example.com", Name: presence.FromValue("Ada"), Plan: presence.Default}
+		query, args, err := presence.BuildUpsert("accounts", row, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, "INSERT INTO accounts (email, name, plan) VALUES (?, ?, DEFAULT)"+
+			" ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name", query)
+		assert.Equal(t, []any{"ada@example.com", row.Name}, args)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := presence.BuildUpsert("users", upsertUser{Tenant: 1}, presence.WithTag("db"))
+		require.ErrorContains(t, err, "conflict field email is unset")
+
+		_, _, err = presence.BuildUpsert("users", insertUser{}, presence.WithTag("db"))
+		require.ErrorContains(t, err, `no field tagged presence:"conflict"`)
+
+		_, _, err = presence.BuildUpsert("users", nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	gojson "github.com/goccy/go-json"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pivaldi/presence"
+	presencejsoniter "github.com/pivaldi/presence/contrib/jsoniter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type compatPatch struct {
+	Name  presence.Of[string]  `json:"name,omitzero"`
+	Age   presence.Of[int]     `json:"age,omitzero"`
+	Bio   presence.String      `json:"bio,omitzero"`
+	Email *presence.Of[string] `json:"email,omitzero"`
+	Note  presence.Of[string]  `json:"note"`
+	Tags  []compatTag          `json:"tags,omitempty"`
+}
+
+type compatTag struct {
+	Label presence.Of[string] `json:"label,omitzero"`
+	Rank  int                 `json:"rank"`
+}
+
+func compatPatchValue() compatPatch {
+	patch := compatPatch{Name: presence.FromValue("Ada"), Age: presence.Null[int]()}
+	patch.Name.SetMarshalUnset(presence.UnsetSkip)
+	patch.Age.SetMarshalUnset(presence.UnsetSkip)
+	patch.Bio.SetMarshalUnset(presence.UnsetSkip)
+	patch.Tags = []compatTag{{Rank: 1}}
+	patch.Tags[0].Label.SetMarshalUnset(presence.UnsetSkip)
+
+	return patch
+}
+
+// Tests for the json-iterator extension
+
+func TestJSONIterExtension(t *testing.T) {
+	api := jsoniter.Config{EscapeHTML: true, SortMapKeys: true, ValidateJsonRawMessage: true}.Froze()
+	presencejsoniter.RegisterTo(api)
+
+	t.Run("encodes like encoding/json", func(t *testing.T) {
+		patch := compatPatchValue()
+		want, err := json.Marshal(patch)
+		require.NoError(t, err)
+
+		got, err := api.Marshal(patch)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got))
+		assert.NotContains(t, string(got), "bio")
+		assert.NotContains(t, string(got), "label")
+	})
+
+	t.Run("set pointers and values are kept", func(t *testing.T) {
+		email := presence.FromValue("a@b.c")
+		patch := compatPatch{Email: &email, Bio: presence.NewString("x")}
+		want, err := json.Marshal(patch)
+		require.NoError(t, err)
+
+		got, err := api.Marshal(patch)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got))
+		assert.Contains(t, string(got), `"email":"a@b.c"`)
+	})
+
+	t.Run("decodes the three states", func(t *testing.T) {
+		var patch compatPatch
+		require.NoError(t, api.Unmarshal([]byte(`{"name":"Ada","age":null}`), &patch))
+		assert.Equal(t, "Ada", patch.Name.MustGet())
+		assert.True(t, patch.Age.IsNull())
+		assert.True(t, patch.Bio.IsUnset())
+		assert.Nil(t, patch.Email)
+	})
+
+	t.Run("other configurations are untouched", func(t *testing.T) {
+		got, err := jsoniter.Config{}.Froze().Marshal(compatPatchValue())
+		require.NoError(t, err)
+		assert.Contains(t, string(got), `"bio":null`)
+	})
+}
+
+// Tests for go-json, which has no extension point
+
+func TestGoJSON(t *testing.T) {
+	t.Run("unset fields encode as null", func(t *testing.T) {
+		got, err := gojson.Marshal(compatPatchValue())
+		require.NoError(t, err)
+		assert.Contains(t, string(got), `"bio":null`, "go-json ignores omitzero")
+	})
+
+	t.Run("decodes the three states", func(t *testing.T) {
+		var patch compatPatch
+		require.NoError(t, gojson.Unmarshal([]byte(`{"name":"Ada","age":null}`), &patch))
+		assert.Equal(t, "Ada", patch.Name.MustGet())
+		assert.True(t, patch.Age.IsNull())
+		assert.True(t, patch.Bio.IsUnset())
+	})
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type patchAddress struct {
+	City presence.Of[string] `json:"city"`
+	Zip  string              `json:"zip"`
+}
+
+type patchUser struct {
+	Version  int                       `json:"version"`
+	Email    presence.Of[string]       `json:"email"`
+	Nickname presence.Of[string]       `json:"nickname"`
+	Tags     presence.Of[[]string]     `json:"tags"`
+	Address  presence.Of[patchAddress] `json:"address"`
+	Home     *patchAddress             `json:"home"`
+	Work     patchAddress              `json:"work"`
+	Path     presence.Of[string]       `json:"a/b"`
+}
+
+func patchUserFixture() patchUser {
+	return patchUser{
+		Version:  3,
+		Email:    presence.FromValue("ada@example.com"),
+		Nickname: presence.FromValue("ada"),
+		Address:  presence.FromValue(patchAddress{City: presence.FromValue("Paris"), Zip: "75001"}),
+		Home:     &patchAddress{Zip: "69001"},
+	}
+}
+
+// Tests for ApplyJSONPatch
+
+func TestApplyJSONPatch(t *testing.T) {
+	t.Run("operations", func(t *testing.T) {
+		user := patchUserFixture()
+		require.NoError(t, presence.ApplyJSONPatch(&user, []byte(`[
+			{"op": "test", "path": "/version", "value": 3},
+			{"op": "replace", "path": "/email", "value": "grace@example.com"},
+			{"op": "add", "path": "/tags", "value": ["go", "sql"]},
+			{"op": "remove", "path": "/nickname"},
+			{"op": "replace", "path": "/version", "value": 4}
+		]`)))
+
+		expected := patchUserFixture()
+		expected.Version = 4
+		expected.Email = presence.FromValue("grace@example.com")
+		expected.Tags = presence.FromValue([]string{"go", "sql"})
+		expected.Nickname.Unset()
+		assert.Equal(t, expected, user)
+	})
+
+	t.Run("remove null", func(t *testing.T) {
+		user := patchUserFixture()
+		require.NoError(t, presence.ApplyJSONPatch(&user, []byte(`[{"op": "remove", "path": "/nickname"}]`),
+			presence.WithRemoveNull()))
+
+		assert.True(t, user.Nickname.IsNull())
+	})
+
+	t.Run("add null", func(t *testing.T) {
+		user := patchUserFixture()
+		require.NoError(t, presence.ApplyJSONPatch(&user, []byte(`[{"op": "add", "path": "/email", "value": null}]`)))
+
+		assert.True(t, user.Email.IsNull())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		user := patchUserFixture()
+		home := user.Home
+		require.NoError(t, presence.ApplyJSONPatch(&user, []byte(`[
+			{"op": "replace", "path": "/address/city", "value": "Lyon"},
+			{"op": "add", "path": "/home/city", "value": "Lyon"},
+			{"op": "replace", "path": "/work/zip", "value": "13001"}
+		]`)))
+
+		address, _ := user.Address.Get()
+		assert.Equal(t, presence.FromValue("Lyon"), address.City)
+		assert.Equal(t, presence.FromValue("Lyon"), user.Home.City)
+		assert.Equal(t, "13001", user.Work.Zip)
+		assert.True(t, home.City.IsUnset(), "the pointed struct is copied")
+	})
+
+	t.Run("move and copy", func(t *testing.T) {
+		user := patchUserFixture()
+		require.NoError(t, presence.ApplyJSONPatch(&user, []byte(`[
+			{"op": "copy", "from": "/nickname", "path": "/a~1b"},
+			{"op": "move", "from": "/email", "path": "/nickname"}
+		]`)))
+
+		assert.Equal(t, presence.FromValue("ada"), user.Path)
+		assert.Equal(t, presence.FromValue("ada@example.com"), user.Nickname)
+		assert.True(t, user.Email.IsUnset())
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		user := patchUserFixture()
+		err := presence.ApplyJSONPatch(&user, []byte(`[
+			{"op": "replace", "path": "/email", "value": "grace@example.com"},
+			{"op": "replace", "path": "/address/city", "value": "Lyon"},
+			{"op": "test", "path": "/version", "value": 2}
+		]`))
+
+		require.ErrorIs(t, err, presence.ErrPatchTest)
+		assert.Equal(t, patchUserFixture(), user)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for name, patch := range map[string]string{
+			"replace unset":  `[{"op": "replace", "path": "/tags", "value": []}]`,
+			"remove unset":   `[{"op": "remove", "path": "/tags"}]`,
+			"test unset":     `[{"op": "test", "path": "/tags", "value": null}]`,
+			"unknown field":  `[{"op": "add", "path": "/unknown", "value": 1}]`,
+			"through unset":  `[{"op": "add", "path": "/tags/0", "value": "go"}]`,
+			"through nil":    `[{"op": "remove", "path": "/home"}, {"op": "add", "path": "/home/city", "value": "Lyon"}]`,
+			"array element":  `[{"op": "add", "path": "/email/0", "value": "a"}]`,
+			"missing value":  `[{"op": "add", "path": "/email"}]`,
+			"invalid path":   `[{"op": "remove", "path": "email"}]`,
+			"unknown op":     `[{"op": "merge", "path": "/email", "value": 1}]`,
+			"invalid value":  `[{"op": "add", "path": "/version", "value": "four"}]`,
+			"invalid patch":  `{"op": "add"}`,
+			"null not valid": `[{"op": "add", "path": "/version", "value": null}]`,
+		} {
+			user := patchUserFixture()
+			require.Error(t, presence.ApplyJSONPatch(&user, []byte(patch)), name)
+			assert.Equal(t, patchUserFixture(), user, name)
+		}
+
+		require.Error(t, presence.ApplyJSONPatch(patchUser{}, []byte(`[]`)))
+	})
+}
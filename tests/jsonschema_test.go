@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	presencejsonschema "github.com/pivaldi/presence/contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaSettings map[string]any
+
+const settingsSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"theme": {"enum": ["light", "dark"]},
+		"fontSize": {"type": "integer", "minimum": 8, "maximum": 32},
+		"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}, "uniqueItems": true, "maxItems": 3}
+	},
+	"required": ["theme"],
+	"additionalProperties": false,
+	"$defs": {"tag": {"type": "string", "pattern": "^[a-z]+$", "minLength": 2}}
+}`
+
+// Tests for presencejsonschema
+
+func TestJSONSchemaValidate(t *testing.T) {
+	s, err := presencejsonschema.Compile([]byte(settingsSchema))
+	require.NoError(t, err)
+
+	valid := map[string]any{"theme": "dark", "fontSize": 12, "tags": []string{"go", "sql"}}
+	require.NoError(t, s.Validate(valid))
+	require.NoError(t, s.Validate(schemaSettings(valid)))
+
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{`{"fontSize": 12}`, "at '': missing property 'theme'"},
+		{`{"theme": "blue"}`, "at '/theme'"},
+		{`{"theme": "dark", "fontSize": 12.5}`, "at '/fontSize'"},
+		{`{"theme": "dark", "fontSize": 40}`, "at '/fontSize'"},
+		{`{"theme": "dark", "tags": ["go", "Go"]}`, "at '/tags/1'"},
+		{`{"theme": "dark", "tags": ["go", "go"]}`, "at '/tags'"},
+		{`{"theme": "dark", "tags": ["a"]}`, "at '/tags/0'"},
+		{`{"theme": "dark", "extra": 1}`, "'extra' not allowed"},
+		{`[]`, "at '': got array, want object"},
+	}
+
+	for _, tt := range tests {
+		var doc any
+		require.NoError(t, json.Unmarshal([]byte(tt.doc), &doc))
+
+		err := s.Validate(doc)
+		require.ErrorIs(t, err, presencejsonschema.ErrViolation, tt.doc)
+		assert.Contains(t, err.Error(), tt.want, tt.doc)
+	}
+
+	for _, schema := range []string{`{`, `1`, `{"pattern": "("}`, `{"$ref": "#/$defs/missing"}`} {
+		_, err := presencejsonschema.Compile([]byte(schema))
+		require.Error(t, err, schema)
+	}
+
+	assert.Panics(t, func() { presencejsonschema.MustCompile([]byte(`{`)) })
+}
+
+func TestJSONSchemaRegister(t *testing.T) {
+	require.NoError(t, presencejsonschema.Register[schemaSettings]([]byte(settingsSchema)))
+	t.Cleanup(presence.UnregisterValidator[schemaSettings])
+
+	t.Run("Scan", func(t *testing.T) {
+		var n presence.Of[schemaSettings]
+		require.NoError(t, n.Scan([]byte(`{"theme": "light", "fontSize": 14}`)))
+		assert.Equal(t, "light", n.MustGet()["theme"])
+
+		err := n.Scan([]byte(`{"theme": "pink"}`))
+		require.ErrorIs(t, err, presencejsonschema.ErrViolation)
+		assert.True(t, n.IsUnset())
+
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("UnmarshalJSON", func(t *testing.T) {
+		type payload struct {
+			Settings presence.Of[schemaSettings] `json:"settings"`
+		}
+
+		var valid, invalid payload
+		require.NoError(t, json.Unmarshal([]byte(`{"settings": {"theme": "dark"}}`), &valid))
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"settings": {"fontSize": 12}}`), &invalid),
+			presencejsonschema.ErrViolation)
+	})
+
+	t.Run("other types are not affected", func(t *testing.T) {
+		var n presence.Of[map[string]any]
+		require.NoError(t, n.Scan([]byte(`{"theme": "pink"}`)))
+	})
+
+	require.Error(t, presencejsonschema.Register[schemaSettings]([]byte(`{"type": "object"`)))
+}
+
+func TestJSONSchemaRegisterComposes(t *testing.T) {
+	type profile map[string]any
+
+	errReserved := errors.New("reserved name")
+	presence.RegisterValidator(func(p profile) error {
+		if p["name"] == "root" {
+			return errReserved
+		}
+
+		return nil
+	})
+	t.Cleanup(presence.UnregisterValidator[profile])
+
+	require.NoError(t, presencejsonschema.Register[profile]([]byte(`{"required": ["name"]}`)))
+
+	var n presence.Of[profile]
+	require.NoError(t, n.Scan([]byte(`{"name": "ada"}`)))
+	require.ErrorIs(t, n.Scan([]byte(`{"name": "root"}`)), errReserved, "the previous validator still runs")
+	require.ErrorIs(t, n.Scan([]byte(`{}`)), presencejsonschema.ErrViolation)
+}
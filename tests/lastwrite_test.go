@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lastWritePatch struct {
+	Name  presence.Of[string]  `json:"name"`
+	Bio   presence.Of[string]  `json:"bio"`
+	Age   presence.Of[int]     `json:"age"`
+	Score presence.Of[float64] `json:"score"`
+}
+
+type lastWriteEntity struct {
+	Name  string   `json:"name"`
+	Bio   *string  `json:"bio"`
+	Age   int64    `json:"age"`
+	Score *float64 `json:"score"`
+}
+
+// Tests for LastWriteFields
+
+func TestLastWriteFields(t *testing.T) {
+	patch := lastWritePatch{Name: presence.FromValue("Ada"), Bio: presence.Null[string](), Age: presence.FromValue(36)}
+
+	written, err := presence.LastWriteFields(patch)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Ada", "bio": nil, "age": 36}, written.Fields)
+
+	t.Run("presence read", func(t *testing.T) {
+		stale, err := written.Stale(patch)
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+
+		stale, err = written.Stale(lastWritePatch{Name: presence.FromValue("Bob"), Bio: presence.FromValue("x")})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"age", "bio", "name"}, stale, "unset fields are stale")
+	})
+
+	t.Run("entity read", func(t *testing.T) {
+		score := 1.5
+
+		stale, err := written.Stale(&lastWriteEntity{Name: "Ada", Age: 36, Score: &score})
+		require.NoError(t, err)
+		assert.Empty(t, stale, "values are converted, nil pointers are null")
+
+		bio := "Countess"
+		stale, err = written.Stale(lastWriteEntity{Name: "Ada", Bio: &bio, Age: 35})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"age", "bio"}, stale)
+	})
+
+	t.Run("comparison options", func(t *testing.T) {
+		written, err := presence.LastWriteFields(lastWritePatch{Score: presence.FromValue(0.1 + 0.2)},
+			presence.WithFloatEpsilon(1e-9))
+		require.NoError(t, err)
+
+		score := 0.3
+		stale, err := written.Stale(lastWriteEntity{Score: &score})
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		type other struct {
+			Name string `json:"name"`
+		}
+
+		_, err := written.Stale(other{Name: "Ada"})
+		require.ErrorContains(t, err, "has no field")
+
+		_, err = written.Stale(nil)
+		require.Error(t, err)
+
+		_, err = presence.LastWriteFields(42)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	presencemapper "github.com/pivaldi/presence/contrib/mapper"
+	"github.com/pivaldi/presence/tests/mappermodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for presencemapper
+
+func TestMapperGeneratedFunctions(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	nickname, score := "ada", float32(1.5)
+
+	t.Run("to DTO", func(t *testing.T) {
+		user := mappermodel.User{
+			ID: 1, Email: "ada@b.c", Nickname: &nickname, Age: 36, Score: &score, Level: 2,
+			CreatedAt: createdAt, Password: "secret",
+		}
+
+		assert.Equal(t, mappermodel.UserDTO{
+			ID:        1,
+			Email:     presence.FromValue("ada@b.c"),
+			Nickname:  presence.FromValue("ada"),
+			Age:       presence.FromValue(int32(36)),
+			Score:     presence.FromValue(1.5),
+			Level:     presence.FromValue(2),
+			Bio:       presence.String{Of: presence.FromValue("")},
+			CreatedAt: presence.FromValue(createdAt),
+			DeletedAt: presence.Null[time.Time](),
+		}, mappermodel.ToUserDTO(user))
+	})
+
+	t.Run("from DTO", func(t *testing.T) {
+		dto := mappermodel.UserDTO{
+			ID:        1,
+			Email:     presence.Null[string](),
+			Nickname:  presence.FromValue("ada"),
+			Score:     presence.FromValue(1.5),
+			Level:     presence.FromValue(2),
+			CreatedAt: presence.FromValue(createdAt),
+			Extra:     presence.FromValue("ignored"),
+		}
+
+		assert.Equal(t, mappermodel.User{
+			ID: 1, Nickname: &nickname, Score: &score, Level: 2, CreatedAt: createdAt,
+		}, mappermodel.FromUserDTO(dto))
+	})
+
+	t.Run("paired through -pair", func(t *testing.T) {
+		summary := mappermodel.ToUserSummary(mappermodel.User{ID: 1, Email: "ada@b.c"})
+		assert.Equal(t, mappermodel.UserSummary{ID: 1, Email: presence.FromValue("ada@b.c")}, summary)
+		assert.Equal(t, mappermodel.User{ID: 1, Email: "ada@b.c"}, mappermodel.FromUserSummary(summary))
+	})
+}
+
+func TestMapperGenerate(t *testing.T) {
+	t.Run("generated code is up to date", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, presencemapper.Generate(&out, "mappermodel", presencemapper.Pair{Entity: "User", DTO: "UserSummary"}))
+
+		committed, err := os.ReadFile(filepath.Join("mappermodel", "presence_mapper.go"))
+		require.NoError(t, err)
+		assert.Equal(t, string(committed), out.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var out bytes.Buffer
+		require.ErrorContains(t, presencemapper.Generate(&out, "mappermodel",
+			presencemapper.Pair{Entity: "Missing", DTO: "UserSummary"}), "unknown type Missing")
+		require.ErrorContains(t, presencemapper.Generate(&out, "mappermodel",
+			presencemapper.Pair{Entity: "User", DTO: "Level"}), "not a struct")
+		require.ErrorContains(t, presencemapper.Generate(&out, "mappermodel",
+			presencemapper.Pair{Entity: "UserDTO", DTO: "UserSummary"}), "cannot map field Email")
+
+		_, err := presencemapper.ParsePair("User")
+		require.Error(t, err)
+	})
+}
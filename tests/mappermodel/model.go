@@ -0,0 +1,48 @@
+// Package mappermodel holds the entities and DTOs whose mapping functions
+// presence-mapper generates for the tests.
+package mappermodel
+
+import (
+	"time"
+
+	"github.com/pivaldi/presence"
+)
+
+//go:generate go run github.com/pivaldi/presence/contrib/mapper/cmd/presence-mapper -pair User=UserSummary
+
+// Level is a defined type converted to and from its underlying type.
+type Level int
+
+// User is the entity.
+type User struct {
+	ID        int64
+	Email     string
+	Nickname  *string
+	Age       int
+	Score     *float32
+	Level     Level
+	Bio       string
+	CreatedAt time.Time
+	DeletedAt *time.Time
+	Password  string
+}
+
+//presence:mapper User
+type UserDTO struct {
+	ID        int64
+	Email     presence.Of[string]
+	Nickname  presence.Of[string]
+	Age       presence.Of[int32]
+	Score     presence.Of[float64]
+	Level     presence.Of[int]
+	Bio       presence.String
+	CreatedAt presence.Of[time.Time]
+	DeletedAt presence.Of[time.Time]
+	Extra     presence.Of[string]
+}
+
+// UserSummary is paired through -pair.
+type UserSummary struct {
+	ID    int64
+	Email presence.Of[string]
+}
@@ -0,0 +1,66 @@
+// Code generated by presence-mapper. DO NOT EDIT.
+
+package mappermodel
+
+import (
+	"github.com/pivaldi/presence"
+)
+
+// ToUserDTO maps a User to a UserDTO.
+func ToUserDTO(v User) UserDTO {
+	return UserDTO{
+		ID:        v.ID,
+		Email:     presence.FromValue(v.Email),
+		Nickname:  presence.FromPtr(v.Nickname),
+		Age:       presence.FromValue(int32(v.Age)),
+		Score:     presence.Map(presence.FromPtr(v.Score), func(x float32) float64 { return float64(x) }),
+		Level:     presence.FromValue(int(v.Level)),
+		Bio:       presence.String{Of: presence.FromValue(v.Bio)},
+		CreatedAt: presence.FromValue(v.CreatedAt),
+		DeletedAt: presence.FromPtr(v.DeletedAt),
+	}
+}
+
+// FromUserDTO maps a UserDTO to a User, null and unset fields giving zero values.
+func FromUserDTO(dto UserDTO) User {
+	var out User
+	out.ID = dto.ID
+	out.Email, _ = dto.Email.Get()
+	if x, ok := dto.Nickname.Get(); ok {
+		out.Nickname = &x
+	}
+	if x, ok := dto.Age.Get(); ok {
+		out.Age = int(x)
+	}
+	if x, ok := dto.Score.Get(); ok {
+		y := float32(x)
+		out.Score = &y
+	}
+	if x, ok := dto.Level.Get(); ok {
+		out.Level = Level(x)
+	}
+	out.Bio, _ = dto.Bio.Get()
+	out.CreatedAt, _ = dto.CreatedAt.Get()
+	if x, ok := dto.DeletedAt.Get(); ok {
+		out.DeletedAt = &x
+	}
+
+	return out
+}
+
+// ToUserSummary maps a User to a UserSummary.
+func ToUserSummary(v User) UserSummary {
+	return UserSummary{
+		ID:    v.ID,
+		Email: presence.FromValue(v.Email),
+	}
+}
+
+// FromUserSummary maps a UserSummary to a User, null and unset fields giving zero values.
+func FromUserSummary(dto UserSummary) User {
+	var out User
+	out.ID = dto.ID
+	out.Email, _ = dto.Email.Get()
+
+	return out
+}
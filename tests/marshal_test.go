@@ -1,8 +1,11 @@
 package tests
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -1300,7 +1303,7 @@ func TestMarshalUnmarshal(t *testing.T) {
 				if dte == nil {
 					assert.Nil(t, obj[i].DateTo.GetValue(), "DateTo nil value mismatch at index %d", i)
 				} else {
-					assert.Equal(t, time.Duration(0), dte.Sub(now), "DateTo value mismatch at index %d", i)
+					assert.True(t, dte.Equal(now), "DateTo value mismatch at index %d", i)
 				}
 			})
 
@@ -1319,3 +1322,373 @@ func TestMarshalUnmarshal(t *testing.T) {
 		}
 	})
 }
+
+func TestMarshalJSON_PointerReceivers(t *testing.T) {
+	type withPointers struct {
+		Name  *presence.Of[string] `json:"name"`
+		Age   *presence.Of[int]    `json:"age,omitzero"`
+		Email *presence.Of[string] `json:"email,omitzero"`
+	}
+
+	t.Run("pointer fields", func(t *testing.T) {
+		name := presence.FromValue("John")
+		var email presence.Of[string]
+		data, err := json.Marshal(withPointers{Name: &name, Email: &email})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"John"}`, string(data))
+	})
+
+	t.Run("nil pointer field marshals as null", func(t *testing.T) {
+		data, err := json.Marshal(withPointers{})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":null}`, string(data))
+	})
+
+	t.Run("pointer to null value", func(t *testing.T) {
+		null := presence.Null[int]()
+		data, err := json.Marshal(withPointers{Age: &null})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":null,"age":null}`, string(data))
+	})
+
+	t.Run("map of pointers", func(t *testing.T) {
+		a := presence.FromValue(1)
+		b := presence.Null[int]()
+		data, err := json.Marshal(map[string]*presence.Of[int]{"a": &a, "b": &b, "c": nil})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a":1,"b":null,"c":null}`, string(data))
+	})
+
+	t.Run("map of values", func(t *testing.T) {
+		data, err := json.Marshal(map[string]presence.Of[int]{"a": presence.FromValue(1), "b": presence.Null[int]()})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a":1,"b":null}`, string(data))
+	})
+
+	t.Run("interface boxing", func(t *testing.T) {
+		v := presence.FromValue("boxed")
+		for _, boxed := range []any{v, &v} {
+			data, err := json.Marshal(boxed)
+			require.NoError(t, err)
+			assert.Equal(t, `"boxed"`, string(data))
+		}
+	})
+
+	t.Run("unmarshal into map of pointers", func(t *testing.T) {
+		var m map[string]*presence.Of[int]
+		require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":null}`), &m))
+		assert.Equal(t, 1, m["a"].MustGet())
+		assert.Nil(t, m["b"], "encoding/json stores null as a nil pointer")
+	})
+
+	t.Run("unmarshal into map of values", func(t *testing.T) {
+		var m map[string]presence.Of[int]
+		require.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":null}`), &m))
+		a, b := m["a"], m["b"]
+		assert.Equal(t, 1, a.MustGet())
+		assert.True(t, b.IsNull())
+	})
+}
+
+// date encodes as "2006-01-02" through value receivers.
+type date struct{ Y, M, D int }
+
+func (d date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%04d-%02d-%02d", d.Y, d.M, d.D))
+}
+
+func (d *date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	_, err := fmt.Sscanf(s, "%d-%d-%d", &d.Y, &d.M, &d.D)
+
+	return err
+}
+
+// ptrDate is a date whose MarshalJSON has a pointer receiver.
+type ptrDate date
+
+func (d *ptrDate) MarshalJSON() ([]byte, error) {
+	return date(*d).MarshalJSON()
+}
+
+func (d *ptrDate) UnmarshalJSON(data []byte) error {
+	return (*date)(d).UnmarshalJSON(data)
+}
+
+// level encodes as text through pointer receivers.
+type level struct{ n int }
+
+func (l *level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", l.n)), nil
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	l.n = len(text)
+
+	return nil
+}
+
+func TestMarshalUnmarshal_CustomMarshalers(t *testing.T) {
+	type event struct {
+		On    presence.Of[date]    `json:"on,omitzero"`
+		Until presence.Of[ptrDate] `json:"until,omitzero"`
+		Level presence.Of[level]   `json:"level,omitzero"`
+	}
+
+	in := event{
+		On:    presence.FromValue(date{2024, 1, 2}),
+		Until: presence.FromValue(ptrDate{2024, 12, 31}),
+		Level: presence.FromValue(level{3}),
+	}
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"on":"2024-01-02","until":"2024-12-31","level":"***"}`, string(data))
+
+	var out event
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	t.Run("null and unset", func(t *testing.T) {
+		data, err := json.Marshal(event{On: presence.Null[date]()})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"on":null}`, string(data))
+
+		var out event
+		require.NoError(t, json.Unmarshal([]byte(`{"until":null}`), &out))
+		assert.True(t, out.Until.IsNull())
+		assert.True(t, out.On.IsUnset())
+	})
+
+	t.Run("marshaler errors", func(t *testing.T) {
+		var out event
+		require.Error(t, json.Unmarshal([]byte(`{"on":"not a date"}`), &out))
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	type event struct {
+		Message presence.Of[string]         `json:"message"`
+		Meta    presence.Of[map[string]any] `json:"meta"`
+		Note    string                      `json:"note"`
+	}
+
+	e := event{
+		Message: presence.FromValue("<b>Tom & Jerry</b>"),
+		Meta:    presence.FromValue(map[string]any{"path": `C:\u003c`}),
+		Note:    "a > b",
+	}
+
+	t.Run("like json.Marshal by default", func(t *testing.T) {
+		want, err := json.Marshal(e)
+		require.NoError(t, err)
+
+		got, err := presence.Marshal(e)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+	})
+
+	t.Run("without HTML escaping", func(t *testing.T) {
+		got, err := presence.Marshal(e, presence.WithEscapeHTML(false))
+		require.NoError(t, err)
+		assert.Equal(t, `{"message":"<b>Tom & Jerry</b>","meta":{"path":"C:\\u003c"},"note":"a > b"}`, string(got))
+	})
+
+	t.Run("indented", func(t *testing.T) {
+		got, err := presence.Marshal(presence.FromValue([]int{1, 2}), presence.WithIndent(">", "  "))
+		require.NoError(t, err)
+		assert.Equal(t, "[\n>  1,\n>  2\n>]", string(got))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := presence.Marshal(presence.FromValue(math.NaN()))
+		require.Error(t, err)
+	})
+}
+
+type layerTeam struct {
+	Name     presence.Of[string] `json:"name" db:"name"`
+	BudgetID presence.Of[int64]  `json:"budget_id" db:"budget_id" presence:"dbonly"`
+}
+
+type layerUser struct {
+	ID           int64                  `json:"id" db:"id"`
+	Email        presence.Of[string]    `json:"email" db:"email"`
+	PasswordHash presence.Of[string]    `json:"password_hash,omitzero" db:"password_hash" presence:"dbonly"`
+	Avatar       presence.Of[string]    `json:"avatar,omitzero" db:"avatar" presence:"apionly"`
+	Team         presence.Of[layerTeam] `json:"team,omitzero" db:"-"`
+	Manager      *layerUser             `json:"manager,omitempty" db:"-"`
+}
+
+// Tests for the dbonly and apionly tag options
+
+func TestLayerTags(t *testing.T) {
+	user := layerUser{
+		ID:           1,
+		Email:        presence.FromValue("ada@example.com"),
+		PasswordHash: presence.FromValue("$2a$10$secret"),
+		Avatar:       presence.FromValue("https://cdn.example.com/ada.png"),
+		Team: presence.FromValue(layerTeam{
+			Name: presence.FromValue("core"), BudgetID: presence.FromValue(int64(7)),
+		}),
+		Manager: &layerUser{ID: 2, PasswordHash: presence.FromValue("$2a$10$other")},
+	}
+
+	t.Run("Marshal leaves dbonly fields out", func(t *testing.T) {
+		got, err := presence.Marshal(&user)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"id": 1,
+			"email": "ada@example.com",
+			"avatar": "https://cdn.example.com/ada.png",
+			"team": {"name": "core"},
+			"manager": {"id": 2, "email": null}
+		}`, string(got))
+
+		indented, err := presence.Marshal(user, presence.WithIndent("", "  "))
+		require.NoError(t, err)
+		assert.JSONEq(t, string(got), string(indented))
+		assert.Contains(t, string(indented), "\n  \"email\": \"ada@example.com\"")
+	})
+
+	t.Run("Marshal filters slices, arrays and maps", func(t *testing.T) {
+		other := layerUser{ID: 3, PasswordHash: presence.FromValue("$2a$10$third")}
+
+		got, err := presence.Marshal([]layerUser{other, {ID: 4}})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"id": 3, "email": null}, {"id": 4, "email": null}]`, string(got))
+
+		got, err = presence.Marshal([1]*layerUser{&other})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"id": 3, "email": null}]`, string(got))
+
+		got, err = presence.Marshal(map[string]layerUser{"x": other})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"x": {"id": 3, "email": null}}`, string(got))
+
+		got, err = presence.Marshal(map[int]any{7: other})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"7": {"id": 3, "email": null}}`, string(got))
+	})
+
+	t.Run("Marshal filters nested slices", func(t *testing.T) {
+		type squad struct {
+			Members []layerUser                    `json:"members"`
+			Leads   presence.Of[[]*layerUser]      `json:"leads"`
+			ByRole  map[string][]layerUser         `json:"by_role"`
+			Grid    [][]layerUser                  `json:"grid"`
+			Tags    presence.Of[map[string]string] `json:"tags"`
+		}
+
+		other := layerUser{ID: 3, PasswordHash: presence.FromValue("$2a$10$third")}
+		got, err := presence.Marshal(squad{
+			Members: []layerUser{other},
+			Leads:   presence.FromValue([]*layerUser{&other, nil}),
+			ByRole:  map[string][]layerUser{"dev": {other}},
+			Grid:    [][]layerUser{{other}, nil},
+			Tags:    presence.FromValue(map[string]string{"password_hash": "kept"}),
+		})
+		require.NoError(t, err)
+		assert.NotContains(t, string(got), "$2a$10$")
+
+		user := `{"id": 3, "email": null}`
+		assert.JSONEq(t, `{
+			"members": [`+user+`],
+			"leads": [`+user+`, null],
+			"by_role": {"dev": [`+user+`]},
+			"grid": [[`+user+`], null],
+			"tags": {"password_hash": "kept"}
+		}`, string(got))
+	})
+
+	t.Run("json.Marshal keeps the dbonly fields of the structs it encodes", func(t *testing.T) {
+		got, err := json.Marshal(user)
+		require.NoError(t, err)
+		assert.Contains(t, string(got), "password_hash")
+		assert.NotContains(t, string(got), "budget_id", "presence values filter their own encoding")
+
+		got, err = json.Marshal(presence.FromValue(user))
+		require.NoError(t, err)
+		assert.NotContains(t, string(got), "$2a$10$")
+
+		got, err = json.Marshal(presence.FromValue[any](user))
+		require.NoError(t, err)
+		assert.NotContains(t, string(got), "$2a$10$")
+	})
+
+	t.Run("Encoder and MarshalCanonical leave dbonly fields out", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		enc := presence.NewEncoder(&buf)
+		require.NoError(t, enc.BeginObject())
+		require.NoError(t, enc.Field("user", user))
+		require.NoError(t, enc.EndObject())
+		assert.NotContains(t, buf.String(), "$2a$10$")
+
+		got, err := presence.MarshalCanonical(user)
+		require.NoError(t, err)
+		assert.NotContains(t, string(got), "$2a$10$")
+	})
+
+	t.Run("struct options are encoded like encoding/json", func(t *testing.T) {
+		type options struct {
+			Count   int                 `json:"count,string"`
+			Label   *string             `json:"label,omitempty"`
+			Tags    []string            `json:"tags,omitempty"`
+			At      time.Time           `json:"at,omitzero"`
+			Raw     json.RawMessage     `json:"raw"`
+			Extra   any                 `json:"extra"`
+			Skipped string              `json:"-"`
+			Secret  presence.Of[string] `json:"secret" presence:"dbonly"`
+		}
+
+		label := "x<y"
+		for _, v := range []options{
+			{Count: 3, Raw: json.RawMessage(`{"b":1}`), Extra: map[string]int{"z": 1, "a": 2}},
+			{Label: &label, Tags: []string{"t"}, At: time.Unix(0, 0).UTC(), Extra: layerUser{ID: 5}},
+		} {
+			v.Secret = presence.FromValue("s")
+
+			plain, err := json.Marshal(v)
+			require.NoError(t, err)
+
+			want := strings.Replace(string(plain), `,"secret":"s"`, "", 1)
+			want = strings.Replace(want, `,"password_hash":null`, "", 1)
+
+			got, err := presence.Marshal(v)
+			require.NoError(t, err)
+			assert.Equal(t, want, string(got))
+		}
+	})
+
+	t.Run("database builders leave apionly fields out", func(t *testing.T) {
+		m, err := presence.ToMap(user, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"email":         "ada@example.com",
+			"password_hash": "$2a$10$secret",
+		}, m)
+
+		ins, err := presence.NewInsert(user, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "email", "password_hash"}, ins.Columns)
+	})
+
+	t.Run("untagged structs are left as is", func(t *testing.T) {
+		e := struct {
+			At   time.Time           `json:"at"`
+			Name presence.Of[string] `json:"name"`
+		}{Name: presence.FromValue("x")}
+
+		want, err := json.Marshal(e)
+		require.NoError(t, err)
+
+		got, err := presence.Marshal(e)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+	})
+}
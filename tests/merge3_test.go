@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergeDoc struct {
+	Title   presence.Of[string]   `json:"title"`
+	Body    presence.Of[string]   `json:"body"`
+	Tags    presence.Of[[]string] `json:"tags"`
+	Summary presence.Of[string]   `json:"summary"`
+	Pages   int                   `json:"pages"`
+}
+
+// Tests for Merge3
+
+func TestMerge3(t *testing.T) {
+	base := mergeDoc{
+		Title:   presence.FromValue("Draft"),
+		Body:    presence.FromValue("Lorem"),
+		Summary: presence.FromValue("Short"),
+		Pages:   1,
+	}
+
+	t.Run("patches", func(t *testing.T) {
+		mine := mergeDoc{Title: presence.FromValue("Final"), Summary: presence.Null[string](), Pages: 1}
+		theirs := mergeDoc{Tags: presence.FromValue([]string{"go"}), Body: presence.FromValue("Lorem"), Pages: 2}
+
+		merged, conflicts, err := presence.Merge3(base, mine, theirs)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Equal(t, mergeDoc{
+			Title:   presence.FromValue("Final"),
+			Body:    presence.FromValue("Lorem"),
+			Tags:    presence.FromValue([]string{"go"}),
+			Summary: presence.Null[string](),
+			Pages:   2,
+		}, merged)
+	})
+
+	t.Run("full structs", func(t *testing.T) {
+		mine, theirs := base, base
+		mine.Title = presence.FromValue("Final")
+		theirs.Body = presence.FromValue("Ipsum")
+
+		merged, conflicts, err := presence.Merge3(&base, &mine, theirs)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+
+		expected := base
+		expected.Title, expected.Body = mine.Title, theirs.Body
+		assert.Equal(t, expected, merged)
+	})
+
+	t.Run("same change", func(t *testing.T) {
+		mine := mergeDoc{Title: presence.FromValue("Final"), Pages: 3}
+		theirs := mergeDoc{Title: presence.FromValue("Final"), Pages: 3}
+
+		merged, conflicts, err := presence.Merge3(base, mine, theirs)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Equal(t, presence.FromValue("Final"), merged.(mergeDoc).Title)
+		assert.Equal(t, 3, merged.(mergeDoc).Pages)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		mine := mergeDoc{Title: presence.FromValue("Mine"), Summary: presence.Null[string](), Pages: 2}
+		theirs := mergeDoc{Title: presence.FromValue("Theirs"), Summary: presence.FromValue("Long"), Pages: 3}
+
+		merged, conflicts, err := presence.Merge3(base, mine, theirs)
+		require.NoError(t, err)
+		assert.Equal(t, base, merged, "conflicting fields keep their base value")
+		assert.Equal(t, []presence.FieldConflict{
+			{
+				Field: "title", Base: "Draft", Mine: "Mine", Theirs: "Theirs",
+				BaseState: presence.StateValue, MineState: presence.StateValue, TheirsState: presence.StateValue,
+			},
+			{
+				Field: "summary", Base: "Short", Theirs: "Long",
+				BaseState: presence.StateValue, MineState: presence.StateNull, TheirsState: presence.StateValue,
+			},
+			{
+				Field: "pages", Base: 1, Mine: 2, Theirs: 3,
+				BaseState: presence.StateValue, MineState: presence.StateValue, TheirsState: presence.StateValue,
+			},
+		}, conflicts)
+	})
+
+	t.Run("comparison options", func(t *testing.T) {
+		type measure struct {
+			Weight presence.Of[float64] `json:"weight"`
+		}
+
+		_, conflicts, err := presence.Merge3(measure{}, measure{Weight: presence.FromValue(1.0)},
+			measure{Weight: presence.FromValue(1.0000001)}, presence.WithFloatEpsilon(1e-6))
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		_, _, err := presence.Merge3(base, base, struct{}{})
+		require.Error(t, err)
+
+		_, _, err = presence.Merge3(base, nil, base)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergeConfig struct {
+	Name    presence.Of[string]            `json:"name"`
+	Port    presence.Of[int]               `json:"port"`
+	Labels  presence.Of[map[string]string] `json:"labels"`
+	Owner   *string                        `json:"owner"`
+	Retries int                            `json:"retries"`
+	Address presence.Of[addressPatch]      `json:"address"`
+	Billing addressPatch                   `json:"billing"`
+}
+
+// Tests for UnmarshalMerge
+
+func TestUnmarshalMerge(t *testing.T) {
+	t.Run("overwrites the fields in data only", func(t *testing.T) {
+		cfg := mergeConfig{Name: presence.FromValue("api"), Port: presence.FromValue(80), Retries: 3}
+		require.NoError(t, presence.UnmarshalMerge([]byte(`{"port":8080,"unknown":1}`), &cfg))
+		assert.Equal(t, "api", cfg.Name.MustGet())
+		assert.Equal(t, 8080, cfg.Port.MustGet())
+		assert.Equal(t, 3, cfg.Retries)
+	})
+
+	t.Run("repeated merges accumulate", func(t *testing.T) {
+		var cfg mergeConfig
+		for _, data := range []string{
+			`{"name":"api","port":80,"retries":1}`,
+			`{"port":8080}`,
+			`{"name":null,"retries":2}`,
+		} {
+			require.NoError(t, presence.UnmarshalMerge([]byte(data), &cfg))
+		}
+
+		assert.True(t, cfg.Name.IsNull())
+		assert.Equal(t, 8080, cfg.Port.MustGet())
+		assert.Equal(t, 2, cfg.Retries)
+		assert.True(t, cfg.Labels.IsUnset())
+	})
+
+	t.Run("values are replaced, not decoded into", func(t *testing.T) {
+		cfg := mergeConfig{Labels: presence.FromValue(map[string]string{"env": "prod"})}
+		shared := cfg.Labels
+		require.NoError(t, presence.UnmarshalMerge([]byte(`{"labels":{"team":"core"}}`), &cfg))
+		assert.Equal(t, map[string]string{"team": "core"}, cfg.Labels.MustGet())
+		assert.Equal(t, map[string]string{"env": "prod"}, shared.MustGet())
+	})
+
+	t.Run("per-value behaviors are kept", func(t *testing.T) {
+		var cfg mergeConfig
+		cfg.Port.SetMarshalUnset(presence.UnsetNull)
+		require.NoError(t, presence.UnmarshalMerge([]byte(`{"port":1}`), &cfg))
+		assert.Equal(t, presence.UnsetNull, cfg.Port.GetMarshalUnset())
+	})
+
+	t.Run("null", func(t *testing.T) {
+		owner := "ada"
+		cfg := mergeConfig{Owner: &owner}
+		require.NoError(t, presence.UnmarshalMerge([]byte(`{"owner":null}`), &cfg))
+		assert.Nil(t, cfg.Owner)
+
+		err := presence.UnmarshalMerge([]byte(`{"retries":null}`), &cfg)
+		require.ErrorIs(t, err, presence.ErrNullNotAllowed)
+		assert.Contains(t, err.Error(), "retries")
+
+		require.NoError(t, presence.UnmarshalMerge([]byte(`null`), &cfg))
+	})
+
+	t.Run("nested presence structs", func(t *testing.T) {
+		cfg := mergeConfig{
+			Address: presence.FromValue(addressPatch{City: presence.FromValue("Nice"), Zip: presence.FromValue("06000")}),
+		}
+		cfg.Billing.City.SetValue("Nice")
+		data := []byte(`{"address":{"city":"Paris"},"billing":{"zip":"75001"}}`)
+
+		replaced := cfg
+		require.NoError(t, presence.UnmarshalMerge(data, &replaced))
+		a := replaced.Address.MustGet()
+		assert.True(t, a.Zip.IsUnset())
+		assert.True(t, replaced.Billing.City.IsUnset())
+
+		merged := cfg
+		require.NoError(t, presence.UnmarshalMerge(data, &merged, presence.WithNested(presence.NestedMaps)))
+		a = merged.Address.MustGet()
+		assert.Equal(t, "Paris", a.City.MustGet())
+		assert.Equal(t, "06000", a.Zip.MustGet())
+		assert.Equal(t, "Nice", merged.Billing.City.MustGet())
+		assert.Equal(t, "75001", merged.Billing.Zip.MustGet())
+		a = cfg.Address.MustGet()
+		assert.Equal(t, "Nice", a.City.MustGet(), "the original value is not modified")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var cfg mergeConfig
+		require.Error(t, presence.UnmarshalMerge([]byte(`{"port":"x"}`), &cfg))
+		require.Error(t, presence.UnmarshalMerge([]byte(`[1]`), &cfg))
+		require.Error(t, presence.UnmarshalMerge([]byte(`{}`), cfg))
+	})
+}
+
+// Tests for MergeJSON
+
+func TestMergeJSON(t *testing.T) {
+	// Test cases from RFC 7386, appendix A.
+	tests := []struct {
+		base, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{``, `{"a":1}`, `{"a":1}`},
+		{`{"id":9007199254740993}`, `{"n":1.50}`, `{"id":9007199254740993,"n":1.5}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.base+" + "+tt.patch, func(t *testing.T) {
+			got, err := presence.MergeJSON([]byte(tt.base), []byte(tt.patch))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("keeping nulls", func(t *testing.T) {
+		got, err := presence.MergeJSON([]byte(`{"a":"b","c":{"d":1}}`), []byte(`{"a":null,"c":{"d":null}}`),
+			presence.WithKeepNulls())
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":null,"c":{"d":null}}`, string(got))
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		_, err := presence.MergeJSON([]byte(`{`), []byte(`{}`))
+		require.ErrorContains(t, err, "base")
+
+		_, err = presence.MergeJSON([]byte(`{}`), []byte(`{} {}`))
+		require.ErrorContains(t, err, "patch")
+	})
+}
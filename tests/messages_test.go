@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ErrorMessage
+
+func TestErrorMessage(t *testing.T) {
+	wrapped := fmt.Errorf("presence patching field age : %w", presence.ErrNullNotAllowed)
+	other := errors.New("boom")
+
+	t.Run("English messages by default", func(t *testing.T) {
+		assert.Equal(t, "must not be null", presence.ErrorMessage(wrapped, "fr"))
+		assert.Equal(t, "required", presence.ErrorMessage(presence.ErrRequired, ""))
+		assert.Equal(t, "boom", presence.ErrorMessage(other, "en"))
+	})
+
+	t.Run("translator set", func(t *testing.T) {
+		presence.SetErrorTranslator(presence.Catalog{
+			"fr": {
+				{Err: presence.ErrRequired, Text: "obligatoire"},
+				{Err: presence.ErrNullNotAllowed, Text: "ne doit pas être nul"},
+			},
+			"pt": {{Err: presence.ErrRequired, Text: "obrigatório"}},
+		}.Translate)
+		defer presence.SetErrorTranslator(nil)
+
+		assert.Equal(t, "ne doit pas être nul", presence.ErrorMessage(wrapped, "fr"))
+		assert.Equal(t, "obligatoire", presence.ErrorMessage(presence.ErrRequired, "fr-CA"))
+		assert.Equal(t, "obrigatório", presence.ErrorMessage(presence.ErrRequired, "pt_BR"))
+		assert.Equal(t, "must not be empty", presence.ErrorMessage(presence.ErrEmpty, "fr"))
+		assert.Equal(t, "required", presence.ErrorMessage(presence.ErrRequired, "de"))
+		assert.Equal(t, "boom", presence.ErrorMessage(other, "fr"))
+
+		errs := presence.FieldErrors{"email": presence.ErrRequired, "age": wrapped}
+		assert.Equal(t, map[string]string{"email": "obligatoire", "age": "ne doit pas être nul"}, errs.Localize("fr"))
+
+		b, err := json.Marshal(errs)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors":{"email":"required","age":"must not be null"}}`, string(b))
+	})
+
+	t.Run("errors matching several messages get the first one", func(t *testing.T) {
+		joined := errors.Join(presence.ErrNullNotAllowed, presence.ErrRequired)
+		catalog := presence.Catalog{"fr": {
+			{Err: presence.ErrRequired, Text: "obligatoire"},
+			{Err: presence.ErrNullNotAllowed, Text: "ne doit pas être nul"},
+		}}
+
+		for range 20 {
+			msg, ok := catalog.Translate(joined, "fr")
+			require.True(t, ok)
+			assert.Equal(t, "obligatoire", msg)
+			assert.Equal(t, "required", presence.ErrorMessage(joined, "en"))
+		}
+	})
+
+	t.Run("nil restores the English messages", func(t *testing.T) {
+		assert.Equal(t, "required", presence.ErrorMessage(presence.ErrRequired, "fr"))
+	})
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pivaldi/presence"
+	presenceprometheus "github.com/pivaldi/presence/contrib/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHook is a MetricsHook counting the failures per kind and type.
+type recordingHook struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (h *recordingHook) add(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts[key]++
+}
+
+func (h *recordingHook) UnmarshalError(typ string, _ error) { h.add("unmarshal " + typ) }
+func (h *recordingHook) ScanError(typ string, _ error)      { h.add("scan " + typ) }
+func (h *recordingHook) Overflow(typ string)                { h.add("overflow " + typ) }
+
+// Tests for MetricsHook
+
+func TestMetricsHook(t *testing.T) {
+	hook := &recordingHook{counts: map[string]int{}}
+	presence.SetMetricsHook(hook)
+	defer presence.SetMetricsHook(nil)
+
+	var small presence.Of[int16]
+	require.Error(t, json.Unmarshal([]byte(`70000`), &small))
+	require.Error(t, json.Unmarshal([]byte(`1.5`), &small))
+	require.Error(t, small.Scan(int64(70000)))
+	require.Error(t, small.Scan("abc"))
+
+	var id presence.Of[uuid.UUID]
+	require.Error(t, id.Scan("not-a-uuid"))
+
+	// Successes and nulls are not reported.
+	require.NoError(t, json.Unmarshal([]byte(`null`), &small))
+	require.NoError(t, small.Scan(int64(7)))
+
+	assert.Equal(t, map[string]int{
+		"unmarshal int16": 2,
+		"scan int16":      2,
+		"overflow int16":  2,
+		"scan uuid.UUID":  1,
+	}, hook.counts)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := presenceprometheus.NewMetrics(reg)
+	presence.SetMetricsHook(metrics)
+	defer presence.SetMetricsHook(nil)
+
+	var n presence.Of[int16]
+	require.Error(t, json.Unmarshal([]byte(`70000`), &n))
+	require.Error(t, n.Scan("abc"))
+
+	count, err := testutil.GatherAndCount(reg)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	collectors := metrics.Collectors()
+	require.Len(t, collectors, 3)
+	assert.InDelta(t, 1, testutil.ToFloat64(collectors[0]), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(collectors[1]), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(collectors[2]), 0)
+}
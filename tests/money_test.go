@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eur(t *testing.T, amount string) presence.Money {
+	t.Helper()
+
+	m, err := presence.NewMoney(amount, "EUR")
+	require.NoError(t, err)
+
+	return m
+}
+
+// Tests for Money
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		units            int64
+		want             string
+	}{
+		{"12.34", "EUR", 1234, "12.34"},
+		{"12.3", "EUR", 1230, "12.30"},
+		{"12", "EUR", 1200, "12.00"},
+		{"0.05", "EUR", 5, "0.05"},
+		{"-0.05", "EUR", -5, "-0.05"},
+		{"12.3400", "EUR", 1234, "12.34"},
+		{"1500", "JPY", 1500, "1500"},
+		{"1.5", "BHD", 1500, "1.500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			m, err := presence.NewMoney(tt.amount, tt.currency)
+			require.NoError(t, err)
+			assert.Equal(t, tt.units, m.Units)
+			assert.Equal(t, tt.want, m.Amount())
+			assert.Equal(t, tt.want+" "+tt.currency, m.String())
+		})
+	}
+
+	t.Run("invalid amounts and currencies", func(t *testing.T) {
+		for _, in := range [][2]string{
+			{"12.345", "EUR"}, {"1.5", "JPY"}, {"", "EUR"}, {"1e2", "EUR"}, {"1.2.3", "EUR"},
+			{"99999999999999999999", "EUR"}, {"1", "eur"}, {"1", "EURO"}, {"1", ""},
+		} {
+			_, err := presence.NewMoney(in[0], in[1])
+			require.ErrorIs(t, err, presence.ErrInvalidMoney, in)
+		}
+	})
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type order struct {
+		Discount presence.Of[presence.Money] `json:"discount,omitzero"`
+	}
+
+	t.Run("encodes as an object", func(t *testing.T) {
+		b, err := json.Marshal(order{Discount: presence.FromValue(eur(t, "12.3"))})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"discount":{"amount":"12.30","currency":"EUR"}}`, string(b))
+
+		b, err = json.Marshal(order{Discount: presence.Null[presence.Money]()})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"discount":null}`, string(b))
+
+		b, err = json.Marshal(order{})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(b))
+	})
+
+	t.Run("decodes string and number amounts", func(t *testing.T) {
+		for _, in := range []string{
+			`{"discount":{"amount":"12.34","currency":"EUR"}}`,
+			`{"discount":{"amount":12.34,"currency":"EUR"}}`,
+		} {
+			var o order
+			require.NoError(t, json.Unmarshal([]byte(in), &o))
+			assert.Equal(t, eur(t, "12.34"), o.Discount.MustGet())
+		}
+
+		var o order
+		require.NoError(t, json.Unmarshal([]byte(`{"discount":null}`), &o))
+		assert.True(t, o.Discount.IsNull())
+
+		require.ErrorIs(t, json.Unmarshal([]byte(`{"discount":{"amount":"1.234","currency":"EUR"}}`), &o),
+			presence.ErrInvalidMoney)
+	})
+}
+
+func TestMoneyValues(t *testing.T) {
+	amount, currency, err := presence.MoneyValues(presence.FromValue(eur(t, "-7.5")))
+	require.NoError(t, err)
+	assert.Equal(t, "-7.50", amount)
+	assert.Equal(t, "EUR", currency)
+
+	amount, currency, err = presence.MoneyValues(presence.Null[presence.Money]())
+	require.NoError(t, err)
+	assert.Nil(t, amount)
+	assert.Nil(t, currency)
+
+	var unset presence.Of[presence.Money]
+	unset.SetValueUnset(presence.ValueUnsetError)
+	_, _, err = presence.MoneyValues(unset)
+	require.ErrorIs(t, err, presence.ErrUnsetValue)
+
+	v, err := presence.FromValue(eur(t, "1")).Value()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"1.00","currency":"EUR"}`, v.(string))
+}
+
+func TestMoneyScanners(t *testing.T) {
+	scan := func(n *presence.Of[presence.Money], amount, currency any) error {
+		a, c := presence.MoneyScanners(n)
+		if err := a.Scan(amount); err != nil {
+			return err
+		}
+
+		return c.Scan(currency)
+	}
+
+	t.Run("value", func(t *testing.T) {
+		var n presence.Of[presence.Money]
+		require.NoError(t, scan(&n, []byte("12.34"), "EUR"))
+		assert.Equal(t, eur(t, "12.34"), n.MustGet())
+	})
+
+	t.Run("padded currency", func(t *testing.T) {
+		var n presence.Of[presence.Money]
+		require.NoError(t, scan(&n, "1500", "JPY "))
+		assert.Equal(t, int64(1500), n.MustGet().Units)
+	})
+
+	t.Run("both NULL", func(t *testing.T) {
+		var n presence.Of[presence.Money]
+		require.NoError(t, scan(&n, nil, nil))
+		assert.True(t, n.IsNull())
+
+		n = presence.Of[presence.Money]{}
+		n.SetScanNull(presence.ScanNullAsUnset)
+		require.NoError(t, scan(&n, nil, nil))
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var n presence.Of[presence.Money]
+		require.ErrorIs(t, scan(&n, "1", nil), presence.ErrInvalidMoney)
+		require.ErrorIs(t, scan(&n, nil, "EUR"), presence.ErrInvalidMoney)
+		require.ErrorIs(t, scan(&n, "1.234", "EUR"), presence.ErrInvalidMoney)
+		assert.True(t, n.IsUnset())
+	})
+}
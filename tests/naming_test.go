@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/presence/naming"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeToCamelCase(t *testing.T) {
+	cases := map[string]string{
+		"created_at":   "createdAt",
+		"ID":           "id",
+		"user_id":      "userId",
+		" first_name ": "firstName",
+		"":             "",
+		"été_prochain": "étéProchain",
+	}
+
+	for in, want := range cases {
+		assert.Equal(t, want, naming.SnakeToCamelCase(in), in)
+	}
+}
+
+func TestCamelToSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"userName":   "user_name",
+		"Address2":   "address2",
+		"ID":         "id",
+		"already_ok": "already_ok",
+	}
+
+	for in, want := range cases {
+		assert.Equal(t, want, naming.CamelToSnakeCase(in), in)
+	}
+}
+
+func TestToKebabCase(t *testing.T) {
+	assert.Equal(t, "created-at", naming.ToKebabCase("CreatedAt"))
+	assert.Equal(t, "user-id", naming.ToKebabCase("user_id"))
+}
+
+func TestNamingRegistry(t *testing.T) {
+	for name, want := range map[string]string{"camel": "createdAt", "snake": "created_at", "kebab": "created-at"} {
+		s, ok := naming.Lookup(name)
+		assert.True(t, ok, name)
+		input := "created_at"
+		if name != "camel" {
+			input = "CreatedAt"
+		}
+		assert.Equal(t, want, s.Convert(input))
+	}
+
+	naming.Register("upper", naming.Func(strings.ToUpper))
+	s, ok := naming.Lookup("upper")
+	assert.True(t, ok)
+	assert.Equal(t, "ID", s.Convert("id"))
+
+	_, ok = naming.Lookup("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pointEWKB is SRID=4326;POINT(1 2) as PostGIS prints it.
+const pointEWKB = "0101000020E6100000000000000000F03F0000000000000040"
+
+// Tests for Point
+
+func TestPointScan(t *testing.T) {
+	binary, err := hex.DecodeString(pointEWKB)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		src  any
+	}{
+		{"hex EWKB text", pointEWKB},
+		{"hex EWKB bytes", []byte(pointEWKB)},
+		{"binary EWKB", binary},
+		{"big endian WKB", "00000000013FF00000000000004000000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n presence.Of[presence.Point]
+			require.NoError(t, n.Scan(tt.src))
+			assert.Equal(t, presence.Point{Lat: 2, Lng: 1}, n.MustGet())
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		var n presence.Of[presence.Point]
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("invalid geometries", func(t *testing.T) {
+		for _, src := range []any{
+			"0101000020E6100000000000000000F03F",                                 // truncated
+			"0101000020110F0000000000000000F03F0000000000000040",                 // SRID 3857
+			"01010000A0E6100000000000000000F03F00000000000000400000000000000840", // POINT Z
+			"0102000020E61000000000000000",                                       // LINESTRING
+			"0101000020E6100000000000000000F87F000000000000F87F",                 // POINT EMPTY
+			"zz", 42,
+		} {
+			var n presence.Of[presence.Point]
+			require.ErrorIs(t, n.Scan(src), presence.ErrInvalidPoint, src)
+			assert.True(t, n.IsUnset())
+		}
+	})
+}
+
+func TestPointValue(t *testing.T) {
+	v, err := presence.FromValue(presence.Point{Lat: 2, Lng: 1}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, pointEWKB, v)
+
+	v, err = presence.Null[presence.Point]().Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	assert.Equal(t, "POINT(1 2)", presence.Point{Lat: 2, Lng: 1}.String())
+}
+
+func TestPointJSON(t *testing.T) {
+	type shop struct {
+		Location presence.Of[presence.Point] `json:"location,omitzero"`
+	}
+
+	t.Run("encodes as GeoJSON", func(t *testing.T) {
+		b, err := json.Marshal(shop{Location: presence.FromValue(presence.Point{Lat: 48.8566, Lng: 2.3522})})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"location":{"type":"Point","coordinates":[2.3522,48.8566]}}`, string(b))
+
+		b, err = json.Marshal(shop{})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(b))
+	})
+
+	t.Run("decodes GeoJSON", func(t *testing.T) {
+		var s shop
+		require.NoError(t, json.Unmarshal([]byte(`{"location":{"type":"Point","coordinates":[2.3522,48.8566]}}`), &s))
+		assert.Equal(t, presence.Point{Lat: 48.8566, Lng: 2.3522}, s.Location.MustGet())
+
+		require.NoError(t, json.Unmarshal([]byte(`{"location":null}`), &s))
+		assert.True(t, s.Location.IsNull())
+
+		for _, in := range []string{
+			`{"location":{"type":"LineString","coordinates":[[0,0],[1,1]]}}`,
+			`{"location":{"type":"Point","coordinates":[1]}}`,
+		} {
+			require.Error(t, json.Unmarshal([]byte(in), &s), in)
+		}
+	})
+}
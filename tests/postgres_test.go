@@ -11,8 +11,11 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	"github.com/pivaldi/presence"
+	presencegorm "github.com/pivaldi/presence/contrib/gorm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 // TypeTest represents all supported presence types
@@ -330,3 +333,36 @@ func TestInsertAndReadWithSqlx(t *testing.T) {
 		assert.True(t, *readTest.Data.GetValue().Bool.GetValue(), "Data.Bool should be true")
 	})
 }
+
+func TestGormAuditNotNull(t *testing.T) {
+	stdDB := getDB(t)
+
+	_, err := stdDB.Exec(`
+		DROP TABLE IF EXISTS audit_test;
+		CREATE TABLE audit_test (
+			id SERIAL PRIMARY KEY,
+			email VARCHAR(255),
+			nickname VARCHAR(255),
+			name VARCHAR(255) NOT NULL
+		);
+		INSERT INTO audit_test (email, nickname, name) VALUES ('ada@example.com', NULL, 'Ada'), ('alan@example.com', 'al', 'Alan');
+	`)
+	require.NoError(t, err)
+
+	db, err := gorm.Open(gormpostgres.New(gormpostgres.Config{Conn: stdDB}), &gorm.Config{})
+	require.NoError(t, err)
+
+	suggestions, err := presencegorm.AuditNotNull(db, 0, "audit_test")
+	require.NoError(t, err)
+	assert.Equal(t, []presencegorm.NotNullSuggestion{{
+		Table: "audit_test",
+		Stats: presence.FieldStats{Field: "email", Value: 2},
+		SQL:   `ALTER TABLE "audit_test" ALTER COLUMN "email" SET NOT NULL;`,
+	}}, suggestions)
+
+	// The first row only: nickname is null
+	suggestions, err = presencegorm.AuditNotNull(db, 1, "audit_test")
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, 1, suggestions[0].Stats.Total())
+}
@@ -5,7 +5,9 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pivaldi/presence"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -120,6 +122,103 @@ func TestScanNullBehavior(t *testing.T) {
 	})
 }
 
+// Tests for scanning times from driver strings
+
+func TestScanTimeStrings(t *testing.T) {
+	paris := time.FixedZone("", 2*60*60)
+
+	cases := []struct {
+		name string
+		src  any
+		want time.Time
+	}{
+		{"RFC3339", "2024-03-01T10:20:30Z", time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"RFC3339 with offset", "2024-03-01T10:20:30+02:00", time.Date(2024, 3, 1, 10, 20, 30, 0, paris)},
+		{"RFC3339 fractional", "2024-03-01T10:20:30.123456Z", time.Date(2024, 3, 1, 10, 20, 30, 123456000, time.UTC)},
+		{"RFC3339 without zone", "2024-03-01T10:20:30", time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"MySQL DATETIME", "2024-03-01 10:20:30", time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"MySQL DATETIME(6)", "2024-03-01 10:20:30.999999", time.Date(2024, 3, 1, 10, 20, 30, 999999000, time.UTC)},
+		{"SQLite with offset", "2024-03-01 10:20:30.5+02:00", time.Date(2024, 3, 1, 10, 20, 30, 500000000, paris)},
+		{"Postgres text", "2024-03-01 10:20:30+02", time.Date(2024, 3, 1, 10, 20, 30, 0, paris)},
+		{"Go String()", "2024-03-01 10:20:30 +0000 UTC", time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"date only", "2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"bytes", []byte("2024-03-01 10:20:30"), time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := presence.Of[time.Time]{}
+			require.NoError(t, n.Scan(tc.src))
+			assert.True(t, n.MustGet().Equal(tc.want), "got %v", n.MustGet())
+		})
+	}
+
+	t.Run("invalid string", func(t *testing.T) {
+		n := presence.Of[time.Time]{}
+		require.Error(t, n.Scan("not a time"))
+		assert.True(t, n.IsUnset())
+	})
+}
+
+// Tests for scanning UUIDs from alternate encodings
+
+func TestScanUUIDEncodings(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	cases := []struct {
+		name string
+		src  any
+	}{
+		{"canonical string", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"braced string", "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"},
+		{"URN string", "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"hex string", "6ba7b8109dad11d180b400c04fd430c8"},
+		{"BINARY(16) bytes", id[:]},
+		{"text bytes", []byte("6ba7b810-9dad-11d1-80b4-00c04fd430c8")},
+		{"array", [16]byte(id)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := presence.Of[uuid.UUID]{}
+			require.NoError(t, n.Scan(tc.src))
+			assert.Equal(t, id, n.MustGet())
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		n := presence.Of[uuid.UUID]{}
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+	})
+
+	t.Run("invalid bytes", func(t *testing.T) {
+		n := presence.Of[uuid.UUID]{}
+		require.Error(t, n.Scan([]byte{1, 2, 3}))
+	})
+
+	t.Run("binary value", func(t *testing.T) {
+		presence.SetDefaultUUIDValue(presence.UUIDValueBinary)
+		defer presence.SetDefaultUUIDValue(presence.UUIDValueString)
+
+		n := presence.FromValue(id)
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Equal(t, id[:], v)
+
+		var back presence.Of[uuid.UUID]
+		require.NoError(t, back.Scan(v))
+		assert.Equal(t, id, back.MustGet())
+	})
+
+	t.Run("string value by default", func(t *testing.T) {
+		n := presence.FromValue(id)
+		v, err := n.Value()
+		require.NoError(t, err)
+		assert.Equal(t, id, v)
+	})
+}
+
 // Tests for Get method
 func TestGet(t *testing.T) {
 	t.Run("Get on value returns value and true", func(t *testing.T) {
@@ -208,6 +307,35 @@ func TestMustGet(t *testing.T) {
 	})
 }
 
+// Tests for MustGetNamed method
+func TestMustGetNamed(t *testing.T) {
+	t.Run("MustGetNamed on value returns value", func(t *testing.T) {
+		n := presence.FromValue("test")
+		assert.Equal(t, "test", n.MustGetNamed("name"))
+	})
+
+	t.Run("MustGetNamed on null panics with the field", func(t *testing.T) {
+		n := presence.Null[string]()
+		assert.PanicsWithValue(t, "presence: MustGetNamed called on null field email (*presence.Of[string])", func() {
+			n.MustGetNamed("email")
+		})
+	})
+
+	t.Run("MustGetNamed on unset panics with the field", func(t *testing.T) {
+		var n presence.Of[int]
+		assert.PanicsWithValue(t, "presence: MustGetNamed called on unset field age (*presence.Of[int])", func() {
+			n.MustGetNamed("age")
+		})
+	})
+
+	t.Run("MustGetNamed on nil receiver panics", func(t *testing.T) {
+		var n *presence.Of[int]
+		assert.Panics(t, func() {
+			n.MustGetNamed("age")
+		})
+	})
+}
+
 // Tests for Ptr method
 func TestPtr(t *testing.T) {
 	t.Run("Ptr on value returns pointer to value", func(t *testing.T) {
@@ -567,8 +695,46 @@ func TestInterfaceCompliance(t *testing.T) {
 		require.NotNil(t, ptr)
 		assert.Equal(t, "test", *ptr)
 	})
+
+	t.Run("Of implements Getter and Setter", func(t *testing.T) {
+		var val presence.Of[string]
+		var setter presence.Setter[string] = &val
+		var getter presence.Getter[string] = &val
+
+		setter.SetValue("test")
+		assert.Equal(t, "test", getter.MustGet())
+
+		setter.SetNull()
+		assert.Equal(t, presence.StateNull, getter.State())
+	})
+
+	t.Run("Getter is enough for read-only APIs", func(t *testing.T) {
+		describe := func(g presence.Getter[int]) string {
+			return g.State().String()
+		}
+
+		assert.Equal(t, "value", describe(stateGetter{state: presence.StateValue}))
+		val := presence.FromValue(1)
+		assert.Equal(t, "value", describe(&val))
+	})
+}
+
+// stateGetter is a read-only presence.Getter, as a repository mock would be.
+type stateGetter struct {
+	state presence.State
 }
 
+func (g stateGetter) IsNull() bool               { return g.state == presence.StateNull }
+func (g stateGetter) IsUnset() bool              { return g.state == presence.StateUnset }
+func (g stateGetter) IsSet() bool                { return g.state != presence.StateUnset }
+func (g stateGetter) IsValue() bool              { return g.state == presence.StateValue }
+func (g stateGetter) State() presence.State      { return g.state }
+func (g stateGetter) GetValue() *int             { return nil }
+func (g stateGetter) Get() (int, bool)           { return 0, g.IsValue() }
+func (g stateGetter) GetOr(defaultValue int) int { return defaultValue }
+func (g stateGetter) MustGet() int               { return 0 }
+func (g stateGetter) Ptr() *int                  { return nil }
+
 // Tests for combined functional operations
 func TestCombinedOperations(t *testing.T) {
 	t.Run("Map then Filter", func(t *testing.T) {
@@ -616,3 +782,163 @@ func TestCombinedOperations(t *testing.T) {
 		assert.Equal(t, "BACKUP", mapped.MustGet())
 	})
 }
+
+// Tests for column-oriented helpers
+func TestPtrs(t *testing.T) {
+	t.Run("Ptrs maps null and unset to nil", func(t *testing.T) {
+		values := []presence.Of[string]{presence.FromValue("a"), presence.Null[string](), {}}
+		ptrs := presence.Ptrs(values)
+		require.Len(t, ptrs, 3)
+		require.NotNil(t, ptrs[0])
+		assert.Equal(t, "a", *ptrs[0])
+		assert.Nil(t, ptrs[1])
+		assert.Nil(t, ptrs[2])
+	})
+
+	t.Run("FromPtrs maps nil to null", func(t *testing.T) {
+		v := 42
+		values := presence.FromPtrs([]*int{&v, nil})
+		require.Len(t, values, 2)
+		assert.Equal(t, 42, values[0].MustGet())
+		assert.True(t, values[1].IsNull())
+	})
+}
+
+// Tests for As
+func TestAs(t *testing.T) {
+	type userID int64
+
+	id := presence.FromValue[userID](7)
+	values := map[string]any{
+		"name":  presence.FromValue("Ada"),
+		"email": presence.Null[string](),
+		"bio":   presence.Of[string]{},
+		"alias": presence.NewString("ada"),
+		"id":    &id,
+		"age":   presence.FromValue[int32](36),
+	}
+
+	t.Run("same type keeps the state", func(t *testing.T) {
+		name, ok := presence.As[string](values["name"])
+		require.True(t, ok)
+		assert.Equal(t, "Ada", name.MustGet())
+
+		email, ok := presence.As[string](values["email"])
+		require.True(t, ok)
+		assert.True(t, email.IsNull())
+
+		bio, ok := presence.As[string](values["bio"])
+		require.True(t, ok)
+		assert.True(t, bio.IsUnset())
+	})
+
+	t.Run("named types and pointers", func(t *testing.T) {
+		alias, ok := presence.As[string](values["alias"])
+		require.True(t, ok)
+		assert.Equal(t, "ada", alias.MustGet())
+
+		id, ok := presence.As[userID](values["id"])
+		require.True(t, ok)
+		assert.Equal(t, userID(7), id.MustGet())
+
+		unset, ok := presence.As[string]((*presence.String)(nil))
+		require.True(t, ok)
+		assert.True(t, unset.IsUnset())
+	})
+
+	t.Run("convertible values", func(t *testing.T) {
+		age, ok := presence.As[int64](values["age"])
+		require.True(t, ok)
+		assert.Equal(t, int64(36), age.MustGet())
+
+		id, ok := presence.As[int64](values["id"])
+		require.True(t, ok)
+		assert.Equal(t, int64(7), id.MustGet())
+	})
+
+	t.Run("mismatches", func(t *testing.T) {
+		_, ok := presence.As[time.Time](values["name"])
+		assert.False(t, ok)
+
+		_, ok = presence.As[string]("Ada")
+		assert.False(t, ok)
+
+		_, ok = presence.As[string](nil)
+		assert.False(t, ok)
+	})
+}
+
+// Tests for With* builders
+func TestWithBuilders(t *testing.T) {
+	base := presence.FromValue("a")
+	base.SetMarshalUnset(presence.UnsetSkip)
+
+	t.Run("copies leave the original untouched", func(t *testing.T) {
+		b := base.WithValue("b")
+		null := base.WithNull()
+		unset := base.WithUnset()
+
+		assert.Equal(t, "a", base.MustGet())
+		assert.Equal(t, "b", b.MustGet())
+		assert.True(t, null.IsNull())
+		assert.True(t, unset.IsUnset())
+	})
+
+	t.Run("behavior settings are kept", func(t *testing.T) {
+		unset := base.WithUnset()
+		assert.Equal(t, presence.UnsetSkip, unset.GetMarshalUnset())
+		assert.True(t, unset.IsZero())
+	})
+
+	t.Run("chaining in table-driven tests", func(t *testing.T) {
+		cases := []presence.Of[int]{
+			presence.Of[int]{}.WithValue(1),
+			presence.Of[int]{}.WithValue(1).WithNull(),
+			presence.Null[int]().WithUnset(),
+		}
+
+		assert.Equal(t, presence.StateValue, cases[0].State())
+		assert.Equal(t, presence.StateNull, cases[1].State())
+		assert.Equal(t, presence.StateUnset, cases[2].State())
+	})
+
+	t.Run("copies do not share the value", func(t *testing.T) {
+		b := base.WithValue("b")
+		*b.Ptr() = "c"
+		assert.Equal(t, "a", base.MustGet())
+	})
+}
+
+func TestMustFromJSON(t *testing.T) {
+	assert.Equal(t, presence.FromValue(42), presence.MustFromJSON[int]("42"))
+	assert.Equal(t, presence.FromValue([]string{"a", "b"}), presence.MustFromJSON[[]string](`["a","b"]`))
+	assert.Equal(t, presence.Null[string](), presence.MustFromJSON[string]("null"))
+	assert.Equal(t, presence.Of[string]{}, presence.MustFromJSON[string](""))
+
+	defer func() {
+		assert.Contains(t, recover(), `presence: MustFromJSON[int]("\"x\"") : `)
+	}()
+	presence.MustFromJSON[int](`"x"`)
+}
+
+func TestFromSQL(t *testing.T) {
+	email, err := presence.FromSQL[string]([]byte("ada@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, presence.FromValue("ada@example.com"), email)
+
+	age, err := presence.FromSQL[int64](int64(36))
+	require.NoError(t, err)
+	assert.Equal(t, presence.FromValue(int64(36)), age)
+
+	tags, err := presence.FromSQL[[]string](`["a","b"]`)
+	require.NoError(t, err)
+	assert.Equal(t, presence.FromValue([]string{"a", "b"}), tags)
+
+	null, err := presence.FromSQL[time.Time](nil)
+	require.NoError(t, err)
+	assert.True(t, null.IsNull())
+
+	invalid, err := presence.FromSQL[int]("x")
+	require.Error(t, err)
+	assert.True(t, invalid.IsUnset())
+}
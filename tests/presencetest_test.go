@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/pivaldi/presence/presencetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type goldenAddress struct {
+	City presence.Of[string] `json:"city"`
+	Zip  presence.Of[string] `json:"zip"`
+}
+
+type goldenAudit struct {
+	UpdatedBy presence.Of[string] `json:"updated_by"`
+}
+
+type goldenUser struct {
+	goldenAudit
+
+	ID        int                            `json:"id"`
+	Name      presence.Of[string]            `json:"name"`
+	Email     presence.Of[string]            `json:"email"`
+	Age       presence.Of[int]               `json:"age,omitzero"`
+	Address   presence.Of[goldenAddress]     `json:"address"`
+	Previous  []goldenAddress                `json:"previous"`
+	Labels    map[string]presence.Of[string] `json:"labels"`
+	Reference *goldenAddress                 `json:"reference"`
+}
+
+// Tests for presencetest
+
+func TestGoldenRoundTrip(t *testing.T) {
+	user := goldenUser{
+		goldenAudit: goldenAudit{UpdatedBy: presence.FromValue("admin")},
+		ID:          7,
+		Name:        presence.FromValue("Ada"),
+		Email:       presence.Null[string](),
+		Address:     presence.FromValue(goldenAddress{City: presence.FromValue("Paris")}),
+		Previous:    []goldenAddress{{Zip: presence.Null[string]()}},
+		Labels:      map[string]presence.Of[string]{"team": presence.FromValue("core")},
+		Reference:   &goldenAddress{},
+	}
+
+	b, err := presencetest.Marshal(user)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"updated_by": "admin",
+		"id": 7,
+		"name": "Ada",
+		"email": null,
+		"address": {"city": "Paris"},
+		"previous": [{"zip": null}],
+		"labels": {"team": "core"},
+		"reference": {}
+	}`, string(b))
+
+	path := filepath.Join(t.TempDir(), "golden", "user.json")
+	require.NoError(t, presencetest.SaveGolden(path, user))
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, b, saved)
+
+	var got goldenUser
+	require.NoError(t, presencetest.LoadFixture(path, &got))
+	assert.Equal(t, user, got)
+}
+
+func TestLoadFixtureErrors(t *testing.T) {
+	var user goldenUser
+
+	err := presencetest.LoadFixture(filepath.Join(t.TempDir(), "missing.json"), &user)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "typo.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"nmae": "Ada"}`), 0o600))
+	require.ErrorContains(t, presencetest.LoadFixture(path, &user), "nmae")
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapRow struct {
+	ID        int64                 `db:"id"`
+	Email     presence.Of[string]   `db:"email_address" json:"email"`
+	Age       presence.Of[int]      `json:"age"`
+	Tags      presence.Of[[]string] `json:"tags"`
+	CreatedAt presence.Of[time.Time]
+	Nickname  *string `json:"nickname"`
+	Score     float64 `json:"score"`
+}
+
+// Tests for ScanMap
+
+func TestScanMap(t *testing.T) {
+	t.Run("missing is unset, nil is null", func(t *testing.T) {
+		row := mapRow{Age: presence.FromValue(1)}
+		require.NoError(t, presence.ScanMap(map[string]any{"id": int64(1), "email_address": nil}, &row))
+
+		assert.Equal(t, mapRow{ID: 1, Email: presence.Null[string]()}, row)
+	})
+
+	t.Run("converts values", func(t *testing.T) {
+		var row mapRow
+		require.NoError(t, presence.ScanMap(map[string]any{
+			"id":            int32(7),
+			"email_address": []byte("ada@b.c"),
+			"age":           "36",
+			"tags":          []string{"go"},
+			"CreatedAt":     "2024-01-02T03:04:05Z",
+			"nickname":      "ada",
+			"score":         float32(1.5),
+		}, &row))
+
+		nickname := "ada"
+		assert.Equal(t, mapRow{
+			ID:        7,
+			Email:     presence.FromValue("ada@b.c"),
+			Age:       presence.FromValue(36),
+			Tags:      presence.FromValue([]string{"go"}),
+			CreatedAt: presence.FromValue(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Nickname:  &nickname,
+			Score:     1.5,
+		}, row)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var row mapRow
+		require.ErrorContains(t, presence.ScanMap(map[string]any{"age": "old"}, &row), "age")
+		require.Error(t, presence.ScanMap(map[string]any{}, row))
+	})
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+)
+
+type sliceUser struct {
+	ID    int
+	Email presence.Of[string]
+}
+
+func sliceUserEmail(u sliceUser) presence.Of[string] { return u.Email }
+
+func sliceUsers() []sliceUser {
+	return []sliceUser{
+		{ID: 1, Email: presence.FromValue("Ada@Example.com")},
+		{ID: 2, Email: presence.Null[string]()},
+		{ID: 3},
+		{ID: 4, Email: presence.FromValue("bob@example.org")},
+	}
+}
+
+// Tests for MapSlice, FilterSlice and GroupByPresence
+
+func TestMapSlice(t *testing.T) {
+	got := presence.MapSlice(sliceUsers(), sliceUserEmail, strings.ToLower)
+	assert.Equal(t, []presence.Of[string]{
+		presence.FromValue("ada@example.com"),
+		presence.Null[string](),
+		{},
+		presence.FromValue("bob@example.org"),
+	}, got)
+
+	assert.Empty(t, presence.MapSlice(nil, sliceUserEmail, strings.ToLower))
+}
+
+func TestFilterSlice(t *testing.T) {
+	got := presence.FilterSlice(sliceUsers(), sliceUserEmail, func(email string) bool {
+		return strings.HasSuffix(email, ".com")
+	})
+	assert.Equal(t, []sliceUser{{ID: 1, Email: presence.FromValue("Ada@Example.com")}}, got)
+
+	all := presence.FilterSlice(sliceUsers(), sliceUserEmail, func(string) bool { return true })
+	assert.Len(t, all, 2, "null and unset fields are dropped")
+}
+
+func TestGroupByPresence(t *testing.T) {
+	groups := presence.GroupByPresence(sliceUsers(), sliceUserEmail)
+
+	ids := func(users []sliceUser) []int {
+		out := make([]int, 0, len(users))
+		for _, u := range users {
+			out = append(out, u.ID)
+		}
+
+		return out
+	}
+	assert.Equal(t, []int{1, 4}, ids(groups[presence.StateValue]))
+	assert.Equal(t, []int{2}, ids(groups[presence.StateNull]))
+	assert.Equal(t, []int{3}, ids(groups[presence.StateUnset]))
+
+	assert.Empty(t, presence.GroupByPresence(nil, sliceUserEmail))
+}
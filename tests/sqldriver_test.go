@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureDriver is a database/sql driver recording the arguments of the executed
+// statements and answering queries with a single row of preset values.
+type captureDriver struct {
+	mu   sync.Mutex
+	args []driver.Value
+	row  []driver.Value
+}
+
+func (d *captureDriver) Open(string) (driver.Conn, error) { return &captureConn{d: d}, nil }
+
+type captureConn struct{ d *captureDriver }
+
+func (c *captureConn) Prepare(string) (driver.Stmt, error) { return &captureStmt{d: c.d}, nil }
+func (c *captureConn) Close() error                        { return nil }
+func (c *captureConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type captureStmt struct{ d *captureDriver }
+
+func (s *captureStmt) Close() error  { return nil }
+func (s *captureStmt) NumInput() int { return -1 }
+
+func (s *captureStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.args = args
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *captureStmt) Query([]driver.Value) (driver.Rows, error) {
+	columns := make([]string, len(s.d.row))
+	for i := range columns {
+		columns[i] = "c"
+	}
+
+	return &captureRows{columns: columns, row: s.d.row}, nil
+}
+
+type captureRows struct {
+	columns []string
+	row     []driver.Value
+	done    bool
+}
+
+func (r *captureRows) Columns() []string { return r.columns }
+func (r *captureRows) Close() error      { return nil }
+
+func (r *captureRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	copy(dest, r.row)
+
+	return nil
+}
+
+var (
+	capture     = &captureDriver{}
+	captureOnce sync.Once
+)
+
+// openCaptureDB returns a database backed by the capture driver.
+func openCaptureDB(t *testing.T) *sql.DB {
+	t.Helper()
+	captureOnce.Do(func() { sql.Register("presence-capture", capture) })
+
+	db, err := sql.Open("presence-capture", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// execArgs executes a statement with args and returns the driver values database/sql bound.
+func execArgs(t *testing.T, db *sql.DB, args ...any) ([]driver.Value, error) {
+	t.Helper()
+
+	_, err := db.Exec("INSERT", args...)
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+
+	return capture.args, err
+}
+
+type sqlModel struct {
+	Name  presence.Of[string]
+	Email presence.String
+	Age   *presence.Of[int64]
+}
+
+// Tests for the database/sql argument conversion of value and pointer models
+
+func TestDatabaseSQLArguments(t *testing.T) {
+	db := openCaptureDB(t)
+	age := presence.FromValue(int64(42))
+	nullAge := presence.Null[int64]()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		arg  any
+		want driver.Value
+	}{
+		{"value", presence.FromValue("a"), "a"},
+		{"pointer to value", &age, int64(42)},
+		{"null value", presence.Null[string](), nil},
+		{"pointer to null", &nullAge, nil},
+		{"unset value", presence.Of[string]{}, nil},
+		{"pointer to unset", &presence.Of[string]{}, nil},
+		{"nil pointer", (*presence.Of[int64])(nil), nil},
+		{"named type", presence.NewString("b"), "b"},
+		{"pointer to named type", &presence.Time{Of: presence.FromValue(now)}, now},
+		{"nil named type pointer", (*presence.String)(nil), nil},
+		{"json", presence.FromValue([]int{1, 2}), "[1,2]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := execArgs(t, db, tc.arg)
+			require.NoError(t, err)
+			assert.Equal(t, []driver.Value{tc.want}, args)
+		})
+	}
+
+	t.Run("struct vs pointer model fields", func(t *testing.T) {
+		model := sqlModel{Name: presence.FromValue("Ada"), Age: &age}
+		byValue, err := execArgs(t, db, model.Name, model.Email, model.Age)
+		require.NoError(t, err)
+
+		ptr := &model
+		byPointer, err := execArgs(t, db, &ptr.Name, &ptr.Email, ptr.Age)
+		require.NoError(t, err)
+
+		assert.Equal(t, []driver.Value{"Ada", nil, int64(42)}, byValue)
+		assert.Equal(t, byValue, byPointer)
+	})
+
+	t.Run("unset with ValueUnsetDefault is rejected by database/sql", func(t *testing.T) {
+		var n presence.Of[string]
+		n.SetValueUnset(presence.ValueUnsetDefault)
+		_, err := execArgs(t, db, n)
+		require.Error(t, err)
+	})
+
+	t.Run("unset with ValueUnsetError fails the statement", func(t *testing.T) {
+		var n presence.Of[string]
+		n.SetValueUnset(presence.ValueUnsetError)
+		_, err := execArgs(t, db, &n)
+		require.ErrorIs(t, err, presence.ErrUnsetValue)
+	})
+}
+
+func TestDatabaseSQLScan(t *testing.T) {
+	db := openCaptureDB(t)
+	capture.mu.Lock()
+	capture.row = []driver.Value{"Ada", nil, int64(42)}
+	capture.mu.Unlock()
+
+	t.Run("into value fields", func(t *testing.T) {
+		var model sqlModel
+		model.Age = &presence.Of[int64]{}
+		require.NoError(t, db.QueryRow("SELECT").Scan(&model.Name, &model.Email, model.Age))
+		assert.Equal(t, "Ada", model.Name.MustGet())
+		assert.True(t, model.Email.IsNull())
+		assert.Equal(t, int64(42), model.Age.MustGet())
+	})
+
+	t.Run("NULL into an unset value with ScanNullAsUnset", func(t *testing.T) {
+		var name, email presence.Of[string]
+		var age presence.Of[int64]
+		email.SetScanNull(presence.ScanNullAsUnset)
+		require.NoError(t, db.QueryRow("SELECT").Scan(&name, &email, &age))
+		assert.True(t, email.IsUnset())
+	})
+}
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	t.Run("State reflects the three states", func(t *testing.T) {
+		var unset presence.Of[string]
+		null := presence.Null[string]()
+		value := presence.FromValue("x")
+
+		assert.Equal(t, presence.StateUnset, unset.State())
+		assert.Equal(t, presence.StateNull, null.State())
+		assert.Equal(t, presence.StateValue, value.State())
+	})
+
+	t.Run("State on nil receiver is unset", func(t *testing.T) {
+		var n *presence.Of[int]
+		assert.Equal(t, presence.StateUnset, n.State())
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "unset", presence.StateUnset.String())
+		assert.Equal(t, "null", presence.StateNull.String())
+		assert.Equal(t, "value", presence.StateValue.String())
+		assert.Equal(t, "unknown", presence.State(42).String())
+	})
+}
+
+func TestMatch(t *testing.T) {
+	describe := func(n presence.Of[int]) string {
+		return presence.Match(n,
+			func() string { return "unset" },
+			func() string { return "null" },
+			func(v int) string { return "value" },
+		)
+	}
+
+	assert.Equal(t, "unset", describe(presence.Of[int]{}))
+	assert.Equal(t, "null", describe(presence.Null[int]()))
+	assert.Equal(t, "value", describe(presence.FromValue(1)))
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statsRow struct {
+	ID    int64               `db:"id"`
+	Email presence.Of[string] `db:"email"`
+	Age   presence.Of[int]    `db:"age"`
+}
+
+// Tests for Stats
+
+func TestStats(t *testing.T) {
+	rows := []statsRow{
+		{ID: 1, Email: presence.FromValue("ada@example.com"), Age: presence.FromValue(36)},
+		{ID: 2, Email: presence.FromValue("grace@example.com"), Age: presence.Null[int]()},
+		{ID: 3, Email: presence.FromValue("alan@example.com")},
+	}
+
+	t.Run("counts", func(t *testing.T) {
+		stats, err := presence.Stats(rows, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []presence.FieldStats{
+			{Field: "email", Value: 3},
+			{Field: "age", Unset: 1, Null: 1, Value: 1},
+		}, stats)
+		assert.Equal(t, 3, stats[1].Total())
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		stats, err := presence.Stats([]*statsRow{&rows[1]}, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, presence.FieldStats{Field: "age", Null: 1}, stats[1])
+
+		_, err = presence.Stats([]*statsRow{nil})
+		require.Error(t, err)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		stats, err := presence.Stats([]statsRow(nil), presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []presence.FieldStats{{Field: "email"}, {Field: "age"}}, stats)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := presence.Stats([]int{1})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,732 @@
+package tests
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"github.com/pivaldi/presence/naming"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditFields struct {
+	UpdatedBy presence.Of[string] `json:"updated_by" db:"updated_by"`
+}
+
+type userPatch struct {
+	auditFields
+	Name     presence.Of[string] `json:"name" db:"full_name"`
+	Email    presence.String     `json:"email" db:"email_address"`
+	Age      presence.Of[int]    `json:"age" db:"age"`
+	Internal presence.Of[string] `json:"-" db:"internal"`
+	Plain    string              `json:"plain"`
+}
+
+type userEntity struct {
+	Name      string
+	Email     *string             `json:"email"`
+	Age       presence.Of[int64]  `json:"age"`
+	UpdatedBy presence.Of[string] `json:"updated_by"`
+}
+
+// Tests for ToMap
+
+func TestToMap(t *testing.T) {
+	patch := userPatch{
+		Name: presence.FromValue("Ada"),
+		Age:  presence.Null[int](),
+	}
+	patch.UpdatedBy.SetValue("admin")
+
+	t.Run("json names by default", func(t *testing.T) {
+		m, err := presence.ToMap(patch)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Ada", "age": nil, "updated_by": "admin"}, m)
+	})
+
+	t.Run("db tag namespace", func(t *testing.T) {
+		m, err := presence.ToMap(&patch, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"full_name": "Ada", "age": nil, "updated_by": "admin"}, m)
+	})
+
+	t.Run("missing tag falls back to the field name", func(t *testing.T) {
+		m, err := presence.ToMap(patch, presence.WithTag("bson"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"Name": "Ada", "Age": nil, "UpdatedBy": "admin"}, m)
+	})
+
+	t.Run("naming strategy for untagged fields", func(t *testing.T) {
+		m, err := presence.ToMap(patch, presence.WithTag("bson"), presence.WithNaming(naming.Snake))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Ada", "age": nil, "updated_by": "admin"}, m)
+
+		m, err = presence.ToMap(patch, presence.WithTag("bson"), presence.WithNaming(naming.Func(strings.ToUpper)))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"NAME": "Ada", "AGE": nil, "UPDATEDBY": "admin"}, m)
+	})
+
+	t.Run("named types are presence fields", func(t *testing.T) {
+		m, err := presence.ToMap(userPatch{Email: presence.NewString("a@b.c")})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"email": "a@b.c"}, m)
+	})
+
+	t.Run("non struct", func(t *testing.T) {
+		_, err := presence.ToMap(42)
+		require.Error(t, err)
+	})
+}
+
+type addressPatch struct {
+	City presence.Of[string] `json:"city"`
+	Zip  presence.Of[string] `json:"zip"`
+}
+
+type contactPatch struct {
+	Email   presence.Of[string]       `json:"email"`
+	Address presence.Of[addressPatch] `json:"address"`
+	Billing addressPatch              `json:"billing"`
+}
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type contact struct {
+	Email   string                    `json:"email"`
+	Address presence.Of[addressPatch] `json:"address"`
+	Billing *address                  `json:"billing"`
+}
+
+func TestToMapNested(t *testing.T) {
+	patch := contactPatch{
+		Email:   presence.FromValue("a@b.c"),
+		Address: presence.FromValue(addressPatch{City: presence.FromValue("Paris"), Zip: presence.Null[string]()}),
+	}
+	patch.Billing.City.SetValue("Lyon")
+
+	t.Run("values by default", func(t *testing.T) {
+		m, err := presence.ToMap(patch)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"email": "a@b.c", "address": patch.Address.MustGet()}, m)
+	})
+
+	t.Run("nested maps", func(t *testing.T) {
+		m, err := presence.ToMap(patch, presence.WithNested(presence.NestedMaps))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"email":   "a@b.c",
+			"address": map[string]any{"city": "Paris", "zip": nil},
+			"billing": map[string]any{"city": "Lyon"},
+		}, m)
+	})
+
+	t.Run("dotted keys", func(t *testing.T) {
+		m, err := presence.ToMap(&patch, presence.WithNested(presence.NestedFlatten))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"email": "a@b.c", "address.city": "Paris", "address.zip": nil, "billing.city": "Lyon",
+		}, m)
+	})
+
+	t.Run("null and empty nested structs", func(t *testing.T) {
+		p := contactPatch{Address: presence.Null[addressPatch]()}
+		for _, mode := range []presence.NestedMode{presence.NestedMaps, presence.NestedFlatten} {
+			m, err := presence.ToMap(p, presence.WithNested(mode))
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"address": nil}, m)
+		}
+	})
+}
+
+func TestPatchStructNested(t *testing.T) {
+	newContact := func() contact {
+		return contact{
+			Email:   "old@b.c",
+			Address: presence.FromValue(addressPatch{City: presence.FromValue("Nice"), Zip: presence.FromValue("06000")}),
+			Billing: &address{City: "Nice", Zip: "06000"},
+		}
+	}
+
+	patch := contactPatch{Address: presence.FromValue(addressPatch{City: presence.FromValue("Paris")})}
+	patch.Billing.Zip.SetValue("75001")
+
+	t.Run("replaces by default", func(t *testing.T) {
+		c := newContact()
+		require.NoError(t, presence.PatchStruct(&c, patch))
+		assert.Equal(t, patch.Address, c.Address)
+		assert.Equal(t, &address{City: "Nice", Zip: "06000"}, c.Billing)
+	})
+
+	t.Run("merges nested structs", func(t *testing.T) {
+		c := newContact()
+		require.NoError(t, presence.PatchStruct(&c, patch, presence.WithNested(presence.NestedFlatten)))
+		assert.Equal(t, "old@b.c", c.Email)
+
+		a := c.Address.MustGet()
+		assert.Equal(t, "Paris", a.City.MustGet())
+		assert.Equal(t, "06000", a.Zip.MustGet())
+		assert.Equal(t, &address{City: "Nice", Zip: "75001"}, c.Billing)
+	})
+
+	t.Run("into empty destinations", func(t *testing.T) {
+		var c contact
+		require.NoError(t, presence.PatchStruct(&c, patch, presence.WithNested(presence.NestedMaps)))
+		assert.Equal(t, patch.Address, c.Address)
+		assert.Equal(t, &address{Zip: "75001"}, c.Billing)
+	})
+
+	t.Run("nested errors name the path", func(t *testing.T) {
+		p := contactPatch{}
+		p.Billing.City.SetNull()
+		c := newContact()
+		err := presence.PatchStruct(&c, p, presence.WithNested(presence.NestedMaps))
+		require.ErrorIs(t, err, presence.ErrNullNotAllowed)
+
+		var errs presence.FieldErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, presence.FieldErrors{"billing.city": presence.ErrNullNotAllowed}, errs)
+
+		b, err := json.Marshal(errs)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors":{"billing.city":"must not be null"}}`, string(b))
+	})
+
+	t.Run("deeply nested errors are not wrapped at every level", func(t *testing.T) {
+		type inner struct {
+			Zip presence.Of[string] `json:"zip"`
+		}
+
+		type middle struct {
+			Inner presence.Of[inner] `json:"inner"`
+		}
+
+		type outer struct {
+			Middle presence.Of[middle] `json:"middle"`
+		}
+
+		type innerDst struct{ Zip string }
+
+		type middleDst struct{ Inner innerDst }
+
+		var dst struct{ Middle middleDst }
+
+		p := outer{Middle: presence.FromValue(middle{Inner: presence.FromValue(inner{Zip: presence.Null[string]()})})}
+		err := presence.PatchStruct(&dst, p, presence.WithNested(presence.NestedMaps), presence.WithTag(""))
+
+		var errs presence.FieldErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Equal(t, presence.FieldErrors{"Middle.Inner.Zip": presence.ErrNullNotAllowed}, errs)
+		assert.Equal(t, "presence field errors : Middle.Inner.Zip: "+presence.ErrNullNotAllowed.Error(), err.Error())
+	})
+}
+
+// Tests for Diff
+
+func TestDiff(t *testing.T) {
+	before := userPatch{Name: presence.FromValue("Ada"), Age: presence.FromValue(36)}
+	after := userPatch{Name: presence.FromValue("Ada"), Age: presence.Null[int](), Email: presence.NewString("a@b.c")}
+
+	changes, err := presence.Diff(before, &after)
+	require.NoError(t, err)
+	assert.Equal(t, []presence.Change{
+		{Field: "email", To: "a@b.c", FromState: presence.StateUnset, ToState: presence.StateValue},
+		{Field: "age", From: 36, FromState: presence.StateValue, ToState: presence.StateNull},
+	}, changes)
+
+	t.Run("db names", func(t *testing.T) {
+		changes, err := presence.Diff(before, after, presence.WithTag("db"))
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, "email_address", changes[0].Field)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		changes, err := presence.Diff(before, before)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("different types", func(t *testing.T) {
+		_, err := presence.Diff(before, userEntity{})
+		require.Error(t, err)
+	})
+}
+
+type measure struct {
+	Value presence.Of[float64]  `json:"value"`
+	Ratio presence.Of[float32]  `json:"ratio"`
+	At    presence.Time         `json:"at"`
+	Label presence.Of[string]   `json:"label"`
+	Tags  presence.Of[[]string] `json:"tags"`
+}
+
+func TestDiffTolerance(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 20, 30, 123456789, time.UTC)
+	tenth := 0.1
+	before := measure{
+		Value: presence.FromValue(tenth + 0.2),
+		Ratio: presence.FromValue(float32(0.5)),
+		At:    presence.NewTime(at),
+		Label: presence.FromValue("Temp"),
+	}
+	after := measure{
+		Value: presence.FromValue(0.3),
+		Ratio: presence.FromValue(float32(0.5000001)),
+		At:    presence.NewTime(at.Truncate(time.Microsecond).In(time.FixedZone("", 3600))),
+		Label: presence.FromValue("temp"),
+	}
+
+	t.Run("strict by default", func(t *testing.T) {
+		changes, err := presence.Diff(before, after)
+		require.NoError(t, err)
+		assert.Len(t, changes, 4)
+	})
+
+	t.Run("epsilon and granularity", func(t *testing.T) {
+		changes, err := presence.Diff(before, after,
+			presence.WithFloatEpsilon(1e-6), presence.WithTimeGranularity(time.Microsecond))
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "label", changes[0].Field)
+	})
+
+	t.Run("EqualFunc per type", func(t *testing.T) {
+		changes, err := presence.Diff(before, after,
+			presence.WithFloatEpsilon(1e-6), presence.WithTimeGranularity(time.Microsecond),
+			presence.EqualFunc(strings.EqualFold))
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("granularity does not hide real changes", func(t *testing.T) {
+		later := after
+		later.At = presence.NewTime(at.Add(time.Second))
+		changes, err := presence.Diff(before, later, presence.WithTimeGranularity(time.Millisecond),
+			presence.WithFloatEpsilon(1e-6), presence.EqualFunc(strings.EqualFold))
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "at", changes[0].Field)
+	})
+
+	t.Run("state changes are always reported", func(t *testing.T) {
+		nulled := after
+		nulled.Value = presence.Null[float64]()
+		changes, err := presence.Diff(before, nulled, presence.EqualFunc(func(a, b float64) bool { return true }))
+		require.NoError(t, err)
+		assert.Equal(t, "value", changes[0].Field)
+	})
+
+	t.Run("slices still compare deeply", func(t *testing.T) {
+		a := measure{Tags: presence.FromValue([]string{"a"})}
+		b := measure{Tags: presence.FromValue([]string{"a"})}
+		changes, err := presence.Diff(a, b, presence.WithFloatEpsilon(1))
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+// Tests for PatchStruct
+
+func TestPatchStruct(t *testing.T) {
+	newEntity := func() userEntity {
+		email := "old@b.c"
+
+		return userEntity{Name: "Old", Email: &email, Age: presence.FromValue(int64(20))}
+	}
+
+	t.Run("applies set fields only", func(t *testing.T) {
+		entity := newEntity()
+		patch := userPatch{Email: presence.NewString("new@b.c"), Age: presence.FromValue(21)}
+		require.NoError(t, presence.PatchStruct(&entity, patch))
+		assert.Equal(t, "Old", entity.Name)
+		assert.Equal(t, "new@b.c", *entity.Email)
+		assert.Equal(t, int64(21), entity.Age.MustGet())
+		assert.True(t, entity.UpdatedBy.IsUnset())
+	})
+
+	t.Run("null clears pointers and presence fields", func(t *testing.T) {
+		entity := newEntity()
+		patch := userPatch{Email: presence.String{Of: presence.Null[string]()}, Age: presence.Null[int]()}
+		require.NoError(t, presence.PatchStruct(&entity, patch))
+		assert.Nil(t, entity.Email)
+		assert.True(t, entity.Age.IsNull())
+	})
+
+	t.Run("null on a plain field", func(t *testing.T) {
+		entity := newEntity()
+		err := presence.PatchStruct(&entity, userPatch{Name: presence.Null[string]()}, presence.WithTag("bson"))
+		require.ErrorIs(t, err, presence.ErrNullNotAllowed)
+		assert.Contains(t, err.Error(), "Name")
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		type strictEntity struct {
+			Name  string `json:"name"`
+			Age   int    `json:"age"`
+			Email string `json:"email"`
+		}
+
+		var entity strictEntity
+		err := presence.PatchStruct(&entity, userPatch{
+			Name:  presence.Null[string](),
+			Age:   presence.Null[int](),
+			Email: presence.NewString("ada@example.com"),
+		})
+
+		var errs presence.FieldErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 2)
+		require.ErrorIs(t, errs["name"], presence.ErrNullNotAllowed)
+		require.ErrorIs(t, errs["age"], presence.ErrNullNotAllowed)
+		assert.Equal(t, "ada@example.com", entity.Email, "the other fields are patched")
+
+		b, err := json.Marshal(errs)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors":{"name":"must not be null","age":"must not be null"}}`, string(b))
+	})
+
+	t.Run("embedded patch fields", func(t *testing.T) {
+		entity := newEntity()
+		patch := userPatch{}
+		patch.UpdatedBy.SetValue("admin")
+		require.NoError(t, presence.PatchStruct(&entity, &patch))
+		assert.Equal(t, "admin", entity.UpdatedBy.MustGet())
+	})
+
+	t.Run("destination must be a pointer", func(t *testing.T) {
+		require.Error(t, presence.PatchStruct(newEntity(), userPatch{}))
+	})
+}
+
+// Tests for InsertColumnsValues
+
+type insertRow struct {
+	ID    int64               `db:"id"`
+	Name  presence.Of[string] `db:"name"`
+	Email presence.Of[string] `db:"email"`
+	Age   presence.Of[int]    `db:"age"`
+}
+
+func TestInsertColumnsValues(t *testing.T) {
+	rows := []insertRow{
+		{ID: 1, Name: presence.FromValue("Ada"), Email: presence.FromValue("ada@example.com")},
+		{ID: 2, Name: presence.Null[string](), Age: presence.FromValue(36)},
+	}
+
+	t.Run("columns set in every row", func(t *testing.T) {
+		columns, values, err := presence.InsertColumnsValues(rows, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, columns)
+		assert.Equal(t, [][]any{
+			{int64(1), presence.FromValue("Ada")},
+			{int64(2), presence.Null[string]()},
+		}, values)
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		columns, values, err := presence.InsertColumnsValues(rows, presence.WithTag("db"),
+			presence.WithPadding(presence.Default))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "email", "age"}, columns)
+		assert.Equal(t, [][]any{
+			{int64(1), presence.FromValue("Ada"), presence.FromValue("ada@example.com"), presence.Default},
+			{int64(2), presence.Null[string](), presence.Default, presence.FromValue(36)},
+		}, values)
+
+		query, args := presence.InsertSQL("users", columns, values)
+		assert.Equal(t, "INSERT INTO users (id, name, email, age) VALUES (?, ?, ?, DEFAULT), (?, ?, DEFAULT, ?)", query)
+		assert.Equal(t, []any{
+			int64(1), presence.FromValue("Ada"), presence.FromValue("ada@example.com"),
+			int64(2), presence.Null[string](), presence.FromValue(36),
+		}, args)
+	})
+
+	t.Run("unset values configured with ValueUnsetDefault", func(t *testing.T) {
+		var age presence.Of[int]
+		age.SetValueUnset(presence.ValueUnsetDefault)
+
+		query, args := presence.InsertSQL("users", []string{"id", "age"},
+			[][]any{{int64(1), age}, {int64(2), presence.Of[int]{}}})
+		assert.Equal(t, "INSERT INTO users (id, age) VALUES (?, DEFAULT), (?, ?)", query)
+		assert.Equal(t, []any{int64(1), int64(2), presence.Of[int]{}}, args, "other unset values are bound")
+	})
+
+	t.Run("values encode through driver.Valuer", func(t *testing.T) {
+		columns, values, err := presence.InsertColumnsValues(rows[1:], presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "age"}, columns)
+
+		age, ok := values[0][2].(driver.Valuer)
+		require.True(t, ok)
+		v, err := age.Value()
+		require.NoError(t, err)
+		assert.Equal(t, 36, v)
+	})
+
+	t.Run("pointer rows", func(t *testing.T) {
+		columns, values, err := presence.InsertColumnsValues([]*insertRow{&rows[0]}, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "email"}, columns)
+		assert.Len(t, values, 1)
+	})
+
+	t.Run("non struct", func(t *testing.T) {
+		_, _, err := presence.InsertColumnsValues([]int{1})
+		require.Error(t, err)
+	})
+}
+
+// Tests for the option types
+
+func TestOptionTypes(t *testing.T) {
+	option := reflect.TypeFor[presence.Option]()
+
+	// The options of a single function are not struct-walking options.
+	for _, opt := range []any{
+		presence.WithPadding(nil), presence.WithKeepNulls(), presence.WithRemoveNull(),
+		presence.WithEscapeHTML(false), presence.WithIndent("", "  "),
+	} {
+		assert.False(t, reflect.TypeOf(opt).AssignableTo(option), "%T", opt)
+	}
+
+	// Struct-walking options still configure the functions walking structs.
+	assert.True(t, option.Implements(reflect.TypeFor[presence.InsertOption]()))
+	assert.True(t, option.Implements(reflect.TypeFor[presence.JSONPatchOption]()))
+	assert.False(t, option.AssignableTo(reflect.TypeFor[presence.MarshalOption]()))
+	assert.False(t, option.AssignableTo(reflect.TypeFor[presence.MergeOption]()))
+}
+
+// Tests for Mask and Project
+
+func TestMask(t *testing.T) {
+	patch := userPatch{Name: presence.FromValue("Ada"), Age: presence.Null[int](), Plain: "kept"}
+	patch.UpdatedBy.SetValue("admin")
+
+	require.NoError(t, presence.Mask(&patch, []string{"name", "updated_by", "unknown"}))
+	assert.Equal(t, "Ada", patch.Name.MustGet())
+	assert.Equal(t, "admin", patch.UpdatedBy.MustGet())
+	assert.True(t, patch.Age.IsUnset())
+	assert.Equal(t, "kept", patch.Plain)
+
+	t.Run("names follow the options", func(t *testing.T) {
+		p := userPatch{Name: presence.FromValue("Ada")}
+		require.NoError(t, presence.Mask(&p, []string{"name"}, presence.WithTag("db")))
+		assert.True(t, p.Name.IsUnset())
+	})
+
+	t.Run("target must be a pointer", func(t *testing.T) {
+		require.Error(t, presence.Mask(patch, nil))
+	})
+}
+
+func TestProject(t *testing.T) {
+	patch := userPatch{Name: presence.FromValue("Ada"), Age: presence.FromValue(36), Plain: "dropped"}
+	patch.UpdatedBy.SetValue("admin")
+
+	got, err := presence.Project(patch, []string{"age", "updated_by"})
+	require.NoError(t, err)
+
+	m, err := presence.ToMap(got)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"age": 36, "updated_by": "admin"}, m)
+	assert.Empty(t, got.Plain)
+	assert.Equal(t, "Ada", patch.Name.MustGet(), "v is left intact")
+	assert.Equal(t, "dropped", patch.Plain)
+
+	_, err = presence.Project(42, nil)
+	require.Error(t, err)
+}
+
+func TestPick(t *testing.T) {
+	patch := userPatch{Name: presence.FromValue("Ada"), Age: presence.FromValue(36), Plain: "dropped"}
+
+	got, err := presence.Pick(patch, "name")
+	require.NoError(t, err)
+
+	m, err := presence.ToMap(got)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "Ada"}, m)
+	assert.Empty(t, got.Plain)
+	assert.Equal(t, 36, patch.Age.MustGet(), "v is left intact")
+
+	_, err = presence.Pick(patch, "name", "nmae")
+	require.ErrorContains(t, err, "nmae")
+
+	_, err = presence.Pick(42)
+	require.Error(t, err)
+}
+
+// Tests for Sanitize
+
+type visibleUser struct {
+	auditFields
+	Name   presence.Of[string] `json:"name"`
+	Email  presence.Of[string] `json:"email" presence:"roles=admin|support"`
+	Salary presence.Of[int]    `json:"salary" presence:"roles=admin"`
+	Notes  string              `json:"notes" presence:"roles=admin"`
+}
+
+func TestSanitize(t *testing.T) {
+	newUser := func() visibleUser {
+		u := visibleUser{
+			Name:   presence.FromValue("Ada"),
+			Email:  presence.FromValue("ada@example.com"),
+			Salary: presence.FromValue(100),
+			Notes:  "secret",
+		}
+		u.UpdatedBy.SetValue("admin")
+
+		return u
+	}
+
+	tests := []struct {
+		role string
+		want map[string]any
+	}{
+		{"admin", map[string]any{"name": "Ada", "email": "ada@example.com", "salary": 100, "updated_by": "admin"}},
+		{"support", map[string]any{"name": "Ada", "email": "ada@example.com", "updated_by": "admin"}},
+		{"guest", map[string]any{"name": "Ada", "updated_by": "admin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			u := newUser()
+			require.NoError(t, presence.Sanitize(&u, tt.role))
+
+			m, err := presence.ToMap(u)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m)
+			assert.Equal(t, tt.role == "admin", u.Notes != "")
+		})
+	}
+
+	t.Run("target must be a pointer", func(t *testing.T) {
+		require.Error(t, presence.Sanitize(newUser(), "admin"))
+	})
+}
+
+func TestApplyToPointers(t *testing.T) {
+	type input struct {
+		Username presence.Of[string] `json:"username,omitempty"`
+		Email    presence.Of[string] `json:"email,omitempty"`
+		Bio      presence.Of[string] `json:"bio,omitempty"`
+		Age      presence.Of[int]    `json:"age,omitempty"`
+	}
+
+	type model struct {
+		ID       string
+		Username string
+		Email    *string
+		Bio      *string
+		Age      *int
+	}
+
+	bio := "Developer"
+	user := model{ID: "1", Username: "ada", Bio: &bio}
+
+	require.NoError(t, presence.ApplyToPointers(&user, input{
+		Email: presence.FromValue("ada@example.com"),
+		Bio:   presence.Null[string](),
+		Age:   presence.FromValue(36),
+	}))
+	assert.Equal(t, "ada", user.Username, "unset fields are untouched")
+	assert.Equal(t, "ada@example.com", *user.Email)
+	assert.Nil(t, user.Bio)
+	assert.Equal(t, 36, *user.Age)
+
+	err := presence.ApplyToPointers(&user, input{Username: presence.Null[string]()})
+	require.ErrorIs(t, err, presence.ErrNullNotAllowed)
+}
+
+// Tests for the Context variants
+
+// countdownContext is done after n calls to Err.
+type countdownContext struct {
+	context.Context
+
+	n int
+}
+
+func (c *countdownContext) Err() error {
+	c.n--
+	if c.n < 0 {
+		return context.Canceled
+	}
+
+	return nil
+}
+
+// wideStruct returns a struct with n set presence fields.
+func wideStruct(n int) any {
+	fields := make([]reflect.StructField, n)
+	for i := range fields {
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("F%d", i), Type: reflect.TypeFor[presence.Of[int]]()}
+	}
+
+	v := reflect.New(reflect.StructOf(fields))
+	for i := range n {
+		v.Elem().Field(i).Set(reflect.ValueOf(presence.FromValue(i)))
+	}
+
+	return v.Interface()
+}
+
+func TestContextVariants(t *testing.T) {
+	patch := userPatch{Name: presence.FromValue("Ada"), Email: presence.NewString("ada@example.com")}
+
+	t.Run("live context", func(t *testing.T) {
+		m, err := presence.ToMapContext(context.Background(), patch)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "Ada", "email": "ada@example.com"}, m)
+
+		changes, err := presence.DiffContext(context.Background(), userPatch{}, patch)
+		require.NoError(t, err)
+		assert.Len(t, changes, 2)
+
+		var dst userEntity
+		require.NoError(t, presence.PatchStructContext(context.Background(), &dst, patch))
+		assert.Equal(t, "ada@example.com", *dst.Email)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := presence.ToMapContext(ctx, patch)
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = presence.ToMapContext(ctx, patch, presence.WithNested(presence.NestedMaps))
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = presence.DiffContext(ctx, userPatch{}, patch)
+		require.ErrorIs(t, err, context.Canceled)
+
+		var dst userEntity
+		require.ErrorIs(t, presence.PatchStructContext(ctx, &dst, patch), context.Canceled)
+		assert.Nil(t, dst.Email)
+	})
+
+	t.Run("canceled while walking", func(t *testing.T) {
+		wide := wideStruct(200)
+
+		_, err := presence.ToMapContext(&countdownContext{Context: context.Background(), n: 2}, wide)
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = presence.DiffContext(&countdownContext{Context: context.Background(), n: 2}, wide, wide)
+		require.ErrorIs(t, err, context.Canceled)
+
+		m, err := presence.ToMapContext(&countdownContext{Context: context.Background(), n: 10}, wide)
+		require.NoError(t, err)
+		assert.Len(t, m, 200)
+	})
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type templateUser struct {
+	Name  presence.Of[string]
+	Email presence.Of[string]
+	Bio   presence.String
+	Age   *presence.Of[int]
+}
+
+func renderTemplate(t *testing.T, text string, data any) string {
+	t.Helper()
+
+	tmpl, err := template.New("t").Funcs(presence.TemplateFuncs()).Parse(text)
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.NoError(t, tmpl.Execute(&out, data))
+
+	return out.String()
+}
+
+// Tests for IsPresent and Any
+
+func TestTemplateMethods(t *testing.T) {
+	user := templateUser{Name: presence.FromValue("Ada"), Email: presence.Null[string]()}
+
+	t.Run("value receivers on non addressable fields", func(t *testing.T) {
+		out := renderTemplate(t, `{{if .Name.IsPresent}}{{.Name.Any}}{{end}}|{{.Email.IsPresent}}|{{.Email.Any}}`, user)
+		assert.Equal(t, "Ada|false|<no value>", out)
+	})
+
+	t.Run("embedding types", func(t *testing.T) {
+		out := renderTemplate(t, `{{.Bio.IsPresent}}`, templateUser{Bio: presence.NewString("x")})
+		assert.Equal(t, "true", out)
+	})
+
+	t.Run("direct calls", func(t *testing.T) {
+		assert.True(t, presence.FromValue(1).IsPresent())
+		assert.False(t, presence.Null[int]().IsPresent())
+		assert.Equal(t, 1, presence.FromValue(1).Any())
+		assert.Nil(t, presence.Of[int]{}.Any())
+	})
+}
+
+// Tests for TemplateFuncs
+
+func TestTemplateFuncs(t *testing.T) {
+	user := templateUser{Name: presence.FromValue("Ada"), Email: presence.Null[string]()}
+
+	t.Run("state", func(t *testing.T) {
+		out := renderTemplate(t, `{{state .Name}} {{state .Email}} {{state .Bio}} {{state .Age}}`, user)
+		assert.Equal(t, "value null unset unset", out)
+	})
+
+	t.Run("branching", func(t *testing.T) {
+		out := renderTemplate(t,
+			`{{if isValue .Name}}v{{end}}{{if isNull .Email}}n{{end}}{{if isUnset .Bio}}u{{end}}{{if isSet .Email}}s{{end}}`,
+			user)
+		assert.Equal(t, "vnus", out)
+	})
+
+	t.Run("values and nil pointers", func(t *testing.T) {
+		out := renderTemplate(t, `{{value .Name}} {{valueOr .Email "n/a"}} {{valueOr .Age 0}}`, user)
+		assert.Equal(t, "Ada n/a 0", out)
+
+		age := presence.FromValue(36)
+		out = renderTemplate(t, `{{valueOr .Age 0}}`, templateUser{Age: &age})
+		assert.Equal(t, "36", out)
+	})
+
+	t.Run("non presence values fail", func(t *testing.T) {
+		tmpl := template.Must(template.New("t").Funcs(presence.TemplateFuncs()).Parse(`{{state .}}`))
+		require.Error(t, tmpl.Execute(&strings.Builder{}, "plain"))
+	})
+
+	t.Run("html/template", func(t *testing.T) {
+		tmpl, err := htmltemplate.New("t").Funcs(presence.TemplateFuncs()).Parse(`<b>{{valueOr .Name "-"}}</b>`)
+		require.NoError(t, err)
+
+		var out strings.Builder
+		require.NoError(t, tmpl.Execute(&out, templateUser{Name: presence.FromValue("<Ada>")}))
+		assert.Equal(t, "<b>&lt;Ada&gt;</b>", out.String())
+	})
+}
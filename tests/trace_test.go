@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Trace
+
+func TestTrace(t *testing.T) {
+	var events []presence.TraceEvent
+	presence.SetTraceHandler(func(e presence.TraceEvent) { events = append(events, e) })
+	defer presence.SetTraceHandler(nil)
+
+	var req struct {
+		Email presence.Of[string] `json:"email"`
+		Name  presence.Of[string] `json:"name"`
+	}
+
+	presence.Trace(&req.Email, "req.Email")
+
+	req.Email.SetValue("a@b.c")
+	req.Name.SetValue("Ada") // not traced
+	require.NoError(t, json.Unmarshal([]byte(`{"email":null}`), &req))
+	req.Email.Unset()
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "SetValue", events[0].Op)
+	assert.Equal(t, presence.StateValue, events[0].State)
+	assert.Equal(t, "a@b.c", events[0].Value)
+	assert.Equal(t, "SetNull", events[1].Op)
+	assert.Equal(t, "Unset", events[2].Op)
+	assert.Equal(t, presence.StateUnset, events[2].State)
+
+	for _, e := range events {
+		assert.Equal(t, "req.Email", e.Label)
+		assert.Contains(t, e.Caller, "tests.TestTrace")
+		assert.Contains(t, e.Caller, "trace_test.go:")
+	}
+
+	presence.Untrace(&req.Email)
+	req.Email.SetNull()
+	assert.Len(t, events, 3)
+}
+
+func TestTraceDefaultHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	var n presence.Of[int]
+	presence.Trace(&n, "n")
+	defer presence.Untrace(&n)
+
+	n.SetNull()
+	assert.Contains(t, buf.String(), "presence trace n: SetNull -> <null> at ")
+}
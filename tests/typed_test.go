@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	userID  int64
+	orderID uuid.UUID
+	slug    string
+)
+
+type typedEntity struct {
+	ID    presence.Of[userID]  `json:"id"`
+	Order presence.Of[orderID] `json:"order"`
+	Slug  presence.Of[slug]    `json:"slug,omitzero"`
+}
+
+// Tests for typed IDs over base types
+
+func TestTypedIDScan(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	t.Run("int64 based", func(t *testing.T) {
+		var n presence.Of[userID]
+		require.NoError(t, n.Scan(int64(42)))
+		assert.Equal(t, userID(42), n.MustGet())
+	})
+
+	t.Run("uuid based from string and bytes", func(t *testing.T) {
+		var n presence.Of[orderID]
+		require.NoError(t, n.Scan(id.String()))
+		assert.Equal(t, orderID(id), n.MustGet())
+
+		require.NoError(t, n.Scan(id[:]))
+		assert.Equal(t, orderID(id), n.MustGet())
+	})
+
+	t.Run("string based", func(t *testing.T) {
+		var n presence.Of[slug]
+		require.NoError(t, n.Scan([]byte("hello")))
+		assert.Equal(t, slug("hello"), n.MustGet())
+	})
+
+	t.Run("null follows the scan null behavior", func(t *testing.T) {
+		var n presence.Of[userID]
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsNull())
+
+		n.SetScanNull(presence.ScanNullAsUnset)
+		require.NoError(t, n.Scan(nil))
+		assert.True(t, n.IsUnset())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		var n presence.Of[orderID]
+		require.Error(t, n.Scan("nope"))
+	})
+}
+
+func TestTypedIDValue(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	v, err := presence.FromValue(userID(42)).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), v)
+
+	v, err = presence.FromValue(orderID(id)).Value()
+	require.NoError(t, err)
+	assert.Equal(t, id, v)
+
+	v, err = presence.FromValue(slug("hello")).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", v)
+
+	t.Run("binary uuid", func(t *testing.T) {
+		presence.SetDefaultUUIDValue(presence.UUIDValueBinary)
+		defer presence.SetDefaultUUIDValue(presence.UUIDValueString)
+
+		v, err := presence.FromValue(orderID(id)).Value()
+		require.NoError(t, err)
+		assert.Equal(t, id[:], v)
+	})
+}
+
+func TestTypedIDJSON(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	entity := typedEntity{
+		ID:    presence.FromValue(userID(7)),
+		Order: presence.FromValue(orderID(id)),
+	}
+
+	data, err := json.Marshal(entity)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":7,"order":"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}`, string(data))
+
+	var decoded typedEntity
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, userID(7), decoded.ID.MustGet())
+	assert.Equal(t, orderID(id), decoded.Order.MustGet())
+	assert.True(t, decoded.Slug.IsUnset())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"order":null}`), &decoded))
+	assert.True(t, decoded.Order.IsNull())
+}
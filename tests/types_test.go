@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedTypesStruct struct {
+	Name      presence.String `json:"name,omitzero"`
+	Count     presence.Int64  `json:"count,omitzero"`
+	CreatedAt presence.Time   `json:"createdAt,omitzero"`
+	Active    presence.Bool   `json:"active,omitzero"`
+}
+
+func TestNamedTypes(t *testing.T) {
+	t.Run("JSON round trip keeps the three states", func(t *testing.T) {
+		in := namedTypesStruct{
+			Name:  presence.NewString("John"),
+			Count: presence.Int64{Of: presence.Null[int64]()},
+		}
+		data, err := json.Marshal(in)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"John","count":null}`, string(data))
+
+		var out namedTypesStruct
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, "John", out.Name.MustGet())
+		assert.True(t, out.Count.IsNull())
+		assert.True(t, out.CreatedAt.IsUnset())
+	})
+
+	t.Run("Scan and Value are promoted", func(t *testing.T) {
+		var s presence.String
+		require.NoError(t, s.Scan("scanned"))
+		assert.Equal(t, "scanned", s.MustGet())
+
+		v, err := presence.NewInt64(7).Value()
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), v)
+	})
+
+	t.Run("String.TrimSpace", func(t *testing.T) {
+		trimmed := presence.NewString("  x \n").TrimSpace()
+		assert.Equal(t, "x", trimmed.MustGet())
+
+		null := presence.String{Of: presence.Null[string]()}.TrimSpace()
+		assert.True(t, null.IsNull())
+	})
+
+	t.Run("String.IsEmptyOrNull", func(t *testing.T) {
+		assert.True(t, presence.NewString("").IsEmptyOrNull())
+		assert.True(t, presence.String{Of: presence.Null[string]()}.IsEmptyOrNull())
+		assert.False(t, presence.NewString("x").IsEmptyOrNull())
+		assert.False(t, presence.String{}.IsEmptyOrNull())
+	})
+
+	t.Run("String.NonEmpty", func(t *testing.T) {
+		empty := presence.NewString("").NonEmpty()
+		assert.True(t, empty.IsNull())
+
+		value := presence.NewString("x").NonEmpty()
+		assert.Equal(t, "x", value.MustGet())
+
+		unset := presence.String{}.NonEmpty()
+		assert.True(t, unset.IsUnset())
+	})
+
+	t.Run("CoalesceNonEmpty", func(t *testing.T) {
+		result := presence.CoalesceNonEmpty(
+			presence.String{},
+			presence.String{Of: presence.Null[string]()},
+			presence.NewString(""),
+			presence.NewString("nick"),
+			presence.NewString("name"),
+		)
+		assert.Equal(t, "nick", result.MustGet())
+
+		none := presence.CoalesceNonEmpty(presence.NewString(""))
+		assert.True(t, none.IsNull())
+	})
+
+	t.Run("Int64.Add", func(t *testing.T) {
+		sum := presence.NewInt64(2).Add(3)
+		assert.Equal(t, int64(5), sum.MustGet())
+
+		unset := presence.Int64{}.Add(3)
+		assert.True(t, unset.IsUnset())
+	})
+
+	t.Run("Time helpers", func(t *testing.T) {
+		ts := presence.NewTime(now)
+		assert.True(t, ts.Before(now.Add(time.Second)))
+		assert.True(t, ts.After(now.Add(-time.Second)))
+		assert.False(t, presence.Time{}.Before(now))
+		utc := presence.NewTime(now.In(time.Local)).UTC()
+		assert.Equal(t, time.UTC, utc.MustGet().Location())
+	})
+
+	t.Run("Bool helpers", func(t *testing.T) {
+		assert.True(t, presence.NewBool(true).IsTrue())
+		assert.True(t, presence.NewBool(false).IsFalse())
+		assert.False(t, presence.Bool{Of: presence.Null[bool]()}.IsTrue())
+		assert.False(t, presence.Bool{}.IsFalse())
+	})
+}
+
+func TestJSON(t *testing.T) {
+	var doc presence.JSON
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "ada",
+		"tags": [{"name": "go"}, {"name": "sql", "aliases": ["pg", null]}],
+		"grid": [[1, 2], [3, 4]],
+		"note": null
+	}`), &doc))
+
+	t.Run("GetPath", func(t *testing.T) {
+		tests := []struct {
+			path string
+			want any
+		}{
+			{"name", "ada"},
+			{"tags[0].name", "go"},
+			{"tags[1].aliases[0]", "pg"},
+			{"tags[1].aliases[1]", nil},
+			{"grid[1][0]", 3.0},
+			{"note", nil},
+		}
+
+		for _, tt := range tests {
+			got, ok := doc.GetPath(tt.path)
+			assert.True(t, ok, tt.path)
+			assert.Equal(t, tt.want, got, tt.path)
+		}
+
+		whole, ok := doc.GetPath("")
+		assert.True(t, ok)
+		assert.Equal(t, doc.MustGet(), whole)
+	})
+
+	t.Run("GetPath misses", func(t *testing.T) {
+		for _, path := range []string{
+			"missing", "tags[2]", "tags.name", "name[0]", "name.first", "grid[0][1][0]",
+			"tags[", "tags[x]", "tags[-1]", "tags[0]x", "a..b", ".name", "tags.[0]",
+		} {
+			_, ok := doc.GetPath(path)
+			assert.False(t, ok, path)
+		}
+
+		_, ok := presence.JSON{}.GetPath("")
+		assert.False(t, ok, "unset")
+
+		_, ok = presence.JSON{Of: presence.Null[any]()}.GetPath("")
+		assert.False(t, ok, "null")
+	})
+
+	t.Run("root arrays", func(t *testing.T) {
+		list := presence.NewJSON([]any{map[string]any{"id": 1.0}})
+		got, ok := list.GetPath("[0].id")
+		assert.True(t, ok)
+		assert.InDelta(t, 1.0, got, 0)
+	})
+
+	t.Run("AsMap and AsSlice", func(t *testing.T) {
+		m, ok := doc.AsMap()
+		assert.True(t, ok)
+		assert.Equal(t, "ada", m["name"])
+
+		_, ok = doc.AsSlice()
+		assert.False(t, ok)
+
+		s, ok := presence.NewJSON([]any{"a"}).AsSlice()
+		assert.True(t, ok)
+		assert.Equal(t, []any{"a"}, s)
+
+		_, ok = presence.JSON{}.AsMap()
+		assert.False(t, ok)
+	})
+
+	t.Run("wraps Of[any]", func(t *testing.T) {
+		var n presence.Of[any]
+		require.NoError(t, n.Scan(`{"a":{"b":true}}`))
+
+		got, ok := presence.JSON{Of: n}.GetPath("a.b")
+		assert.True(t, ok)
+		assert.Equal(t, true, got)
+
+		b, err := json.Marshal(presence.JSON{Of: n})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"a":{"b":true}}`, string(b))
+	})
+}
+
+func TestJSONQuery(t *testing.T) {
+	var doc presence.JSON
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"items": [{"name": "pen", "price": 2}, {"price": 5}, {"name": null}],
+		"meta": {"a.b": "dotted", "0": "zero key"},
+		"note": null
+	}`), &doc))
+
+	t.Run("values", func(t *testing.T) {
+		tests := []struct {
+			path string
+			want any
+		}{
+			{"items.0.name", "pen"},
+			{"items.1.price", 5.0},
+			{"items.#", 3},
+			{"items.#.name", []any{"pen", nil}},
+			{"items.#.missing", []any{}},
+			{`meta.a\.b`, "dotted"},
+			{"meta.0", "zero key"},
+			{"$.items[1].price", 5.0},
+		}
+
+		for _, tt := range tests {
+			got := doc.Query(tt.path)
+			assert.True(t, got.IsValue(), tt.path)
+			assert.Equal(t, tt.want, got.MustGet(), tt.path)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		assert.Equal(t, presence.Null[any](), doc.Query("note"))
+		assert.Equal(t, presence.Null[any](), doc.Query("items.2.name"))
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		for _, path := range []string{"missing", "items.3", "items.x", "items.-1", "note.a", "items.0.name.x", "$.meta[1]"} {
+			assert.Equal(t, presence.Of[any]{}, doc.Query(path), path)
+		}
+
+		assert.Equal(t, presence.Of[any]{}, presence.JSON{}.Query("a"))
+		assert.Equal(t, presence.Of[any]{}, presence.JSON{Of: presence.Null[any]()}.Query("a"))
+	})
+
+	t.Run("whole document", func(t *testing.T) {
+		assert.Equal(t, doc.Of, doc.Query(""))
+		assert.Equal(t, doc.Of, doc.Query("$"))
+		assert.Equal(t, presence.Null[any](), presence.JSON{Of: presence.Null[any]()}.Query(""))
+	})
+}
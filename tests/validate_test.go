@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signupRequest struct {
+	Email    presence.Of[string]   `json:"email"`
+	Name     presence.Of[string]   `json:"name"`
+	Bio      presence.Of[string]   `json:"bio"`
+	Tags     presence.Of[[]string] `json:"tags"`
+	Nickname *string               `json:"nickname"`
+	Age      int                   `json:"age"`
+}
+
+// Tests for ValidateStruct
+
+func TestValidateStruct(t *testing.T) {
+	rules := map[string]presence.Rule{
+		"email":    presence.RequiredValue,
+		"name":     presence.RequiredSet,
+		"bio":      presence.NullableButNotEmpty,
+		"tags":     presence.NullableButNotEmpty,
+		"nickname": presence.RequiredValue,
+		"age":      presence.NullableButNotEmpty,
+	}
+
+	t.Run("valid struct", func(t *testing.T) {
+		nickname := "ada"
+		req := signupRequest{
+			Email:    presence.FromValue("a@b.c"),
+			Name:     presence.Null[string](),
+			Tags:     presence.FromValue([]string{"go"}),
+			Nickname: &nickname,
+			Age:      36,
+		}
+		assert.Nil(t, presence.ValidateStruct(req, rules))
+	})
+
+	t.Run("reports every failing field", func(t *testing.T) {
+		req := signupRequest{
+			Email: presence.Null[string](),
+			Bio:   presence.FromValue(""),
+			Tags:  presence.FromValue([]string{}),
+		}
+
+		errs := presence.ValidateStruct(&req, rules)
+		require.ErrorIs(t, errs["email"], presence.ErrNotNullable)
+		require.ErrorIs(t, errs["name"], presence.ErrRequired)
+		require.ErrorIs(t, errs["bio"], presence.ErrEmpty)
+		require.ErrorIs(t, errs["tags"], presence.ErrEmpty)
+		require.ErrorIs(t, errs["nickname"], presence.ErrNotNullable)
+		require.ErrorIs(t, errs["age"], presence.ErrEmpty)
+
+		b, err := json.Marshal(errs)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors":{
+			"email":"must not be null", "name":"required", "bio":"must not be empty",
+			"tags":"must not be empty", "nickname":"must not be null", "age":"must not be empty"
+		}}`, string(b))
+	})
+
+	t.Run("combined rules", func(t *testing.T) {
+		errShort := errors.New("too short")
+		minLength := func(_ presence.State, v any) error {
+			if s, ok := v.(string); ok && len(s) < 3 {
+				return errShort
+			}
+
+			return nil
+		}
+
+		rule := map[string]presence.Rule{"name": presence.Rules(presence.RequiredValue, minLength)}
+		assert.Equal(t, presence.FieldErrors{"name": presence.ErrRequired},
+			presence.ValidateStruct(signupRequest{}, rule))
+		assert.Equal(t, presence.FieldErrors{"name": errShort},
+			presence.ValidateStruct(signupRequest{Name: presence.FromValue("al")}, rule))
+		assert.Nil(t, presence.ValidateStruct(signupRequest{Name: presence.FromValue("ada")}, rule))
+	})
+
+	t.Run("unknown fields and invalid targets", func(t *testing.T) {
+		errs := presence.ValidateStruct(signupRequest{}, map[string]presence.Rule{"unknown": presence.RequiredSet})
+		require.Error(t, errs["unknown"])
+		assert.Contains(t, errs.Error(), "unknown")
+
+		errs = presence.ValidateStruct(42, rules)
+		require.Error(t, errs[""])
+	})
+
+	t.Run("unwrap", func(t *testing.T) {
+		var err error = presence.FieldErrors{"email": presence.ErrNotNullable, "name": presence.ErrRequired}
+		require.ErrorIs(t, err, presence.ErrRequired)
+		require.ErrorIs(t, err, presence.ErrNotNullable)
+		require.NotErrorIs(t, err, presence.ErrEmpty)
+		assert.Equal(t, "presence field errors : email: must not be null; name: required", err.Error())
+	})
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Var
+
+func TestVar(t *testing.T) {
+	t.Run("loads and stores the three states", func(t *testing.T) {
+		var v presence.Var[int]
+		assert.Equal(t, presence.Of[int]{}, v.Load())
+		assert.Equal(t, "null", v.String())
+
+		v.Store(presence.FromValue(10))
+		assert.Equal(t, presence.FromValue(10), v.Load())
+		assert.Equal(t, "10", v.String())
+
+		v.Store(presence.Null[int]())
+		assert.Equal(t, "null", v.String())
+	})
+
+	t.Run("publishes to expvar", func(t *testing.T) {
+		v := presence.NewVar(presence.FromValue("canary"))
+		expvar.Publish("presence_test_rollout", v)
+		assert.JSONEq(t, `"canary"`, expvar.Get("presence_test_rollout").String())
+	})
+
+	t.Run("watchers receive the latest value", func(t *testing.T) {
+		var v presence.Var[bool]
+		changes, stop := v.Watch()
+
+		v.Store(presence.FromValue(true))
+		v.Store(presence.Null[bool]()) // replaces the value not received yet
+		assert.Equal(t, presence.Null[bool](), <-changes)
+
+		v.Store(presence.FromValue(false))
+		assert.Equal(t, presence.FromValue(false), <-changes)
+
+		stop()
+		stop()
+		_, ok := <-changes
+		assert.False(t, ok)
+
+		v.Store(presence.FromValue(true)) // no watcher left
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		var v presence.Var[int]
+		changes, stop := v.Watch()
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() {
+				v.Store(presence.FromValue(i))
+				_ = v.String()
+			})
+		}
+
+		wg.Wait()
+		last := <-changes
+		stop()
+		require.True(t, last.IsValue())
+		assert.Equal(t, v.Load(), last)
+	})
+}
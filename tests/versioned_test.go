@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pivaldi/presence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type documentPatch struct {
+	Title   presence.Of[string] `db:"title"`
+	Body    presence.Of[string] `db:"body"`
+	Tags    presence.Of[string] `db:"tags"`
+	Version int64               `db:"version" presence:"version"`
+}
+
+// rowsResult is a sql.Result reporting a number of affected rows.
+type rowsResult struct {
+	rows int64
+	err  error
+}
+
+func (r rowsResult) LastInsertId() (int64, error) { return 0, nil }
+func (r rowsResult) RowsAffected() (int64, error) { return r.rows, r.err }
+
+// Tests for VersionedUpdate
+
+func TestNewVersionedUpdate(t *testing.T) {
+	t.Run("splits the patch and the version guard", func(t *testing.T) {
+		patch := documentPatch{Title: presence.FromValue("v2"), Body: presence.Null[string](), Version: 3}
+
+		u, err := presence.NewVersionedUpdate(patch, presence.WithTag("db"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"title": "v2", "body": nil}, u.Set)
+		assert.Equal(t, "version", u.Version)
+		assert.Equal(t, int64(3), u.Expected)
+
+		query, args := u.SQL("documents", "id = ?", 7)
+		assert.Equal(t,
+			"UPDATE documents SET body = ?, title = ?, version = version + 1 WHERE (id = ?) AND version = ?", query)
+		assert.Equal(t, []any{nil, "v2", 7, int64(3)}, args)
+	})
+
+	t.Run("presence version field", func(t *testing.T) {
+		type patch struct {
+			Name    presence.Of[string] `json:"name"`
+			Version presence.Of[int]    `json:"version" presence:"version"`
+		}
+
+		u, err := presence.NewVersionedUpdate(&patch{Version: presence.FromValue(2)})
+		require.NoError(t, err)
+		assert.Empty(t, u.Set)
+		assert.Equal(t, 2, u.Expected)
+
+		_, err = presence.NewVersionedUpdate(patch{Version: presence.Null[int]()})
+		require.Error(t, err)
+	})
+
+	t.Run("requires a version field", func(t *testing.T) {
+		_, err := presence.NewVersionedUpdate(addressPatch{})
+		require.Error(t, err)
+	})
+}
+
+func TestCheckUpdated(t *testing.T) {
+	require.NoError(t, presence.CheckUpdated(rowsResult{rows: 1}, nil))
+	require.ErrorIs(t, presence.CheckUpdated(rowsResult{}, nil), presence.ErrConflict)
+
+	execErr := errors.New("exec failed")
+	require.ErrorIs(t, presence.CheckUpdated(nil, execErr), execErr)
+	require.Error(t, presence.CheckUpdated(rowsResult{err: errors.New("unsupported")}, nil))
+}
+
+func TestParseIfMatch(t *testing.T) {
+	for _, in := range []string{`"3"`, `W/"3"`, ` "3" `, presence.ETag(3)} {
+		version, err := presence.ParseIfMatch(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, int64(3), version, in)
+	}
+
+	for _, in := range []string{``, `*`, `3`, `"a"`, `"3", "4"`} {
+		_, err := presence.ParseIfMatch(in)
+		require.Error(t, err, in)
+	}
+}
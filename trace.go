@@ -0,0 +1,120 @@
+package presence
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// TraceEvent is a state transition of a value traced with Trace.
+type TraceEvent struct {
+	// Label is the label given to Trace.
+	Label string
+	// Op is the transition: "SetValue", "SetNull" or "Unset". Scan and UnmarshalJSON
+	// report the transition they make.
+	Op string
+	// State and Value are those of the value after the transition.
+	State State
+	Value any
+	// Caller is the first caller outside of this package, encoding/json and
+	// database/sql, as "function file:line".
+	Caller string
+}
+
+// String renders the event for logs.
+func (e TraceEvent) String() string {
+	return fmt.Sprintf("presence trace %s: %s -> %s at %s", e.Label, e.Op, debugValue(e.State, e.Value, debugStyle{}),
+		e.Caller)
+}
+
+var (
+	// tracedCount is the number of traced values, so that untraced programs only pay
+	// for an atomic load per transition.
+	tracedCount atomic.Int64
+	// traced maps the traced *Of[T] to their label.
+	traced sync.Map
+	// traceHandler holds the func(TraceEvent) set by SetTraceHandler.
+	traceHandler atomic.Value
+)
+
+// Trace records every transition of the value at n, with its caller, to debug "who
+// cleared this field" issues in large handlers:
+//
+//	presence.Trace(&req.Email, "req.Email")
+//	defer presence.Untrace(&req.Email)
+//	// presence trace req.Email: Unset -> <unset> at main.normalize handler.go:42
+//
+// Events go to the handler set by SetTraceHandler, the standard logger by default.
+// Tracing follows the address of the value: copies of it are not traced, and the value
+// is kept alive until Untrace.
+func Trace[T any](n *Of[T], label string) {
+	if _, loaded := traced.Swap(n, label); !loaded {
+		tracedCount.Add(1)
+	}
+}
+
+// Untrace stops tracing the value at n.
+func Untrace[T any](n *Of[T]) {
+	if _, loaded := traced.LoadAndDelete(n); loaded {
+		tracedCount.Add(-1)
+	}
+}
+
+// SetTraceHandler sets the function receiving the TraceEvents, nil restoring the
+// standard logger.
+func SetTraceHandler(fn func(TraceEvent)) {
+	traceHandler.Store(fn)
+}
+
+// trace reports the transition op of n when it is traced.
+func (n *Of[T]) trace(op string) {
+	if tracedCount.Load() == 0 {
+		return
+	}
+
+	label, ok := traced.Load(n)
+	if !ok {
+		return
+	}
+
+	event := TraceEvent{Label: label.(string), Op: op, State: n.State(), Value: n.anyValue(), Caller: traceCaller()}
+	if fn, _ := traceHandler.Load().(func(TraceEvent)); fn != nil {
+		fn(event)
+
+		return
+	}
+
+	log.Print(event)
+}
+
+// traceCaller returns the first caller outside of the packages making transitions.
+func traceCaller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	for {
+		frame, more := frames.Next()
+		if !isTransitionFrame(frame.Function) {
+			return fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// isTransitionFrame reports whether function belongs to this package, encoding/json
+// (v2 included) or database/sql.
+func isTransitionFrame(function string) bool {
+	for _, pkg := range []string{"github.com/pivaldi/presence.", "encoding/json", "database/sql"} {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
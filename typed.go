@@ -0,0 +1,177 @@
+package presence
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// Typed IDs such as `type UserID int64` or `type OrderID uuid.UUID` neither match the
+// type switches of Scan and Value nor inherit the methods of their underlying type.
+// Unless they implement the interfaces themselves, their values are converted to and
+// from the base type of their kind.
+
+var (
+	uuidType            = reflect.TypeFor[uuid.UUID]()
+	scannerType         = reflect.TypeFor[sql.Scanner]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	baseTypes = map[reflect.Kind]reflect.Type{
+		reflect.String:  reflect.TypeFor[string](),
+		reflect.Int16:   reflect.TypeFor[int16](),
+		reflect.Int32:   reflect.TypeFor[int32](),
+		reflect.Int:     reflect.TypeFor[int](),
+		reflect.Int64:   reflect.TypeFor[int64](),
+		reflect.Float64: reflect.TypeFor[float64](),
+		reflect.Bool:    reflect.TypeFor[bool](),
+	}
+
+	baseScanners = map[reflect.Type]func(v any) (any, bool, error){
+		uuidType:                   scanAs[uuid.UUID],
+		reflect.TypeFor[string]():  scanAs[string],
+		reflect.TypeFor[int16]():   scanAs[int16],
+		reflect.TypeFor[int32]():   scanAs[int32],
+		reflect.TypeFor[int]():     scanAs[int],
+		reflect.TypeFor[int64]():   scanAs[int64],
+		reflect.TypeFor[float64](): scanAs[float64],
+		reflect.TypeFor[bool]():    scanAs[bool],
+	}
+)
+
+// baseType returns the supported base type a defined type converts to.
+// It returns false for the base types themselves and for unnamed types.
+func baseType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Name() == "" {
+		return nil, false
+	}
+
+	if typ.Kind() == reflect.Array {
+		return uuidType, typ != uuidType && typ.ConvertibleTo(uuidType)
+	}
+
+	base, ok := baseTypes[typ.Kind()]
+
+	return base, ok && typ != base
+}
+
+// implements reports whether typ or *typ implements iface.
+func implements(typ, iface reflect.Type) bool {
+	return reflect.PointerTo(typ).Implements(iface)
+}
+
+// jsonFallbackDenied returns ErrUnsupportedType when T is a slice or a map, without a
+// Scanner of its own, and JSONFallbackDisabled is configured.
+func jsonFallbackDenied[T any]() error {
+	if GetDefaultJSONFallback() != JSONFallbackDisabled {
+		return nil
+	}
+
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Map || implements(typ, scannerType) {
+		return nil
+	}
+
+	return fmt.Errorf("%w : %s", ErrUnsupportedType, typ)
+}
+
+// emptyJSON returns the JSON encoding of an empty value of the container type T, {} or
+// [], and nil for the other types and the types with their own JSON encoding.
+func emptyJSON[T any]() []byte {
+	typ := reflect.TypeFor[T]()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if implements(typ, jsonMarshalerType) || implements(typ, textMarshalerType) {
+		return nil
+	}
+
+	kind := typ.Kind()
+	if kind == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 || kind == reflect.Array {
+		return []byte("[]")
+	}
+
+	if kind == reflect.Map || kind == reflect.Struct || kind == reflect.Interface {
+		return []byte("{}")
+	}
+
+	return nil
+}
+
+// isTypedUUID reports whether T is a defined type over uuid.UUID without its own
+// JSON encoding: encoding/json would otherwise encode it as an array of 16 numbers.
+func isTypedUUID[T any]() bool {
+	typ := reflect.TypeFor[T]()
+	if base, ok := baseType(typ); !ok || base != uuidType {
+		return false
+	}
+
+	return !implements(typ, jsonMarshalerType) && !implements(typ, textMarshalerType) &&
+		!implements(typ, jsonUnmarshalerType) && !implements(typ, textUnmarshalerType)
+}
+
+// scanAs scans v as a B, reporting false for NULL.
+func scanAs[B any](v any) (any, bool, error) {
+	var base Of[B]
+	base.SetScanNull(ScanNullAsNull)
+	err := base.Scan(v)
+	val, ok := base.Get()
+
+	return val, ok, err
+}
+
+// scanTyped scans v through the base type of T and converts the result to T.
+// It reports false when T is not a typed ID.
+func (n *Of[T]) scanTyped(v any) (bool, error) {
+	typ := reflect.TypeFor[T]()
+	base, ok := baseType(typ)
+	if !ok || implements(typ, scannerType) {
+		return false, nil
+	}
+
+	val, ok, err := baseScanners[base](v)
+	if err != nil {
+		return true, err
+	}
+
+	if ok {
+		n.setScanned(fromBase[T](val))
+	} else {
+		n.handleScanNull()
+	}
+
+	return true, nil
+}
+
+// typedValue converts a typed ID to its base type.
+func typedValue[T any](v T) (any, bool) {
+	base, ok := baseType(reflect.TypeFor[T]())
+	if !ok {
+		return nil, false
+	}
+
+	return reflect.ValueOf(v).Convert(base).Interface(), true
+}
+
+// fromBase converts a base type value to the typed ID T.
+func fromBase[T any](v any) T {
+	return reflect.ValueOf(v).Convert(reflect.TypeFor[T]()).Interface().(T)
+}
+
+// unmarshalTypedUUID decodes data as a uuid.UUID into the typed ID T.
+func (n *Of[T]) unmarshalTypedUUID(data []byte) error {
+	var id uuid.UUID
+	err := json.Unmarshal(data, &id)
+	if err != nil {
+		return fmt.Errorf("presence Unmarshal Error : %w", err)
+	}
+
+	return n.SetValueChecked(fromBase[T](id))
+}
@@ -0,0 +1,115 @@
+package presence
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"time"
+)
+
+// The named types inherit the methods of Of[T] with the same receivers.
+var (
+	_ driver.Valuer = String{}
+	_ driver.Valuer = (*Int64)(nil)
+	_ sql.Scanner   = (*Time)(nil)
+	_ sql.Scanner   = (*Bool)(nil)
+)
+
+// String is a presence string with string specific helpers.
+// It embeds Of[string] and therefore marshals and scans the same way.
+type String struct{ Of[string] }
+
+// Int64 is a presence int64 with integer specific helpers.
+type Int64 struct{ Of[int64] }
+
+// Time is a presence time.Time with time specific helpers.
+type Time struct{ Of[time.Time] }
+
+// Bool is a presence bool with boolean specific helpers.
+type Bool struct{ Of[bool] }
+
+// NewString is a String constructor from the given value.
+func NewString(v string) String {
+	return String{FromValue(v)}
+}
+
+// NewInt64 is an Int64 constructor from the given value.
+func NewInt64(v int64) Int64 {
+	return Int64{FromValue(v)}
+}
+
+// NewTime is a Time constructor from the given value.
+func NewTime(v time.Time) Time {
+	return Time{FromValue(v)}
+}
+
+// NewBool is a Bool constructor from the given value.
+func NewBool(v bool) Bool {
+	return Bool{FromValue(v)}
+}
+
+// TrimSpace returns a copy with leading and trailing white space removed from the value.
+// Null and unset values are returned unchanged.
+func (s String) TrimSpace() String {
+	return String{Map(s.Of, strings.TrimSpace)}
+}
+
+// IsEmptyOrNull returns true if the value is null or the empty string.
+// Unset values are neither empty nor null and return false.
+func (s String) IsEmptyOrNull() bool {
+	return s.IsNull() || (s.IsValue() && *s.val == "")
+}
+
+// NonEmpty returns a copy where the empty string is turned into null,
+// so that "present but empty" and null are modeled the same way.
+func (s String) NonEmpty() String {
+	if s.IsValue() && *s.val == "" {
+		return String{Null[string]()}
+	}
+
+	return s
+}
+
+// CoalesceNonEmpty returns the first value holding a non-empty string, or null if there is none.
+func CoalesceNonEmpty(values ...String) String {
+	for _, v := range values {
+		if v.IsValue() && *v.val != "" {
+			return v
+		}
+	}
+
+	return String{Null[string]()}
+}
+
+// Add returns a copy with delta added to the value.
+// Null and unset values are returned unchanged.
+func (i Int64) Add(delta int64) Int64 {
+	return Int64{Map(i.Of, func(v int64) int64 { return v + delta })}
+}
+
+// Before reports whether the value is before u. It returns false if null or unset.
+func (t Time) Before(u time.Time) bool {
+	return t.IsValue() && t.val.Before(u)
+}
+
+// After reports whether the value is after u. It returns false if null or unset.
+func (t Time) After(u time.Time) bool {
+	return t.IsValue() && t.val.After(u)
+}
+
+// UTC returns a copy with the value converted to UTC.
+// Null and unset values are returned unchanged.
+func (t Time) UTC() Time {
+	return Time{Map(t.Of, time.Time.UTC)}
+}
+
+// IsTrue returns true iff the value is set to true.
+func (b Bool) IsTrue() bool {
+	return b.IsValue() && *b.val
+}
+
+// IsFalse returns true iff the value is set to false.
+// Null and unset values are neither true nor false.
+func (b Bool) IsFalse() bool {
+	return b.IsValue() && !*b.val
+}
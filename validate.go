@@ -0,0 +1,203 @@
+package presence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// The errors of the predefined rules. Their messages are meant for API clients, hence
+// without the package prefix.
+var (
+	// ErrRequired is reported by RequiredSet and RequiredValue for unset fields.
+	ErrRequired = errors.New("required")
+	// ErrNotNullable is reported by RequiredValue for null fields.
+	ErrNotNullable = errors.New("must not be null")
+	// ErrEmpty is reported by NullableButNotEmpty for empty values.
+	ErrEmpty = errors.New("must not be empty")
+)
+
+// Rule checks a struct field from its state and its value, nil unless StateValue.
+type Rule func(state State, value any) error
+
+// RequiredSet rejects unset fields: the field must be sent, null or not.
+func RequiredSet(state State, _ any) error {
+	if state == StateUnset {
+		return ErrRequired
+	}
+
+	return nil
+}
+
+// RequiredValue rejects unset and null fields.
+func RequiredValue(state State, _ any) error {
+	switch state {
+	case StateUnset:
+		return ErrRequired
+	case StateNull:
+		return ErrNotNullable
+	case StateValue:
+	}
+
+	return nil
+}
+
+// NullableButNotEmpty accepts unset and null fields but rejects empty values: empty
+// strings, slices and maps, and the zero value of other types.
+func NullableButNotEmpty(state State, value any) error {
+	if state != StateValue {
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || rv.IsZero() {
+		return ErrEmpty
+	}
+
+	if k := rv.Kind(); (k == reflect.String || k == reflect.Slice || k == reflect.Map) && rv.Len() == 0 {
+		return ErrEmpty
+	}
+
+	return nil
+}
+
+// Rules combines rules, reporting the error of the first failing one:
+//
+//	"name": presence.Rules(presence.RequiredValue, maxLength(64)),
+func Rules(rules ...Rule) Rule {
+	return func(state State, value any) error {
+		for _, rule := range rules {
+			err := rule(state, value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// FieldErrors maps field names to their error, aggregating the errors of the operations
+// on several fields: ValidateStruct and PatchStruct. It encodes in JSON as the object of
+// the error messages, given by ErrorMessage, under "errors", ready for a 422 response:
+//
+//	{"errors":{"email":"required","name":"must not be empty"}}
+//
+// errors.Is and errors.As match the errors of every field.
+type FieldErrors map[string]error
+
+// Error implements the error interface, listing the field errors by field name.
+func (e FieldErrors) Error() string {
+	var b strings.Builder
+
+	b.WriteString("presence field errors :")
+	for _, name := range slices.Sorted(maps.Keys(e)) {
+		b.WriteString(" " + name + ": " + e[name].Error() + ";")
+	}
+
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// Unwrap returns the errors of the fields, sorted by field name.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, name := range slices.Sorted(maps.Keys(e)) {
+		errs = append(errs, e[name])
+	}
+
+	return errs
+}
+
+// Localize returns the messages of the errors in the language lang, see ErrorMessage.
+func (e FieldErrors) Localize(lang string) map[string]string {
+	messages := make(map[string]string, len(e))
+	for name, err := range e {
+		messages[name] = ErrorMessage(err, lang)
+	}
+
+	return messages
+}
+
+// MarshalJSON implements the encoding json interface, encoding the messages of the
+// errors in the default language of the translator.
+func (e FieldErrors) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(map[string]map[string]string{"errors": e.Localize("")})
+	if err != nil {
+		return nil, fmt.Errorf("presence field errors marshaling : %w", err)
+	}
+
+	return b, nil
+}
+
+// ValidateStruct checks the fields of the struct v named in rules, returning the errors
+// of the failing ones, nil when all pass:
+//
+//	errs := presence.ValidateStruct(req, map[string]presence.Rule{
+//		"email": presence.RequiredValue,
+//		"bio":   presence.NullableButNotEmpty,
+//	})
+//	if errs != nil {
+//		writeJSON(w, http.StatusUnprocessableEntity, errs)
+//	}
+//
+// Plain fields are values, pointers being null when nil. A name matching no field, or
+// a v which is not a struct, is reported as an error of that name, "" for v.
+func ValidateStruct(v any, rules map[string]Rule, opts ...Option) FieldErrors {
+	rv, err := structValue(v)
+	if err != nil {
+		return FieldErrors{"": err}
+	}
+
+	errs := FieldErrors{}
+	seen := make(map[string]bool, len(rules))
+
+	walkFields(rv.Type(), nil, newOptions(opts), func(name string, index []int, isPresence bool) {
+		rule, ok := rules[name]
+		if !ok || seen[name] {
+			return
+		}
+
+		seen[name] = true
+		state, value := fieldState(rv.FieldByIndex(index), isPresence)
+
+		err := rule(state, value)
+		if err != nil {
+			errs[name] = err
+		}
+	})
+
+	for name := range rules {
+		if !seen[name] {
+			errs[name] = fmt.Errorf("presence: no field %s in %s", name, rv.Type())
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// fieldState returns the state and value of the struct field f.
+func fieldState(f reflect.Value, isPresence bool) (State, any) {
+	if isPresence {
+		pf := f.Addr().Interface().(presenceField)
+
+		return pf.State(), pf.anyValue()
+	}
+
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return StateNull, nil
+		}
+
+		f = f.Elem()
+	}
+
+	return StateValue, f.Interface()
+}
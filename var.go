@@ -0,0 +1,95 @@
+package presence
+
+import (
+	"expvar"
+	"slices"
+	"sync"
+)
+
+var _ expvar.Var = (*Var[int])(nil)
+
+// Var is a presence value shared by goroutines, such as a runtime toggle or a feature
+// flag: unset falls back to the default behavior, null disables and a value enables.
+// It implements expvar.Var, so that it can be published, and notifies its watchers of
+// the changes:
+//
+//	var rateLimit presence.Var[int]
+//	expvar.Publish("rate_limit", &rateLimit)
+//
+//	changes, stop := rateLimit.Watch()
+//	defer stop()
+//	for limit := range changes {
+//		limiter.SetLimit(limit.GetOr(defaultLimit))
+//	}
+//
+// The zero Var is unset and ready to use. A Var must not be copied after first use.
+type Var[T any] struct {
+	mu       sync.RWMutex
+	value    Of[T]
+	watchers []chan Of[T]
+}
+
+// NewVar returns a Var holding n.
+func NewVar[T any](n Of[T]) *Var[T] {
+	return &Var[T]{value: n}
+}
+
+// Load returns the value of v.
+func (v *Var[T]) Load() Of[T] {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.value
+}
+
+// Store sets the value of v and notifies the watchers.
+func (v *Var[T]) Store(n Of[T]) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.value = n
+	for _, ch := range v.watchers {
+		// Watchers only need the latest value: replace the one not received yet.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- n
+	}
+}
+
+// Watch returns a channel receiving the values stored from now on, and the function
+// stopping the watch, which closes the channel. A slow watcher misses intermediate
+// values but always receives the latest one.
+func (v *Var[T]) Watch() (<-chan Of[T], func()) {
+	ch := make(chan Of[T], 1)
+
+	v.mu.Lock()
+	v.watchers = append(v.watchers, ch)
+	v.mu.Unlock()
+
+	var once sync.Once
+
+	return ch, func() {
+		once.Do(func() {
+			v.mu.Lock()
+			defer v.mu.Unlock()
+
+			v.watchers = slices.DeleteFunc(v.watchers, func(c chan Of[T]) bool { return c == ch })
+			close(ch)
+		})
+	}
+}
+
+// String implements expvar.Var, returning the JSON encoding of the value, null when it
+// is null or unset.
+func (v *Var[T]) String() string {
+	n := v.Load()
+
+	b, err := n.MarshalJSON()
+	if err != nil || n.IsUnset() {
+		return "null"
+	}
+
+	return string(b)
+}
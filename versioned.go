@@ -0,0 +1,165 @@
+package presence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrConflict is returned when a versioned update matches no row: the row changed, or
+// was deleted, since the client read it.
+var ErrConflict = errors.New("presence: conflict, the row changed since it was read")
+
+// VersionedUpdate is the update of a row from a presence patch, guarded by a version
+// column for optimistic locking. The version field of the patch, tagged
+// `presence:"version"`, holds the version the client read, e.g. from If-Match:
+//
+//	type UserPatch struct {
+//		Name    presence.Of[string] `db:"name"`
+//		Version int64               `db:"version" presence:"version"`
+//	}
+//
+// The version column must be an integer, incremented by the update.
+type VersionedUpdate struct {
+	// Set holds the set presence fields of the patch, the version field excepted.
+	Set map[string]any
+	// Version is the name of the version column.
+	Version string
+	// Expected is the version the row must have.
+	Expected any
+}
+
+// NewVersionedUpdate returns the update of the struct patch, whose fields are named
+// according to the options. Fields tagged presence:"apionly" are left out of Set, like by
+// ToMap.
+func NewVersionedUpdate(patch any, opts ...Option) (VersionedUpdate, error) {
+	o := newOptions(opts)
+	rv, err := structValue(patch)
+	if err != nil {
+		return VersionedUpdate{}, err
+	}
+
+	var u VersionedUpdate
+	var expected bool
+
+	walkFields(rv.Type(), nil, o, func(name string, index []int, isPresence bool) {
+		if !hasPresenceOption(rv.Type().FieldByIndex(index), "version") {
+			return
+		}
+
+		u.Version, u.Expected, expected = name, rv.FieldByIndex(index).Interface(), true
+		if isPresence {
+			pf := rv.FieldByIndex(index).Addr().Interface().(presenceField)
+			u.Expected, expected = pf.anyValue(), pf.State() == StateValue
+		}
+	})
+
+	if u.Version == "" {
+		return VersionedUpdate{}, fmt.Errorf("presence versioned update : %s has no field tagged presence:\"version\"",
+			rv.Type())
+	}
+
+	if !expected {
+		return VersionedUpdate{}, fmt.Errorf("presence versioned update : version field %s has no value", u.Version)
+	}
+
+	u.Set = map[string]any{}
+	for _, f := range presenceFields(rv.Type(), o) {
+		pf := fieldOf(rv, f)
+		if f.name != u.Version && pf.State() != StateUnset &&
+			!hasPresenceOption(rv.Type().FieldByIndex(f.index), "apionly") {
+			u.Set[f.name] = pf.anyValue()
+		}
+	}
+
+	return u, nil
+}
+
+// hasPresenceOption reports whether the presence tag of f holds option, e.g.
+// presence:"version".
+func hasPresenceOption(f reflect.StructField, option string) bool {
+	for o := range strings.SplitSeq(f.Tag.Get("presence"), ",") {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SQL returns the UPDATE statement of table, with ? placeholders (rebind them for
+// PostgreSQL, e.g. with sqlx's Rebind). where selects the row, its args following the
+// SET ones, and is completed by the version guard:
+//
+//	query, args := u.SQL("users", "id = ?", id)
+//	res, err := db.ExecContext(ctx, db.Rebind(query), args...)
+//	err = presence.CheckUpdated(res, err)
+//	// UPDATE users SET name = ?, version = version + 1 WHERE (id = ?) AND version = ?
+//
+// The table, the column names and where are written as they are: they must be trusted,
+// the identifiers being quoted beforehand when they need to be.
+func (u VersionedUpdate) SQL(table, where string, whereArgs ...any) (string, []any) {
+	columns := slices.Sorted(maps.Keys(u.Set))
+
+	var b strings.Builder
+	args := make([]any, 0, len(columns)+len(whereArgs)+1)
+
+	b.WriteString("UPDATE " + table + " SET ")
+	for _, column := range columns {
+		b.WriteString(column + " = ?, ")
+		args = append(args, u.Set[column])
+	}
+
+	b.WriteString(u.Version + " = " + u.Version + " + 1 WHERE (" + where + ") AND " + u.Version + " = ?")
+	args = append(args, whereArgs...)
+	args = append(args, u.Expected)
+
+	return b.String(), args
+}
+
+// CheckUpdated returns err if not nil, and ErrConflict when res reports that no row
+// was updated.
+func CheckUpdated(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("presence checking updated rows : %w", err)
+	}
+
+	if n == 0 {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// ParseIfMatch returns the version held by an If-Match header value produced by ETag:
+// "3" or W/"3".
+func ParseIfMatch(value string) (int64, error) {
+	tag := strings.TrimPrefix(strings.TrimSpace(value), "W/")
+
+	unquoted, err := strconv.Unquote(tag)
+	if err == nil {
+		var version int64
+
+		version, err = strconv.ParseInt(unquoted, 10, 64)
+		if err == nil {
+			return version, nil
+		}
+	}
+
+	return 0, fmt.Errorf("presence parsing If-Match %q : not a version entity tag", value)
+}
+
+// ETag returns the entity tag of version, "3" for 3, for the ETag response header.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}